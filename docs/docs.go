@@ -15,26 +15,40 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/delete": {
-            "delete": {
+        "/admin/earning_adjustment": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete a user from the database",
+                "description": "Books a manual adjustment (positive or negative) or a penalty (given as a positive amount) for a courier",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User methods"
+                    "Earnings"
+                ],
+                "summary": "AdjustEarnings",
+                "parameters": [
+                    {
+                        "description": "Adjustment",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.EarningAdjustmentRequest"
+                        }
+                    }
                 ],
-                "summary": "Delete",
                 "responses": {
-                    "200": {
-                        "description": "All users in system",
+                    "201": {
+                        "description": "Booked earning",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/model.Earning"
                         }
                     },
                     "400": {
@@ -49,6 +63,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -58,60 +78,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/getall": {
-            "get": {
+        "/admin/hub": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "GetAll function returns list of all users in database(test function)",
+                "description": "Adds an active hub where parcels of multi-leg deliveries change couriers",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "temporary methods"
+                    "Hubs"
                 ],
-                "summary": "GetAll",
-                "responses": {
-                    "200": {
-                        "description": "All users in system",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.User"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Error message",
+                "summary": "CreateHub",
+                "parameters": [
+                    {
+                        "description": "Hub",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/model.Hub"
                         }
                     }
-                }
-            }
-        },
-        "/auth/getpersonalinfo": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Fetch personal information of the active user based on the access token provided in the Authorization header.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "User methods"
                 ],
-                "summary": "Get Personal Info",
                 "responses": {
-                    "200": {
-                        "description": "User's personal information",
+                    "201": {
+                        "description": "Created hub",
                         "schema": {
-                            "$ref": "#/definitions/model.User"
+                            "$ref": "#/definitions/model.Hub"
                         }
                     },
                     "400": {
@@ -126,6 +126,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "409": {
+                        "description": "Hub code is taken",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -135,9 +141,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login": {
-            "post": {
-                "description": "Logs in a user and returns access and refresh tokens",
+        "/admin/hub_staff": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Assigns a user with the HubStaff role to the hub they scan parcels at, replacing their previous hub",
                 "consumes": [
                     "application/json"
                 ],
@@ -145,30 +156,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication methods"
+                    "Hubs"
                 ],
-                "summary": "Login",
+                "summary": "SetHubStaff",
                 "parameters": [
                     {
-                        "description": "Login details",
+                        "description": "Hub staff",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Login"
+                            "$ref": "#/definitions/model.HubStaff"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": " Generating access and refresh tokens",
+                        "description": "Staff has been assigned",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Error message",
+                        "description": "Hub not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "string"
                         }
@@ -176,42 +204,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/signup": {
-            "post": {
-                "description": "Creates a new user in the system",
-                "consumes": [
-                    "application/json"
+        "/admin/hubs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Returns every hub including inactive ones",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication methods"
-                ],
-                "summary": "SignUp",
-                "parameters": [
-                    {
-                        "description": "Sign up details",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.SignUp"
-                        }
-                    }
+                    "Hubs"
                 ],
+                "summary": "GetHubs",
                 "responses": {
                     "200": {
-                        "description": "User has been registered successfully",
+                        "description": "Hubs",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Hub"
+                            }
                         }
                     },
                     "401": {
@@ -229,40 +244,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/courier/choose_availible_delivery": {
-            "patch": {
+        "/admin/statements": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "allows the courier to choose delivery",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the weekly or monthly statements of all couriers, newest period first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Courier Bussiness logic"
+                    "Earnings"
                 ],
-                "summary": "ChooseAvailibleDelivery",
+                "summary": "GetStatements",
                 "parameters": [
                     {
-                        "description": "Delivery to create",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.DeliveryId"
-                        }
+                        "type": "string",
+                        "description": "week (default) or month",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only periods starting after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only periods starting before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Delivery has been sucessfully choosed by courier",
+                        "description": "Statements",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Statement"
+                            }
                         }
                     },
                     "400": {
@@ -286,29 +310,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/courier/getalldeliveries": {
+        "/admin/statements_csv": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "GetAlldeliveries",
+                "description": "Returns the weekly or monthly statements of all couriers as a CSV file for payouts",
                 "produces": [
-                    "application/json"
+                    "text/csv"
                 ],
                 "tags": [
-                    "Courier Bussiness logic"
+                    "Earnings"
+                ],
+                "summary": "ExportStatements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "week (default) or month",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only periods starting after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only periods starting before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
                 ],
-                "summary": "GetAlldeliveries",
                 "responses": {
                     "200": {
-                        "description": "Delivery has been sucessfully created",
+                        "description": "Statements",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.DeliveryGet"
-                            }
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -332,14 +373,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/courier/update_delivery_status": {
-            "patch": {
+        "/admin/zone": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "allows the courier to update delivery status",
+                "description": "Adds an active service zone bounded by a GeoJSON Polygon or MultiPolygon ([lon, lat] positions).\nDeliveries picked up inside the zone belong to it, are priced with the tariff of the zone code\nand can only be created while the zone is open",
                 "consumes": [
                     "application/json"
                 ],
@@ -347,25 +388,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Courier Bussiness logic"
+                    "Zones"
                 ],
-                "summary": "UpdateDeliveryStatus",
+                "summary": "CreateZone",
                 "parameters": [
                     {
-                        "description": "Delivery status to update",
+                        "description": "Zone",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.DeliveryStatus"
+                            "$ref": "#/definitions/model.Zone"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Delivery status has been sucessfully updated by courier",
+                    "201": {
+                        "description": "Created zone",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/model.Zone"
                         }
                     },
                     "400": {
@@ -380,6 +421,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "409": {
+                        "description": "Zone code is taken",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -389,14 +436,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/courier/updatecourier": {
-            "patch": {
+        "/admin/zone/{id}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates Courier information",
+                "description": "Replaces the code, name, boundary, hours and active flag of a zone, existing deliveries keep their zone",
                 "consumes": [
                     "application/json"
                 ],
@@ -404,26 +451,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Courier Bussiness logic"
+                    "Zones"
                 ],
-                "summary": "UpdateCourier",
+                "summary": "UpdateZone",
                 "parameters": [
                     {
-                        "description": "Courier to update",
+                        "type": "string",
+                        "description": "Zone id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Zone",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Courier"
+                            "$ref": "#/definitions/model.Zone"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Courier info has been sucessfully updated",
+                        "description": "Updated zone",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Zone"
                         }
                     },
                     "400": {
@@ -438,6 +491,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Zone not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Zone code is taken",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -447,41 +512,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/delivery/create_delivary": {
-            "post": {
+        "/admin/zone_lookup": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Creates a new delivery instance",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns the active zone containing the point, the smallest one when zones overlap",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Courier Bussiness logic"
+                    "Zones"
                 ],
-                "summary": "CreateDelivery",
+                "summary": "LocateZone",
                 "parameters": [
                     {
-                        "description": "Delivery to create",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.Delivery"
-                        }
+                        "type": "number",
+                        "description": "Latitude",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude",
+                        "name": "lon",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Delivery has been sucessfully created",
+                        "description": "Zone",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Zone"
                         }
                     },
                     "400": {
@@ -496,6 +562,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Point is outside every zone",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -504,95 +576,6446 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.Courier": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "string"
-                },
-                "name": {
+        },
+        "/admin/zones": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every service zone including inactive ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Zones"
+                ],
+                "summary": "GetZones",
+                "responses": {
+                    "200": {
+                        "description": "Zones",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Zone"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/delete": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a user from the database",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User methods"
+                ],
+                "summary": "Delete",
+                "responses": {
+                    "200": {
+                        "description": "All users in system",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/getall": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "GetAll function returns list of all users in database(test function)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "temporary methods"
+                ],
+                "summary": "GetAll",
+                "responses": {
+                    "200": {
+                        "description": "All users in system",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.User"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/getpersonalinfo": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetch personal information of the active user based on the access token provided in the Authorization header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User methods"
+                ],
+                "summary": "Get Personal Info",
+                "responses": {
+                    "200": {
+                        "description": "User's personal information",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Logs in a user and returns access and refresh tokens",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication methods"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "description": "Login details",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Login"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": " Generating access and refresh tokens",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Error message",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/signup": {
+            "post": {
+                "description": "Creates a new user in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication methods"
+                ],
+                "summary": "SignUp",
+                "parameters": [
+                    {
+                        "description": "Sign up details",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SignUp"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User has been registered successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/client/cancel_delivery": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancels a delivery owned by the authorized client, only possible before pickup.\nReasons: no_longer_needed, wrong_details, duplicate, too_late, operational, other (needs a comment).\nA fee may apply once a courier is assigned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client methods"
+                ],
+                "summary": "CancelDelivery",
+                "parameters": [
+                    {
+                        "description": "Delivery to cancel and reason",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CancelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cancellation with the fee charged",
+                        "schema": {
+                            "$ref": "#/definitions/model.Cancellation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is already picked up",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/client/create_delivery": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Places a new delivery order on behalf of the authorized client.\nThe response carries the recipient PIN, it is not shown on the tracking page and must be passed to the recipient",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client methods"
+                ],
+                "summary": "CreateDelivery",
+                "parameters": [
+                    {
+                        "description": "Delivery to order",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Delivery"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Delivery has been sucessfully ordered",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/client/getdelivery/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns live status of a delivery owned by the authorized client",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client methods"
+                ],
+                "summary": "GetDelivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryGet"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/client/getmydeliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns all deliveries ordered by the authorized client, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client methods"
+                ],
+                "summary": "GetMyDeliveries",
+                "responses": {
+                    "200": {
+                        "description": "Client's deliveries",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DeliveryGet"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/client/rate_delivery": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Rates the courier of a delivered delivery from 1 to 5 with optional tags and comment.\nA delivery is rated once and only within the rating period after delivery",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client methods"
+                ],
+                "summary": "RateDelivery",
+                "parameters": [
+                    {
+                        "description": "Rating",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RatingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Stored rating",
+                        "schema": {
+                            "$ref": "#/definitions/model.Rating"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is not delivered, already rated or the rating period has ended",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/client/reissue_tracking_code": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revokes the current tracking code of a delivery owned by the authorized client and issues a new one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client methods"
+                ],
+                "summary": "ReissueTrackingCode",
+                "parameters": [
+                    {
+                        "description": "Delivery",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryId"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New tracking code",
+                        "schema": {
+                            "$ref": "#/definitions/model.TrackingCode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/accept_offer": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts a pending offer, the delivery is assigned to the authorized courier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "AcceptOffer",
+                "parameters": [
+                    {
+                        "description": "Offer",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.OfferId"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Offer has been accepted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Offer expired or delivery is already taken",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/cash": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the cash the authorized courier holds and their ledger entries of the period",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "GetMyCash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only entries created after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries created before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cash ledger",
+                        "schema": {
+                            "$ref": "#/definitions/model.CashLedger"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/choose_availible_delivery": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "allows the courier to choose delivery",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "ChooseAvailibleDelivery",
+                "parameters": [
+                    {
+                        "description": "Delivery to create",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryId"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery has been sucessfully choosed by courier",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Delivery is outside the courier's home zones",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Courier is offline, off shift, at capacity or delivery is taken",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/claim_leg": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Assigns a free leg to the authorized active courier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "ClaimLeg",
+                "parameters": [
+                    {
+                        "description": "Leg",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LegId"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Claimed leg",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryLeg"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Leg not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Leg is already taken or courier is not active",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/decline_offer": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Declines a pending offer, the delivery is offered to the next courier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "DeclineOffer",
+                "parameters": [
+                    {
+                        "description": "Offer",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.OfferId"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Offer has been declined",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Offer is not pending",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/deliver": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Marks a picked up delivery of the courier delivered. Depending on configuration a photo, a signature image\nand/or the recipient's one-time PIN are required, the PIN only for deliveries it was issued for. Capture time and GPS are stored with each proof",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "CompleteDelivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery id",
+                        "name": "delivery_id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "PIN given by the recipient",
+                        "name": "pin",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Capture time, RFC 3339, defaults to now",
+                        "name": "captured_at",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude where the proof was captured",
+                        "name": "lat",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude where the proof was captured",
+                        "name": "lon",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Cash taken from the recipient, required for cash on delivery. The courier owes the COD amount, a smaller amount is reported as a shortfall",
+                        "name": "collected_amount",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Photo of the handed over parcel",
+                        "name": "photo",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Recipient signature image",
+                        "name": "signature",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recorded proofs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DeliveryProof"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or missing proof",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is not picked up or the PIN is locked, managers reissue locked PINs",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/delivery/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a delivery assigned to the authorized courier with its items and totals",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "GetDelivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery with items",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryGet"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found or not assigned to the courier",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/failed_attempt": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Records a failed attempt with a reason (recipient_absent, address_not_found, access_denied, refused,\nunsafe_location, other). The delivery stays with the courier and is rescheduled into its next window, or returned to the sender\non a new delivery leg carried by the same courier once the maximum number of attempts is reached.\nThe sender follows the return leg with the tracking code of the original delivery",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "FailAttempt",
+                "parameters": [
+                    {
+                        "description": "Failed attempt",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.FailedAttempt"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "What happens to the delivery next",
+                        "schema": {
+                            "$ref": "#/definitions/model.AttemptOutcome"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is not picked up",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/getalldeliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists deliveries, couriers with home zones only see deliveries of those zones and deliveries without a zone.\nThe recipient is left empty, the assigned courier reads it from /courier/delivery/{id}",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "GetAlldeliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only deliveries whose window ends after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only deliveries whose window starts before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery has been sucessfully created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DeliveryGet"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/go_offline": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Switches the authorized courier from active to off_shift, assigned deliveries stay with the courier",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "GoOffline",
+                "responses": {
+                    "200": {
+                        "description": "Courier is offline",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Courier can not change status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/go_online": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Switches the authorized courier from off_shift to active, allowed only during a planned shift",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "GoOnline",
+                "responses": {
+                    "200": {
+                        "description": "Courier is online",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Courier is not on shift or can not change status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/legs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns unfinished legs assigned to the authorized courier and free legs they can claim",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "GetMyLegs",
+                "responses": {
+                    "200": {
+                        "description": "Legs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DeliveryLeg"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/locations": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stores a batch of up to 500 GPS fixes, invalid fixes are rejected and duplicates ignored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "PostLocations",
+                "parameters": [
+                    {
+                        "description": "GPS fixes",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LocationBatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Accepted and rejected fixes",
+                        "schema": {
+                            "$ref": "#/definitions/model.LocationBatchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/offers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns delivery offers waiting for the authorized courier's answer",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "GetOffers",
+                "responses": {
+                    "200": {
+                        "description": "Pending offers",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.OfferView"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/performance": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the performance indicator of the authorized courier with on-time, failed, acceptance and rating components",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "GetMyPerformance",
+                "responses": {
+                    "200": {
+                        "description": "Performance breakdown",
+                        "schema": {
+                            "$ref": "#/definitions/model.PerformanceBreakdown"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not calculated yet",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/pickup_leg": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Marks the first leg of the authorized courier picked up at the pickup address,\nparcels are handed out at hubs with /hub/check_out",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "PickUpLeg",
+                "parameters": [
+                    {
+                        "description": "Leg",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LegId"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Picked up leg",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryLeg"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Leg not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Leg is not the courier's first leg or already picked up",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/route": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Plans pickups and drop-offs of the authorized courier's active deliveries, pickups come before their drop-offs and delivery windows are kept where possible",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "GetMyRoute",
+                "responses": {
+                    "200": {
+                        "description": "Planned route",
+                        "schema": {
+                            "$ref": "#/definitions/model.RoutePlan"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/shifts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns current and upcoming shifts of the authorized courier for the next two weeks",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "GetMyShifts",
+                "responses": {
+                    "200": {
+                        "description": "Shifts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CourierShift"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/statement/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a statement of the authorized courier with the earnings it sums up",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "GetMyStatement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Statement id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Statement with entries",
+                        "schema": {
+                            "$ref": "#/definitions/model.Statement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Statement not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/statements": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the weekly or monthly earnings statements of the authorized courier, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "GetMyStatements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "week (default) or month",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only periods starting after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only periods starting before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Statements",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Statement"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/update_delivery_status": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lets the courier mark their delivery picked_up. Delivered is set through /courier/deliver\nand failed attempts are reported through /courier/failed_attempt",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "UpdateDeliveryStatus",
+                "parameters": [
+                    {
+                        "description": "Delivery status to update",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryStatus"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery status has been sucessfully updated by courier",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Couriers can not cancel deliveries",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery is not assigned to the courier",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is already picked up or closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/courier/updatecourier": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates name and surname of the authorized courier, status changes must be valid transitions, omitted fields are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "UpdateCourier",
+                "parameters": [
+                    {
+                        "description": "Fields to update",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CourierPatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated courier",
+                        "schema": {
+                            "$ref": "#/definitions/model.Courier"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Status transition is not allowed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/create_delivary": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a new delivery instance. The response carries the recipient PIN, it is not shown on the tracking page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Courier Bussiness logic"
+                ],
+                "summary": "CreateDelivery",
+                "parameters": [
+                    {
+                        "description": "Delivery to create",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Delivery"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery has been sucessfully created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates deliveries from a CSV or XLSX file (first sheet), one delivery per row below a header row.\nColumns: pickup_address_line1, pickup_address_line2, pickup_city, pickup_postcode, pickup_lat, pickup_lon,\nthe same for dropoff, recipient_name, recipient_phone, access_notes, window_start, window_end (RFC 3339 or\nYYYY-MM-DD HH:MM in UTC), weight_kg, express, cod_amount, delivery_comment, promo_code and client_id, which\ndefaults to the client_id of the form. Every row is validated and priced, the report lists problems per row\nand column, including rows past the uses left of their promo code. Valid rows are inserted together and\nrows with problems are skipped, with dry_run nothing is saved. The report gives the id, tracking code and\nrecipient PIN of every inserted delivery, PINs are not shown on the tracking page",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import"
+                ],
+                "summary": "ImportDeliveries",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or XLSX file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv or xlsx, defaults to the file extension",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client of the rows without a client_id",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate and price the rows",
+                        "name": "dry_run",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run or nothing inserted",
+                        "schema": {
+                            "$ref": "#/definitions/model.ImportReport"
+                        }
+                    },
+                    "201": {
+                        "description": "Valid rows inserted",
+                        "schema": {
+                            "$ref": "#/definitions/model.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, unreadable file or header, unknown client",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "A promo code was used up while importing",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/promo": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a promo code with either a percentage or a fixed discount, codes are stored in upper case",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "CreatePromo",
+                "parameters": [
+                    {
+                        "description": "Promo code, used is ignored",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Promo"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Stored promo code",
+                        "schema": {
+                            "$ref": "#/definitions/model.Promo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Code already exists",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/quote": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Prices a delivery with the current tariff of the service zone of its pickup point, or of its drop-off city\noutside every zone. The promo code is checked but not used up",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Quote",
+                "parameters": [
+                    {
+                        "description": "Delivery to price",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.QuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Price with its components",
+                        "schema": {
+                            "$ref": "#/definitions/model.Quote"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "No tariff for the zone or the zone is closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/reissue_tracking_code": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revokes the current tracking code of a delivery and issues a new one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tracking"
+                ],
+                "summary": "ReissueCode",
+                "parameters": [
+                    {
+                        "description": "Delivery",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryId"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New tracking code",
+                        "schema": {
+                            "$ref": "#/definitions/model.TrackingCode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/revoke_tracking_code": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revokes every tracking code of a delivery",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tracking"
+                ],
+                "summary": "RevokeCodes",
+                "parameters": [
+                    {
+                        "description": "Delivery",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryId"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tracking codes have been revoked",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/tariff": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds the next version of a zone's tariff, it applies from valid_from or immediately; zone \"*\" is the default tariff",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "CreateTariff",
+                "parameters": [
+                    {
+                        "description": "Tariff, id, version and created_at are ignored",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Tariff"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Stored tariff version",
+                        "schema": {
+                            "$ref": "#/definitions/model.Tariff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Another version was added meanwhile",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/tariffs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns all versions of the zone's tariff, or of every zone when zone is omitted, newest version first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "GetTariffs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone",
+                        "name": "zone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tariff versions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Tariff"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Streams status changes, assignments and courier positions of the deliveries the caller may see: managers and admins see all, clients their own orders and couriers their assigned deliveries. Clients that can not set headers pass a ticket from /events/ticket in the ticket query parameter instead",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "StreamEvents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ticket for clients that can not set headers",
+                        "name": "ticket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of events",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/ticket": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a ticket that opens the event stream of the caller in the ticket query parameter, for clients such as browser EventSource that can not set headers. The ticket expires after a minute and is not accepted as an access token",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "IssueTicket",
+                "responses": {
+                    "200": {
+                        "description": "Ticket",
+                        "schema": {
+                            "$ref": "#/definitions/model.EventTicket"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/hub/check_in": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Completes the picked up leg ending at the staff's hub, the parcel then waits for the next leg",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Hubs"
+                ],
+                "summary": "CheckIn",
+                "parameters": [
+                    {
+                        "description": "Delivery",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryId"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Completed leg",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryLeg"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not assigned to a hub",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Parcel is not on its way to the hub",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/hub/check_out": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Hands a parcel waiting at the staff's hub to the courier of the next leg, the leg is picked up",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Hubs"
+                ],
+                "summary": "CheckOut",
+                "parameters": [
+                    {
+                        "description": "Delivery",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryId"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Picked up leg",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryLeg"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not assigned to a hub",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Parcel is not at the hub or the next leg has no courier",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/hub/inventory": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the next legs of parcels checked in at the staff's hub and not checked out yet",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Hubs"
+                ],
+                "summary": "GetHubInventory",
+                "responses": {
+                    "200": {
+                        "description": "Waiting legs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DeliveryLeg"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not assigned to a hub",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/assign_delivery": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Assigns a delivery that is not picked up yet to the courier with the given user id, replacing the current courier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "AssignDelivery",
+                "parameters": [
+                    {
+                        "description": "Assignment",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryAssignment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery has been assigned",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is picked up or closed, or courier is suspended",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/assign_leg": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Assigns or reassigns a leg that has not been picked up yet to a courier that is not suspended",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "AssignLeg",
+                "parameters": [
+                    {
+                        "description": "Assignment",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LegAssignment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Assigned leg",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryLeg"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Leg or courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Leg is picked up or courier is suspended",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/cancel_delivery": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancels an open delivery and unassigns its courier, who is notified through the event stream.\nThe fee for the current state (created, assigned, picked_up) is charged unless waive_fee is set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "CancelDelivery",
+                "parameters": [
+                    {
+                        "description": "Delivery to cancel and reason",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ManagerCancelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cancellation with the fee charged",
+                        "schema": {
+                            "$ref": "#/definitions/model.Cancellation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is closed or has changed meanwhile",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/cash_handover": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Records the cash counted at shift end against the courier's balance, the discrepancy is kept with the handover",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "RecordHandover",
+                "parameters": [
+                    {
+                        "description": "Counted cash",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CashHandoverRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Recorded handover",
+                        "schema": {
+                            "$ref": "#/definitions/model.CashHandover"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Cash was collected meanwhile",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/cash_handovers/{userid}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the cash handovers of the courier with their discrepancies, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetCashHandovers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Courier user id",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Handovers",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CashHandover"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/cash_outstanding": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns couriers whose cash balance is not settled with their collections, the lifetime total of their\nhandover discrepancies and the COD amount they reported as not collected",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetOutstandingCash",
+                "responses": {
+                    "200": {
+                        "description": "Outstanding cash per courier",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CourierCash"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/courier": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates name, surname and status of the courier with the given user id, omitted fields are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "UpdateCourier",
+                "parameters": [
+                    {
+                        "description": "Fields to update",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CourierManagerPatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated courier",
+                        "schema": {
+                            "$ref": "#/definitions/model.Courier"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Courier has changed meanwhile",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/courier_capacity": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets the maximum number of concurrent deliveries and the optional vehicle weight limit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "SetCourierCapacity",
+                "parameters": [
+                    {
+                        "description": "Courier capacity",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CourierCapacityUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Courier capacity has been changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/courier_cash/{userid}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the cash the courier holds and their ledger entries of the period",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetCourierCash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Courier user id",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries created after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries created before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cash ledger",
+                        "schema": {
+                            "$ref": "#/definitions/model.CashLedger"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/courier_performance/{userid}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the performance breakdown of the courier with the given user id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetCourierPerformance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Courier user id",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Performance breakdown",
+                        "schema": {
+                            "$ref": "#/definitions/model.PerformanceBreakdown"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/courier_positions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the last known position of every courier who has sent one, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetCourierPositions",
+                "responses": {
+                    "200": {
+                        "description": "Courier positions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CourierPosition"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/courier_ratings/{userid}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the count, average, score distribution and tag counts of the courier's published ratings\ntogether with their latest ratings including hidden ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetCourierRatings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Courier user id",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rating aggregates",
+                        "schema": {
+                            "$ref": "#/definitions/model.CourierRatings"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/courier_route/{userid}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Plans the route of the courier with the given user id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "PreviewCourierRoute",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Courier user id",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Planned route",
+                        "schema": {
+                            "$ref": "#/definitions/model.RoutePlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/courier_shift": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Plans a working period for the courier with the given user id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "PlanShift",
+                "parameters": [
+                    {
+                        "description": "Shift",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CourierShiftCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Planned shift",
+                        "schema": {
+                            "$ref": "#/definitions/model.CourierShift"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a planned shift",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "CancelShift",
+                "parameters": [
+                    {
+                        "description": "Shift",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ShiftId"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shift has been cancelled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/courier_status": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets courier status to active, suspended or off_shift",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "SetCourierStatus",
+                "parameters": [
+                    {
+                        "description": "Courier status",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CourierStatusUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Courier status has been changed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/courier_zones": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the home zones of a courier, they then only see and claim deliveries of those zones\nand deliveries without a zone. An empty list lets the courier work everywhere",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "SetCourierZones",
+                "parameters": [
+                    {
+                        "description": "Home zones",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CourierZones"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Home zones",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Zone"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier or zone not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/courier_zones/{userid}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the home zones of the courier with the given user id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetCourierZones",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Courier user id",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Home zones",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Zone"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Courier not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/couriers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the courier roster with status and number of active deliveries",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetCouriers",
+                "responses": {
+                    "200": {
+                        "description": "Courier roster",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CourierLoad"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/delivery_attempts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the failed attempts recorded for a delivery, first attempt first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetDeliveryAttempts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attempts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DeliveryAttempt"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/delivery_cancellation/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the cancellation record of a delivery",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetCancellation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cancellation",
+                        "schema": {
+                            "$ref": "#/definitions/model.Cancellation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery was not cancelled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/delivery_legs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the legs of a multi-leg delivery with the state derived from them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetDeliveryLegs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Legs",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryLegs"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found or not routed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/delivery_price/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the price components frozen when the delivery was created",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetDeliveryPrice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Frozen price",
+                        "schema": {
+                            "$ref": "#/definitions/model.Quote"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery has no price",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/delivery_proof/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the proofs recorded for a delivery, files are downloaded by proof id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetDeliveryProofs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Proofs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DeliveryProof"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/delivery_proof_file/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the image stored for a photo or signature proof",
+                "produces": [
+                    "image/jpeg",
+                    "image/png"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetProofFile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Proof id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Proof image",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Proof or file not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/delivery_route": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Splits a new unassigned delivery into legs through the given hubs, in order. Each leg gets its own courier\nand the delivery status is derived from the legs from then on",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "RouteDelivery",
+                "parameters": [
+                    {
+                        "description": "Routing",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryRouting"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Legs",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryLegs"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery or hub not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is assigned, picked up or already routed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/delivery_track/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns downsampled track points of the delivery in time order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetDeliveryTrack",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Track",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TrackPoint"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/dispatch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Scores available couriers and assigns or offers the delivery to the best one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "Dispatch",
+                "parameters": [
+                    {
+                        "description": "Delivery to dispatch",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryId"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dispatch result",
+                        "schema": {
+                            "$ref": "#/definitions/model.DispatchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is already taken or has a pending offer",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/offer_metrics": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns offered, accepted, declined and expired counts and acceptance rate per courier for the last 30 days",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetOfferMetrics",
+                "responses": {
+                    "200": {
+                        "description": "Offer metrics",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.OfferMetrics"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/rating_alerts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns published ratings at or below the configured low score, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "GetRatingAlerts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only ratings created after this time (RFC 3339)",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Low ratings",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Rating"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/rating_moderation": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Hides a rating from the courier's aggregates, alerts and performance, or publishes it again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "ModerateRating",
+                "parameters": [
+                    {
+                        "description": "Moderation",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RatingModeration"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Moderated rating",
+                        "schema": {
+                            "$ref": "#/definitions/model.Rating"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Rating not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/reissue_delivery_pin": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the PIN of an open delivery, which also unlocks a PIN locked after too many wrong attempts.\nThe new PIN is returned only here, the manager passes it to the recipient",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "ReissuePin",
+                "parameters": [
+                    {
+                        "description": "Delivery",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryId"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New PIN",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryPin"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/unassign_delivery": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a delivery that is not picked up yet to the pool of available deliveries",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Manager methods"
+                ],
+                "summary": "UnassignDelivery",
+                "parameters": [
+                    {
+                        "description": "Delivery",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeliveryId"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery has been unassigned",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is picked up or closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/track/{code}": {
+            "get": {
+                "description": "Returns status, timeline, ETA and courier first name for a tracking code, no authorization required.\nA returned delivery also shows the leg taking the parcel back to the sender",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tracking"
+                ],
+                "summary": "Track",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tracking code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery tracking info",
+                        "schema": {
+                            "$ref": "#/definitions/model.TrackingView"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown or revoked code",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/track/{code}/rating": {
+            "post": {
+                "description": "Rates the courier of a delivered delivery from 1 to 5 with optional tags and comment, no authorization required.\nThe delivery id of the body is ignored, a delivery is rated once and only within the rating period after delivery",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tracking"
+                ],
+                "summary": "RateByTrackingCode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tracking code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rating",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RatingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Stored rating",
+                        "schema": {
+                            "$ref": "#/definitions/model.Rating"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown or revoked code",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery is not delivered, already rated or the rating period has ended",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.AttemptOutcome": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "$ref": "#/definitions/model.DeliveryAttempt"
+                },
+                "delivery_status": {
+                    "type": "string"
+                },
+                "return_delivery_id": {
+                    "type": "string"
+                },
+                "window_end": {
+                    "type": "string"
+                },
+                "window_start": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CancelRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Cancellation": {
+            "type": "object",
+            "properties": {
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "cancelled_by": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CashEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "handover_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CashHandover": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "counted": {
+                    "type": "number"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "discrepancy": {
+                    "type": "number"
+                },
+                "expected": {
+                    "type": "number"
+                },
+                "handed_over_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "manager_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CashHandoverRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "counted": {
+                    "type": "number"
+                },
+                "courier_userid": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CashLedger": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CashEntry"
+                    }
+                }
+            }
+        },
+        "model.Courier": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "perfomance_indicator": {
+                    "description": "Perfomance_indicator is computed from delivery outcomes and ignored on updates",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "userid": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CourierCapacityUpdate": {
+            "type": "object",
+            "properties": {
+                "max_active_deliveries": {
+                    "type": "integer"
+                },
+                "max_weight_kg": {
+                    "type": "number"
+                },
+                "userid": {
+                    "type": "string"
+                },
+                "vehicle_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CourierCash": {
+            "type": "object",
+            "properties": {
+                "collections": {
+                    "type": "integer"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "last_handover_at": {
+                    "type": "string"
+                },
+                "lifetime_discrepancies": {
+                    "description": "LifetimeDiscrepancies is the sum of every handover discrepancy the courier ever had, settled or not,\na shortage still owed is part of Outstanding because only the counted cash leaves the balance",
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "outstanding": {
+                    "type": "number"
+                },
+                "shortfall": {
+                    "description": "Shortfall is the COD amount the courier reported as not collected on delivered deliveries",
+                    "type": "number"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "userid": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CourierLoad": {
+            "type": "object",
+            "properties": {
+                "active_deliveries": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "perfomance_indicator": {
+                    "description": "Perfomance_indicator is computed from delivery outcomes and ignored on updates",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "userid": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CourierManagerPatch": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "userid": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CourierPatch": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CourierPosition": {
+            "type": "object",
+            "properties": {
+                "accuracy_m": {
+                    "type": "number"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "speed_mps": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "userid": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CourierRatings": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "type": "number"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "distribution": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "hidden": {
+                    "type": "integer"
+                },
+                "ratings": {
+                    "type": "integer"
+                },
+                "recent": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Rating"
+                    }
+                },
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "model.CourierShift": {
+            "type": "object",
+            "properties": {
+                "courier_id": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CourierShiftCreate": {
+            "type": "object",
+            "properties": {
+                "ends_at": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "userid": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CourierStatusUpdate": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                },
+                "userid": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CourierZones": {
+            "type": "object",
+            "properties": {
+                "userid": {
+                    "type": "string"
+                },
+                "zone_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.Delivery": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "cod_amount": {
+                    "description": "CodAmount is the cash the courier collects from the recipient, zero when the delivery is prepaid",
+                    "type": "number"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "declared_value": {
+                    "type": "number"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "delivery_comment": {
+                    "type": "string"
+                },
+                "delivery_status": {
+                    "type": "string"
+                },
+                "dropoff": {
+                    "$ref": "#/definitions/model.Location"
+                },
+                "express": {
+                    "type": "boolean"
+                },
+                "fragile": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "item_count": {
+                    "type": "integer"
+                },
+                "items": {
+                    "description": "Items are optional, when present WeightKg and the totals are computed from them",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DeliveryItem"
+                    }
+                },
+                "max_side_cm": {
+                    "type": "number"
+                },
+                "picked_up_at": {
+                    "type": "string"
+                },
+                "pickup": {
+                    "$ref": "#/definitions/model.Location"
+                },
+                "price": {
+                    "description": "Price is quoted when the delivery is created and never changes afterwards",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Quote"
+                        }
+                    ]
+                },
+                "promo_code": {
+                    "type": "string"
+                },
+                "recipient": {
+                    "$ref": "#/definitions/model.Recipient"
+                },
+                "temperature": {
+                    "type": "string"
+                },
+                "tracking_code": {
+                    "type": "string"
+                },
+                "volume_l": {
+                    "type": "number"
+                },
+                "weight_kg": {
+                    "type": "number"
+                },
+                "window_end": {
+                    "type": "string"
+                },
+                "window_start": {
+                    "type": "string"
+                },
+                "zone_id": {
+                    "description": "ZoneId is the service zone containing the pickup point, it is set when the delivery is priced",
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeliveryAssignment": {
+            "type": "object",
+            "properties": {
+                "courier_userid": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeliveryAttempt": {
+            "type": "object",
+            "properties": {
+                "attempted_at": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeliveryEvent": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "position": {
+                    "$ref": "#/definitions/model.GeoPoint"
+                },
+                "previous_courier_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeliveryGet": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "cod_amount": {
+                    "type": "number"
+                },
+                "cod_collected": {
+                    "description": "CodCollected is the cash recorded when a cash on delivery was delivered",
+                    "type": "number"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "declared_value": {
+                    "type": "number"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "delivery_comment": {
+                    "type": "string"
+                },
+                "delivery_status": {
+                    "type": "string"
+                },
+                "dropoff": {
+                    "$ref": "#/definitions/model.Location"
+                },
+                "eta": {
+                    "description": "ETA is the estimated drop-off time, nil until the courier's position is known",
+                    "type": "string"
+                },
+                "express": {
+                    "type": "boolean"
+                },
+                "fragile": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "item_count": {
+                    "type": "integer"
+                },
+                "items": {
+                    "description": "Items are only loaded for the delivery detail",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DeliveryItem"
+                    }
+                },
+                "legs": {
+                    "description": "Legs is the number of legs of a delivery routed through hubs, zero for direct deliveries.\nThe status, courier and timestamps of a multi-leg delivery are derived from its legs",
+                    "type": "integer"
+                },
+                "max_side_cm": {
+                    "type": "number"
+                },
+                "picked_up_at": {
+                    "type": "string"
+                },
+                "pickup": {
+                    "$ref": "#/definitions/model.Location"
+                },
+                "price": {
+                    "description": "Price is the frozen total, nil for deliveries created before pricing",
+                    "type": "number"
+                },
+                "recipient": {
+                    "$ref": "#/definitions/model.Recipient"
+                },
+                "return_of": {
+                    "type": "string"
+                },
+                "temperature": {
+                    "type": "string"
+                },
+                "volume_l": {
+                    "type": "number"
+                },
+                "weight_kg": {
+                    "type": "number"
+                },
+                "window_end": {
+                    "type": "string"
+                },
+                "window_start": {
+                    "type": "string"
+                },
+                "zone_id": {
+                    "description": "ZoneId is nil for deliveries picked up outside every service zone",
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeliveryId": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeliveryItem": {
+            "type": "object",
+            "properties": {
+                "declared_value": {
+                    "type": "number"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "fragile": {
+                    "type": "boolean"
+                },
+                "height_cm": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "length_cm": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "temperature": {
+                    "type": "string"
+                },
+                "weight_kg": {
+                    "type": "number"
+                },
+                "width_cm": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.DeliveryLeg": {
+            "type": "object",
+            "properties": {
+                "courier_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "from_hub_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "picked_up_at": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "to_hub_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeliveryLegs": {
+            "type": "object",
+            "properties": {
+                "at_hub_id": {
+                    "type": "string"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "legs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DeliveryLeg"
+                    }
+                },
+                "picked_up_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeliveryPin": {
+            "type": "object",
+            "properties": {
+                "pin": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeliveryProof": {
+            "type": "object",
+            "properties": {
+                "captured_at": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "size_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.DeliveryRouting": {
+            "type": "object",
+            "properties": {
+                "delivery_id": {
+                    "type": "string"
+                },
+                "hub_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.DeliveryStatus": {
+            "type": "object",
+            "properties": {
+                "delivery_status": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DispatchResult": {
+            "type": "object",
+            "properties": {
+                "courier_id": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "offer_id": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.Earning": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "leg_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.EarningAdjustmentRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "courier_userid": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.EventTicket": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "ticket": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.FailedAttempt": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GeoPoint": {
+            "type": "object",
+            "properties": {
+                "lat": {
+                    "type": "number"
+                },
+                "lon": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.Geometry": {
+            "type": "object",
+            "properties": {
+                "coordinates": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Hub": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "string"
                 },
-                "perfomance_indicator": {
+                "location": {
+                    "$ref": "#/definitions/model.Location"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.HubStaff": {
+            "type": "object",
+            "properties": {
+                "hub_id": {
+                    "type": "string"
+                },
+                "userid": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ImportReport": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ImportedDelivery"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ImportRowError"
+                    }
+                },
+                "inserted": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ImportRowError": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ImportedDelivery": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "pin": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "row": {
+                    "type": "integer"
+                },
+                "tracking_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LegAssignment": {
+            "type": "object",
+            "properties": {
+                "courier_userid": {
+                    "type": "string"
+                },
+                "leg_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LegId": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Location": {
+            "type": "object",
+            "properties": {
+                "address_line1": {
+                    "type": "string"
+                },
+                "address_line2": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "postcode": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LocationBatch": {
+            "type": "object",
+            "properties": {
+                "fixes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LocationFix"
+                    }
+                }
+            }
+        },
+        "model.LocationBatchResult": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.LocationFix": {
+            "type": "object",
+            "properties": {
+                "accuracy_m": {
+                    "type": "number"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "speed_mps": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.Login": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ManagerCancelRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "waive_fee": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.OfferId": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.OfferMetrics": {
+            "type": "object",
+            "properties": {
+                "acceptance_rate": {
+                    "type": "number"
+                },
+                "accepted": {
+                    "type": "integer"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "declined": {
+                    "type": "integer"
+                },
+                "expired": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "offered": {
+                    "type": "integer"
+                },
+                "surname": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.OfferView": {
+            "type": "object",
+            "properties": {
+                "courier_id": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "dropoff": {
+                    "$ref": "#/definitions/model.Location"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "offered_at": {
+                    "type": "string"
+                },
+                "pickup": {
+                    "$ref": "#/definitions/model.Location"
+                },
+                "responded_at": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "window_end": {
+                    "type": "string"
+                },
+                "window_start": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PerformanceBreakdown": {
+            "type": "object",
+            "properties": {
+                "acceptance_rate": {
+                    "type": "number"
+                },
+                "accepted": {
+                    "type": "integer"
+                },
+                "average_rating": {
+                    "type": "number"
+                },
+                "calculated_at": {
+                    "type": "string"
+                },
+                "completed": {
+                    "type": "integer"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "failed_rate": {
+                    "type": "number"
+                },
+                "offered": {
+                    "type": "integer"
+                },
+                "on_time": {
+                    "type": "integer"
+                },
+                "on_time_rate": {
+                    "type": "number"
+                },
+                "performance_indicator": {
+                    "type": "integer"
+                },
+                "ratings": {
+                    "type": "integer"
+                },
+                "window_start": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Promo": {
+            "type": "object",
+            "properties": {
+                "amount_off": {
+                    "type": "number"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "max_uses": {
+                    "type": "integer"
+                },
+                "percent_off": {
+                    "type": "number"
+                },
+                "used": {
+                    "type": "integer"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_to": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Quote": {
+            "type": "object",
+            "properties": {
+                "base_fee": {
+                    "type": "number"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "number"
+                },
+                "distance_fee": {
+                    "type": "number"
+                },
+                "distance_km": {
+                    "type": "number"
+                },
+                "express_surcharge": {
+                    "type": "number"
+                },
+                "fragile_surcharge": {
+                    "type": "number"
+                },
+                "night_surcharge": {
+                    "type": "number"
+                },
+                "promo_code": {
+                    "type": "string"
+                },
+                "quoted_at": {
+                    "type": "string"
+                },
+                "tariff_id": {
+                    "type": "string"
+                },
+                "tariff_version": {
                     "type": "integer"
                 },
-                "status": {
+                "total": {
+                    "type": "number"
+                },
+                "weight_fee": {
+                    "type": "number"
+                },
+                "weight_kg": {
+                    "type": "number"
+                },
+                "zone": {
                     "type": "string"
+                }
+            }
+        },
+        "model.QuoteRequest": {
+            "type": "object",
+            "properties": {
+                "dropoff": {
+                    "$ref": "#/definitions/model.Location"
                 },
-                "surname": {
+                "express": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DeliveryItem"
+                    }
+                },
+                "pickup": {
+                    "$ref": "#/definitions/model.Location"
+                },
+                "promo_code": {
                     "type": "string"
                 },
-                "userid": {
+                "weight_kg": {
+                    "type": "number"
+                },
+                "window_start": {
                     "type": "string"
                 }
             }
         },
-        "model.Delivery": {
+        "model.Rating": {
             "type": "object",
             "properties": {
-                "courier_id": {
+                "comment": {
                     "type": "string"
                 },
-                "delivery_comment": {
+                "courier_id": {
                     "type": "string"
                 },
-                "delivery_date": {
+                "created_at": {
                     "type": "string"
                 },
-                "delivery_status": {
+                "delivery_id": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
+                },
+                "moderated_at": {
+                    "type": "string"
+                },
+                "moderated_by": {
+                    "type": "string"
+                },
+                "moderation_note": {
+                    "type": "string"
+                },
+                "rated_by": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "model.DeliveryGet": {
+        "model.RatingModeration": {
             "type": "object",
             "properties": {
-                "delivery_comment": {
+                "id": {
                     "type": "string"
                 },
-                "delivery_date": {
+                "note": {
                     "type": "string"
                 },
-                "delivery_status": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RatingRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "model.DeliveryId": {
+        "model.Recipient": {
             "type": "object",
             "properties": {
-                "id": {
+                "access_notes": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
                     "type": "string"
                 }
             }
         },
-        "model.DeliveryStatus": {
+        "model.RoutePlan": {
             "type": "object",
             "properties": {
-                "delivery_status": {
+                "courier_id": {
                     "type": "string"
                 },
-                "id": {
+                "distance_km": {
+                    "type": "number"
+                },
+                "finish_at": {
                     "type": "string"
+                },
+                "late_stops": {
+                    "type": "integer"
+                },
+                "stops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RouteStop"
+                    }
                 }
             }
         },
-        "model.Login": {
+        "model.RouteStop": {
             "type": "object",
             "properties": {
-                "login": {
+                "arrival": {
                     "type": "string"
                 },
-                "password": {
+                "delivery_id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "late": {
+                    "type": "boolean"
+                },
+                "location": {
+                    "$ref": "#/definitions/model.Location"
+                },
+                "window_end": {
+                    "type": "string"
+                },
+                "window_start": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ShiftId": {
+            "type": "object",
+            "properties": {
+                "id": {
                     "type": "string"
                 }
             }
@@ -614,6 +7037,202 @@ const docTemplate = `{
                 }
             }
         },
+        "model.Statement": {
+            "type": "object",
+            "properties": {
+                "adjustment_amount": {
+                    "type": "number"
+                },
+                "bonus_amount": {
+                    "type": "number"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "deliveries": {
+                    "type": "integer"
+                },
+                "delivery_amount": {
+                    "type": "number"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Earning"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "penalty_amount": {
+                    "type": "number"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "period_end": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "userid": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Tariff": {
+            "type": "object",
+            "properties": {
+                "base_fee": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "express_pct": {
+                    "type": "number"
+                },
+                "fragile_fee": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "min_price": {
+                    "type": "number"
+                },
+                "night_end_hour": {
+                    "type": "integer"
+                },
+                "night_pct": {
+                    "type": "number"
+                },
+                "night_start_hour": {
+                    "type": "integer"
+                },
+                "per_kg": {
+                    "type": "number"
+                },
+                "per_km": {
+                    "type": "number"
+                },
+                "time_zone": {
+                    "type": "string"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                },
+                "zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TrackPoint": {
+            "type": "object",
+            "properties": {
+                "delivery_id": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "recorded_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TrackingCode": {
+            "type": "object",
+            "properties": {
+                "tracking_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TrackingEvent": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TrackingReturn": {
+            "type": "object",
+            "properties": {
+                "delivered_at": {
+                    "type": "string"
+                },
+                "eta": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "window_end": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TrackingView": {
+            "type": "object",
+            "properties": {
+                "courier_first_name": {
+                    "type": "string"
+                },
+                "eta": {
+                    "type": "string"
+                },
+                "return": {
+                    "description": "Return follows the parcel back to the sender once the delivery is returned",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TrackingReturn"
+                        }
+                    ]
+                },
+                "status": {
+                    "type": "string"
+                },
+                "timeline": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TrackingEvent"
+                    }
+                },
+                "window_end": {
+                    "type": "string"
+                },
+                "window_start": {
+                    "type": "string"
+                }
+            }
+        },
         "model.User": {
             "type": "object",
             "properties": {
@@ -642,6 +7261,55 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "model.Zone": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "boundary": {
+                    "$ref": "#/definitions/model.Geometry"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hours": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ZoneHours"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ZoneHours": {
+            "type": "object",
+            "properties": {
+                "close": {
+                    "type": "string"
+                },
+                "open": {
+                    "type": "string"
+                },
+                "weekday": {
+                    "type": "integer"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,95 @@
+// Package blobstore keeps binary artifacts such as proof of delivery images
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// LocalStore keeps blobs as files under a root directory
+type LocalStore struct {
+	root string
+}
+
+// NewLocalStore creates the root directory if it does not exist
+func NewLocalStore(root string) (*LocalStore, error) {
+	err := os.MkdirAll(root, 0o750)
+	if err != nil {
+		return nil, fmt.Errorf("MkdirAll(): %w", err)
+	}
+	return &LocalStore{root: root}, nil
+}
+
+// path maps a slash separated key to a file inside the root, keys must not leave the root
+func (s *LocalStore) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: invalid blob key %q", model.ErrValidation, key)
+	}
+	return filepath.Join(s.root, clean), nil
+}
+
+// Put writes the blob through a temporary file so that readers never see a partial blob
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o750)
+	if err != nil {
+		return fmt.Errorf("MkdirAll(): %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("CreateTemp(): %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("Copy(): %w", err)
+	}
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("Rename(): %w", err)
+	}
+	return nil
+}
+
+// Open returns model.ErrNotFound for unknown keys
+func (s *LocalStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("Open(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Open(): %w", err)
+	}
+	return file, nil
+}
+
+// Delete ignores keys that do not exist
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("Remove(): %w", err)
+	}
+	return nil
+}
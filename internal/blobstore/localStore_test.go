@@ -0,0 +1,32 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLocalStore checks a put/open/delete round trip and that keys can not escape the root
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "deliveries/1/photo.jpg", strings.NewReader("test_blob")))
+	blob, err := store.Open(ctx, "deliveries/1/photo.jpg")
+	require.NoError(t, err)
+	data, err := io.ReadAll(blob)
+	require.NoError(t, err)
+	require.NoError(t, blob.Close())
+	require.Equal(t, "test_blob", string(data))
+
+	require.NoError(t, store.Delete(ctx, "deliveries/1/photo.jpg"))
+	_, err = store.Open(ctx, "deliveries/1/photo.jpg")
+	require.ErrorIs(t, err, model.ErrNotFound)
+
+	require.ErrorIs(t, store.Put(ctx, "../outside", strings.NewReader("")), model.ErrValidation)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type AttemptHandler struct {
+	srv AttemptServiceInterface
+}
+
+func NewAttemptHandler(srv AttemptServiceInterface) *AttemptHandler {
+	return &AttemptHandler{srv: srv}
+}
+
+type AttemptServiceInterface interface {
+	FailAttempt(ctx context.Context, userId uuid.UUID, report *model.FailedAttempt) (*model.AttemptOutcome, error)
+	GetDeliveryAttempts(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryAttempt, error)
+}
+
+// FailAttempt reports that the courier could not hand the delivery over
+// @Summary FailAttempt
+// @Description Records a failed attempt with a reason (recipient_absent, address_not_found, access_denied, refused,
+// @Description unsafe_location, other). The delivery stays with the courier and is rescheduled into its next window, or returned to the sender
+// @Description on a new delivery leg carried by the same courier once the maximum number of attempts is reached.
+// @Description The sender follows the return leg with the tracking code of the original delivery
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.FailedAttempt true "Failed attempt"
+// @Success 200 {object} model.AttemptOutcome "What happens to the delivery next"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Delivery not found"
+// @Failure 409 {string} string "Delivery is not picked up"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/failed_attempt [post]
+func (h *AttemptHandler) FailAttempt(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	report := &model.FailedAttempt{}
+	err = c.Bind(report)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	outcome, err := h.srv.FailAttempt(c.Request().Context(), userId, report)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId, "deliveryId": report.DeliveryId}).Errorf("FailAttempt: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("FailAttempt: %v", err))
+	}
+	return c.JSON(http.StatusOK, outcome)
+}
+
+// GetDeliveryAttempts returns failed attempts of a delivery
+// @Summary GetDeliveryAttempts
+// @Description Returns the failed attempts recorded for a delivery, first attempt first
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Delivery id"
+// @Success 200 {array} model.DeliveryAttempt "Attempts"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/delivery_attempts/{id} [get]
+func (h *AttemptHandler) GetDeliveryAttempts(c echo.Context) error {
+	deliveryId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	attempts, err := h.srv.GetDeliveryAttempts(c.Request().Context(), deliveryId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"deliveryId": deliveryId}).Errorf("GetDeliveryAttempts: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetDeliveryAttempts: %v", err))
+	}
+	return c.JSON(http.StatusOK, attempts)
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type AvailabilityHandler struct {
+	srv AvailabilityServiceInterface
+}
+
+func NewAvailabilityHandler(srv AvailabilityServiceInterface) *AvailabilityHandler {
+	return &AvailabilityHandler{srv: srv}
+}
+
+type AvailabilityServiceInterface interface {
+	GoOnline(ctx context.Context, userId uuid.UUID) error
+	GoOffline(ctx context.Context, userId uuid.UUID) error
+	GetMyShifts(ctx context.Context, userId uuid.UUID) ([]*model.CourierShift, error)
+	SetCourierCapacity(ctx context.Context, update *model.CourierCapacityUpdate) error
+	PlanShift(ctx context.Context, create *model.CourierShiftCreate) (*model.CourierShift, error)
+	CancelShift(ctx context.Context, shiftId uuid.UUID) error
+}
+
+// GoOnline makes the courier available for deliveries
+// @Summary GoOnline
+// @Description Switches the authorized courier from off_shift to active, allowed only during a planned shift
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {string} string "Courier is online"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 409 {string} string "Courier is not on shift or can not change status"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/go_online [patch]
+func (h *AvailabilityHandler) GoOnline(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	err = h.srv.GoOnline(c.Request().Context(), userId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GoOnline: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GoOnline: %v", err))
+	}
+	return c.JSON(http.StatusOK, "Courier is online")
+}
+
+// GoOffline stops new deliveries from reaching the courier
+// @Summary GoOffline
+// @Description Switches the authorized courier from active to off_shift, assigned deliveries stay with the courier
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {string} string "Courier is offline"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 409 {string} string "Courier can not change status"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/go_offline [patch]
+func (h *AvailabilityHandler) GoOffline(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	err = h.srv.GoOffline(c.Request().Context(), userId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GoOffline: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GoOffline: %v", err))
+	}
+	return c.JSON(http.StatusOK, "Courier is offline")
+}
+
+// GetMyShifts returns planned shifts of the courier
+// @Summary GetMyShifts
+// @Description Returns current and upcoming shifts of the authorized courier for the next two weeks
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} model.CourierShift "Shifts"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/shifts [get]
+func (h *AvailabilityHandler) GetMyShifts(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	shifts, err := h.srv.GetMyShifts(c.Request().Context(), userId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GetMyShifts: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetMyShifts: %v", err))
+	}
+	return c.JSON(http.StatusOK, shifts)
+}
+
+// SetCourierCapacity changes delivery limits of a courier
+// @Summary SetCourierCapacity
+// @Description Sets the maximum number of concurrent deliveries and the optional vehicle weight limit
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.CourierCapacityUpdate true "Courier capacity"
+// @Success 200 {string} string "Courier capacity has been changed"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/courier_capacity [patch]
+func (h *AvailabilityHandler) SetCourierCapacity(c echo.Context) error {
+	update := &model.CourierCapacityUpdate{}
+	err := c.Bind(update)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"update": update}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	err = h.srv.SetCourierCapacity(c.Request().Context(), update)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"update": update}).Errorf("SetCourierCapacity: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("SetCourierCapacity: %v", err))
+	}
+	return c.JSON(http.StatusOK, "Courier capacity has been changed")
+}
+
+// PlanShift adds a shift to the courier schedule
+// @Summary PlanShift
+// @Description Plans a working period for the courier with the given user id
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.CourierShiftCreate true "Shift"
+// @Success 201 {object} model.CourierShift "Planned shift"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/courier_shift [post]
+func (h *AvailabilityHandler) PlanShift(c echo.Context) error {
+	create := &model.CourierShiftCreate{}
+	err := c.Bind(create)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"shift": create}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	shift, err := h.srv.PlanShift(c.Request().Context(), create)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"shift": create}).Errorf("PlanShift: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("PlanShift: %v", err))
+	}
+	return c.JSON(http.StatusCreated, shift)
+}
+
+// CancelShift removes a shift from the courier schedule
+// @Summary CancelShift
+// @Description Deletes a planned shift
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.ShiftId true "Shift"
+// @Success 200 {string} string "Shift has been cancelled"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/courier_shift [delete]
+func (h *AvailabilityHandler) CancelShift(c echo.Context) error {
+	Id := &model.ShiftId{}
+	err := c.Bind(Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"Id": Id}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	err = h.srv.CancelShift(c.Request().Context(), Id.Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"shiftId": Id.Id}).Errorf("CancelShift: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("CancelShift: %v", err))
+	}
+	return c.JSON(http.StatusOK, "Shift has been cancelled")
+}
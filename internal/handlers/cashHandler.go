@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type CashHandler struct {
+	srv CashServiceInterface
+}
+
+func NewCashHandler(srv CashServiceInterface) *CashHandler {
+	return &CashHandler{srv: srv}
+}
+
+type CashServiceInterface interface {
+	GetCashLedger(ctx context.Context, userId uuid.UUID, filter *model.DeliveryFilter) (*model.CashLedger, error)
+	RecordHandover(ctx context.Context, managerId uuid.UUID, request *model.CashHandoverRequest) (*model.CashHandover, error)
+	GetCashHandovers(ctx context.Context, userId uuid.UUID) ([]*model.CashHandover, error)
+	GetOutstandingCash(ctx context.Context) ([]*model.CourierCash, error)
+}
+
+// periodFilter reads the optional from and to query parameters
+func periodFilter(c echo.Context) (*model.DeliveryFilter, error) {
+	from, err := timeQueryParam(c, "from")
+	if err != nil {
+		return nil, fmt.Errorf("from: %w", err)
+	}
+	to, err := timeQueryParam(c, "to")
+	if err != nil {
+		return nil, fmt.Errorf("to: %w", err)
+	}
+	return &model.DeliveryFilter{From: from, To: to}, nil
+}
+
+// GetMyCash returns the cash ledger of the authorized courier
+// @Summary GetMyCash
+// @Description Returns the cash the authorized courier holds and their ledger entries of the period
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Produce json
+// @Param from query string false "Only entries created after this time (RFC 3339)"
+// @Param to query string false "Only entries created before this time (RFC 3339)"
+// @Success 200 {object} model.CashLedger "Cash ledger"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/cash [get]
+func (h *CashHandler) GetMyCash(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	filter, err := periodFilter(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	ledger, err := h.srv.GetCashLedger(c.Request().Context(), userId, filter)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GetCashLedger: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetCashLedger: %v", err))
+	}
+	return c.JSON(http.StatusOK, ledger)
+}
+
+// GetCourierCash returns the cash ledger of a courier
+// @Summary GetCourierCash
+// @Description Returns the cash the courier holds and their ledger entries of the period
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Param userid path string true "Courier user id"
+// @Param from query string false "Only entries created after this time (RFC 3339)"
+// @Param to query string false "Only entries created before this time (RFC 3339)"
+// @Success 200 {object} model.CashLedger "Cash ledger"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/courier_cash/{userid} [get]
+func (h *CashHandler) GetCourierCash(c echo.Context) error {
+	userId, err := uuid.Parse(c.Param("userid"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	filter, err := periodFilter(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	ledger, err := h.srv.GetCashLedger(c.Request().Context(), userId, filter)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GetCashLedger: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetCashLedger: %v", err))
+	}
+	return c.JSON(http.StatusOK, ledger)
+}
+
+// RecordHandover reconciles the cash a courier hands over
+// @Summary RecordHandover
+// @Description Records the cash counted at shift end against the courier's balance, the discrepancy is kept with the handover
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.CashHandoverRequest true "Counted cash"
+// @Success 201 {object} model.CashHandover "Recorded handover"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 409 {string} string "Cash was collected meanwhile"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/cash_handover [post]
+func (h *CashHandler) RecordHandover(c echo.Context) error {
+	managerId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": managerId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	request := &model.CashHandoverRequest{}
+	err = c.Bind(request)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"request": request}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	handover, err := h.srv.RecordHandover(c.Request().Context(), managerId, request)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"request": request}).Errorf("RecordHandover: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("RecordHandover: %v", err))
+	}
+	return c.JSON(http.StatusCreated, handover)
+}
+
+// GetCashHandovers lists the handovers of a courier
+// @Summary GetCashHandovers
+// @Description Returns the cash handovers of the courier with their discrepancies, newest first
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Param userid path string true "Courier user id"
+// @Success 200 {array} model.CashHandover "Handovers"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/cash_handovers/{userid} [get]
+func (h *CashHandler) GetCashHandovers(c echo.Context) error {
+	userId, err := uuid.Parse(c.Param("userid"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	handovers, err := h.srv.GetCashHandovers(c.Request().Context(), userId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GetCashHandovers: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetCashHandovers: %v", err))
+	}
+	return c.JSON(http.StatusOK, handovers)
+}
+
+// GetOutstandingCash reports the cash held by couriers
+// @Summary GetOutstandingCash
+// @Description Returns couriers whose cash balance is not settled with their collections, the lifetime total of their
+// @Description handover discrepancies and the COD amount they reported as not collected
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} model.CourierCash "Outstanding cash per courier"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/cash_outstanding [get]
+func (h *CashHandler) GetOutstandingCash(c echo.Context) error {
+	cash, err := h.srv.GetOutstandingCash(c.Request().Context())
+	if err != nil {
+		logrus.Errorf("GetOutstandingCash: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetOutstandingCash: %v", err))
+	}
+	return c.JSON(http.StatusOK, cash)
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type ClientHandler struct {
+	srv ClientServiceInterface
+}
+
+func NewClientHandler(srv ClientServiceInterface) *ClientHandler {
+	return &ClientHandler{srv: srv}
+}
+
+type ClientServiceInterface interface {
+	PlaceOrder(ctx context.Context, clientId uuid.UUID, delivery *model.Delivery) (*model.Delivery, error)
+	GetOrderHistory(ctx context.Context, clientId uuid.UUID) ([]*model.DeliveryGet, error)
+	GetOrder(ctx context.Context, clientId uuid.UUID, deliveryId uuid.UUID) (*model.DeliveryGet, error)
+	CancelOrder(ctx context.Context, clientId uuid.UUID, request *model.CancelRequest) (*model.Cancellation, error)
+	ReissueTrackingCode(ctx context.Context, clientId uuid.UUID, deliveryId uuid.UUID) (string, error)
+}
+
+// CreateDelivery places a new delivery order owned by the client
+// @Summary CreateDelivery
+// @Description Places a new delivery order on behalf of the authorized client.
+// @Description The response carries the recipient PIN, it is not shown on the tracking page and must be passed to the recipient
+// @Tags Client methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.Delivery true "Delivery to order"
+// @Success 201 {object} map[string]interface{} "Delivery has been sucessfully ordered"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /client/create_delivery [post]
+func (h *ClientHandler) CreateDelivery(c echo.Context) error {
+	clientId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": clientId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	delivery := &model.Delivery{}
+	err = c.Bind(delivery)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"delivery": delivery}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	created, err := h.srv.PlaceOrder(c.Request().Context(), clientId, delivery)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"clientId": clientId}).Errorf("PlaceOrder: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("PlaceOrder: %v", err))
+	}
+	response := map[string]interface{}{
+		"message":       "delivery ordered!",
+		"id":            created.Id,
+		"tracking_code": created.TrackingCode,
+		"pin":           created.Pin,
+		"price":         created.Price,
+	}
+	return c.JSON(http.StatusCreated, response)
+}
+
+// GetMyDeliveries returns the order history of the client
+// @Summary GetMyDeliveries
+// @Description Returns all deliveries ordered by the authorized client, newest first
+// @Tags Client methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} model.DeliveryGet "Client's deliveries"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /client/getmydeliveries [get]
+func (h *ClientHandler) GetMyDeliveries(c echo.Context) error {
+	clientId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": clientId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	deliveries, err := h.srv.GetOrderHistory(c.Request().Context(), clientId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"clientId": clientId}).Errorf("GetOrderHistory: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetOrderHistory: %v", err))
+	}
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+// GetDelivery returns current state of one of the client's deliveries
+// @Summary GetDelivery
+// @Description Returns live status of a delivery owned by the authorized client
+// @Tags Client methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Delivery id"
+// @Success 200 {object} model.DeliveryGet "Delivery"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /client/getdelivery/{id} [get]
+func (h *ClientHandler) GetDelivery(c echo.Context) error {
+	clientId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": clientId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	deliveryId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	delivery, err := h.srv.GetOrder(c.Request().Context(), clientId, deliveryId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"clientId": clientId, "deliveryId": deliveryId}).Errorf("GetOrder: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetOrder: %v", err))
+	}
+	return c.JSON(http.StatusOK, delivery)
+}
+
+// CancelDelivery cancels the client's delivery before it is picked up
+// @Summary CancelDelivery
+// @Description Cancels a delivery owned by the authorized client, only possible before pickup.
+// @Description Reasons: no_longer_needed, wrong_details, duplicate, too_late, operational, other (needs a comment).
+// @Description A fee may apply once a courier is assigned
+// @Tags Client methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.CancelRequest true "Delivery to cancel and reason"
+// @Success 200 {object} model.Cancellation "Cancellation with the fee charged"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Delivery is already picked up"
+// @Failure 500 {string} string "Internal server error"
+// @Router /client/cancel_delivery [patch]
+func (h *ClientHandler) CancelDelivery(c echo.Context) error {
+	clientId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": clientId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	request := &model.CancelRequest{}
+	err = c.Bind(request)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"request": request}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	cancellation, err := h.srv.CancelOrder(c.Request().Context(), clientId, request)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"clientId": clientId, "deliveryId": request.Id}).Errorf("CancelOrder: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("CancelOrder: %v", err))
+	}
+	return c.JSON(http.StatusOK, cancellation)
+}
+
+// ReissueTrackingCode replaces the public tracking code of the client's delivery
+// @Summary ReissueTrackingCode
+// @Description Revokes the current tracking code of a delivery owned by the authorized client and issues a new one
+// @Tags Client methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.DeliveryId true "Delivery"
+// @Success 200 {object} model.TrackingCode "New tracking code"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /client/reissue_tracking_code [patch]
+func (h *ClientHandler) ReissueTrackingCode(c echo.Context) error {
+	clientId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": clientId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	Id := &model.DeliveryId{}
+	err = c.Bind(Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"Id": Id}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	code, err := h.srv.ReissueTrackingCode(c.Request().Context(), clientId, Id.Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"clientId": clientId, "deliveryId": Id.Id}).Errorf("ReissueTrackingCode: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("ReissueTrackingCode: %v", err))
+	}
+	return c.JSON(http.StatusOK, &model.TrackingCode{Code: code})
+}
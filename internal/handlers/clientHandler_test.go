@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/handlers/mocks"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/liza/labwork_45/internal/service"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestContext builds an echo context with a bearer token issued for the given user
+func newTestContext(t *testing.T, method, target, body string, userId uuid.UUID, role string) (echo.Context, *httptest.ResponseRecorder) {
+	access, _, err := service.GenerateAccessAndRefreshTokens("test_key", role, userId)
+	require.NoError(t, err)
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Authorization", "Bearer "+access)
+	rec := httptest.NewRecorder()
+	return echo.New().NewContext(req, rec), rec
+}
+
+// TestGetDeliveryOfOtherClient checks that a foreign delivery is reported as not found
+func TestGetDeliveryOfOtherClient(t *testing.T) {
+	srv := mocks.NewClientServiceInterface(t)
+	clientId, deliveryId := uuid.New(), uuid.New()
+	srv.On("GetOrder", mock.Anything, clientId, deliveryId).Return(nil, model.ErrNotFound)
+
+	c, _ := newTestContext(t, http.MethodGet, "/client/getdelivery/"+deliveryId.String(), "", clientId, "Client")
+	c.SetParamNames("id")
+	c.SetParamValues(deliveryId.String())
+	err := NewClientHandler(srv).GetDelivery(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	require.True(t, ok)
+	require.Equal(t, http.StatusNotFound, httpErr.Code)
+}
+
+// TestCancelPickedUpDelivery checks that cancelling after pickup is a conflict
+func TestCancelPickedUpDelivery(t *testing.T) {
+	srv := mocks.NewClientServiceInterface(t)
+	clientId, deliveryId := uuid.New(), uuid.New()
+	srv.On("CancelOrder", mock.Anything, clientId, mock.MatchedBy(func(request *model.CancelRequest) bool {
+		return request.Id == deliveryId && request.Reason == model.CancelTooLate
+	})).Return(nil, model.ErrConflict)
+
+	c, _ := newTestContext(t, http.MethodPatch, "/client/cancel_delivery", `{"id":"`+deliveryId.String()+`","reason":"too_late"}`, clientId, "Client")
+	err := NewClientHandler(srv).CancelDelivery(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	require.True(t, ok)
+	require.Equal(t, http.StatusConflict, httpErr.Code)
+}
+
+// TestCreateClientDelivery checks that the order is placed for the token owner, who gets the tracking code and the PIN
+func TestCreateClientDelivery(t *testing.T) {
+	srv := mocks.NewClientServiceInterface(t)
+	clientId, deliveryId := uuid.New(), uuid.New()
+	srv.On("PlaceOrder", mock.Anything, clientId, mock.AnythingOfType("*model.Delivery")).Return(&model.Delivery{Id: deliveryId, TrackingCode: "TESTCODE0000", Pin: "123456"}, nil)
+
+	c, rec := newTestContext(t, http.MethodPost, "/client/create_delivery", `{"delivery_comment":"test_comment"}`, clientId, "Client")
+	err := NewClientHandler(srv).CreateDelivery(c)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Contains(t, rec.Body.String(), deliveryId.String())
+	require.Contains(t, rec.Body.String(), "TESTCODE0000")
+	require.Contains(t, rec.Body.String(), `"pin":"123456"`)
+}
@@ -22,24 +22,27 @@ func NewCourierHandler(srv CourierServiceInterface) *CourierHandler {
 }
 
 type CourierServiceInterface interface {
-	UpdateCourier(context.Context, uuid.UUID, *model.Courier) error
+	UpdateCourier(context.Context, uuid.UUID, *model.CourierPatch) (*model.Courier, error)
 	CreateDelivery(context.Context, *model.Delivery) error
-	GetAllDeliveries(context.Context) ([]*model.DeliveryGet, error)
+	GetAllDeliveries(context.Context, *model.DeliveryFilter) ([]*model.DeliveryGet, error)
+	GetDelivery(ctx context.Context, userId uuid.UUID, deliveryId uuid.UUID) (*model.DeliveryGet, error)
 	AssignCourierToDelivery(context.Context, uuid.UUID, uuid.UUID) error
-	UpdateDeliveryStatus(context.Context, *model.DeliveryStatus) error
+	UpdateDeliveryStatus(ctx context.Context, userId uuid.UUID, delivery *model.DeliveryStatus) error
 }
 
-// UpdateCourier updates courier info for the given Courier instance
+// UpdateCourier updates the fields present in the request
 // @Summary UpdateCourier
-// @Description Updates Courier information
+// @Description Updates name and surname of the authorized courier, status changes must be valid transitions, omitted fields are kept
 // @Tags Courier Bussiness logic
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
-// @Param input body model.Courier true "Courier to update"
-// @Success 200 {object} map[string]interface{} "Courier info has been sucessfully updated"
+// @Param input body model.CourierPatch true "Fields to update"
+// @Success 200 {object} model.Courier "Updated courier"
 // @Failure 400 {string} string "Bad request"
 // @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 409 {string} string "Status transition is not allowed"
 // @Failure 500 {string} string "Internal server error"
 // @Router /courier/updatecourier [patch]
 func (h *CourierHandler) UpdateCourier(c echo.Context) error {
@@ -48,26 +51,23 @@ func (h *CourierHandler) UpdateCourier(c echo.Context) error {
 		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
 	}
-	courier := &model.Courier{}
-	err = c.Bind(courier)
+	patch := &model.CourierPatch{}
+	err = c.Bind(patch)
 	if err != nil {
-		logrus.WithFields(logrus.Fields{"courier": courier}).Errorf("Bind: %v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Bind: %v", err))
+		logrus.WithFields(logrus.Fields{"patch": patch}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
 	}
-	err = h.srv.UpdateCourier(c.Request().Context(), userId, courier)
+	courier, err := h.srv.UpdateCourier(c.Request().Context(), userId, patch)
 	if err != nil {
-		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("CreateCourier: %v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("CreateCourier: %v", err))
-	}
-	response := map[string]interface{}{
-		"message": "courier info updated!",
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("UpdateCourier: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("UpdateCourier: %v", err))
 	}
-	return c.JSON(http.StatusCreated, response)
+	return c.JSON(http.StatusOK, courier)
 }
 
 // CreateDelivery creates a new delivery
 // @Summary CreateDelivery
-// @Description Creates a new delivery instance
+// @Description Creates a new delivery instance. The response carries the recipient PIN, it is not shown on the tracking page
 // @Tags Courier Bussiness logic
 // @Security ApiKeyAuth
 // @Accept json
@@ -88,27 +88,46 @@ func (h *CourierHandler) CreateDelivery(c echo.Context) error {
 	err = h.srv.CreateDelivery(c.Request().Context(), delivery)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{"delivery": delivery}).Errorf("CreateDelivery: %v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("CreateDelivery: %v", err))
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("CreateDelivery: %v", err))
 	}
 	response := map[string]interface{}{
-		"message": "delivery info updated!",
+		"message":       "delivery info updated!",
+		"id":            delivery.Id,
+		"tracking_code": delivery.TrackingCode,
+		"pin":           delivery.Pin,
 	}
 	return c.JSON(http.StatusCreated, response)
 }
 
 // CreateDelivery creates a new delivery
 // @Summary GetAlldeliveries
-// @Description GetAlldeliveries
+// @Description Lists deliveries, couriers with home zones only see deliveries of those zones and deliveries without a zone.
+// @Description The recipient is left empty, the assigned courier reads it from /courier/delivery/{id}
 // @Tags Courier Bussiness logic
 // @Security ApiKeyAuth
 // @Produce json
+// @Param from query string false "Only deliveries whose window ends after this time (RFC 3339)"
+// @Param to query string false "Only deliveries whose window starts before this time (RFC 3339)"
 // @Success 200 {array} model.DeliveryGet "Delivery has been sucessfully created"
 // @Failure 400 {string} string "Bad request"
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 500 {string} string "Internal server error"
 // @Router /courier/getalldeliveries [get]
 func (h *CourierHandler) GetAlldeliveries(c echo.Context) error {
-	deliveries, err := h.srv.GetAllDeliveries(c.Request().Context())
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	from, err := timeQueryParam(c, "from")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("from: %v", err))
+	}
+	to, err := timeQueryParam(c, "to")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("to: %v", err))
+	}
+	deliveries, err := h.srv.GetAllDeliveries(c.Request().Context(), &model.DeliveryFilter{From: from, To: to, CourierUserId: &userId})
 	if err != nil {
 		logrus.WithFields(logrus.Fields{"id": c.Param("userId")}).Errorf("GetAllDeliveries: %v", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetAllDeliveries: %v", err))
@@ -127,6 +146,9 @@ func (h *CourierHandler) GetAlldeliveries(c echo.Context) error {
 // @Success 200 {string} string "Delivery has been sucessfully choosed by courier"
 // @Failure 400 {string} string "Bad request"
 // @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Delivery is outside the courier's home zones"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Courier is offline, off shift, at capacity or delivery is taken"
 // @Failure 500 {string} string "Internal server error"
 // @Router /courier/choose_availible_delivery [patch]
 func (h *CourierHandler) ChooseAvailibleDelivery(c echo.Context) error {
@@ -145,14 +167,15 @@ func (h *CourierHandler) ChooseAvailibleDelivery(c echo.Context) error {
 	err = h.srv.AssignCourierToDelivery(c.Request().Context(), Id.Id, userId)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("AssignCourierToDelivery: %v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("AssignCourierToDelivery: %v", err))
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("AssignCourierToDelivery: %v", err))
 	}
 	return c.JSON(http.StatusOK, "OK, let's go!")
 }
 
 // CreateDelivery creates a new delivery
 // @Summary UpdateDeliveryStatus
-// @Description allows the courier to update delivery status
+// @Description Lets the courier mark their delivery picked_up. Delivered is set through /courier/deliver
+// @Description and failed attempts are reported through /courier/failed_attempt
 // @Tags Courier Bussiness logic
 // @Security ApiKeyAuth
 // @Accept json
@@ -161,20 +184,59 @@ func (h *CourierHandler) ChooseAvailibleDelivery(c echo.Context) error {
 // @Success 200 {string} string "Delivery status has been sucessfully updated by courier"
 // @Failure 400 {string} string "Bad request"
 // @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Couriers can not cancel deliveries"
+// @Failure 404 {string} string "Delivery is not assigned to the courier"
+// @Failure 409 {string} string "Delivery is already picked up or closed"
 // @Failure 500 {string} string "Internal server error"
 // @Router /courier/update_delivery_status [patch]
 func (h *CourierHandler) UpdateDeliveryStatus(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
 	delivery := &model.DeliveryStatus{}
-	err := c.Bind(delivery)
+	err = c.Bind(delivery)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{"delivery": delivery}).Errorf("Bind: %v", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Bind: %v", err))
 	}
-	err = h.srv.UpdateDeliveryStatus(c.Request().Context(), delivery)
+	err = h.srv.UpdateDeliveryStatus(c.Request().Context(), userId, delivery)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{"deliveryId": delivery}).Errorf("UpdateDeliveryStatus: %v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("UpdateDeliveryStatus: %v", err))
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("UpdateDeliveryStatus: %v", err))
 	}
 	return c.JSON(http.StatusOK, "Status has been changed successfully")
 
 }
+
+// GetDelivery returns the detail of a delivery assigned to the authorized courier
+// @Summary GetDelivery
+// @Description Returns a delivery assigned to the authorized courier with its items and totals
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Delivery id"
+// @Success 200 {object} model.DeliveryGet "Delivery with items"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Delivery not found or not assigned to the courier"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/delivery/{id} [get]
+func (h *CourierHandler) GetDelivery(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	deliveryId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	delivery, err := h.srv.GetDelivery(c.Request().Context(), userId, deliveryId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId, "deliveryId": deliveryId}).Errorf("GetDelivery: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetDelivery: %v", err))
+	}
+	return c.JSON(http.StatusOK, delivery)
+}
@@ -3,7 +3,9 @@ package handlers
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/liza/labwork_45/internal/handlers/mocks"
@@ -15,9 +17,13 @@ import (
 var (
 	mockCourierServiceInterface *mocks.CourierServiceInterface
 
+	testWindowStart = time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)
+	testWindowEnd   = testWindowStart.Add(2 * time.Hour)
+
 	mockDeliveryInstance = &model.Delivery{
 		Id:              uuid.New(),
-		DeliveryDate:    "2024-13-12",
+		WindowStart:     &testWindowStart,
+		WindowEnd:       &testWindowEnd,
 		DeliveryStatus:  "test_delivered",
 		DeliveryComment: "test_comment",
 	}
@@ -36,3 +42,20 @@ func TestCreateDeliveryWithError(t *testing.T) {
 
 	mockCourierServiceInterface.AssertCalled(t, "CreateDelivery", mock.Anything, mockDeliveryInstance)
 }
+
+// TestUpdateCourierKeepsOmittedFields checks that only the sent fields reach the service and the courier is returned with 200
+func TestUpdateCourierKeepsOmittedFields(t *testing.T) {
+	srv := mocks.NewCourierServiceInterface(t)
+	userId := uuid.New()
+	updated := &model.Courier{UserId: userId, Name: "test_name", Surname: "test_surname", Status: model.CourierStatusActive}
+	srv.On("UpdateCourier", mock.Anything, userId, mock.MatchedBy(func(patch *model.CourierPatch) bool {
+		return patch.Name != nil && *patch.Name == "test_name" && patch.Surname == nil && patch.Status == nil
+	})).Return(updated, nil)
+
+	c, rec := newTestContext(t, http.MethodPatch, "/courier/updatecourier", `{"name":"test_name"}`, userId, "Courier")
+	err := NewCourierHandler(srv).UpdateCourier(c)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"surname":"test_surname"`)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type DispatchHandler struct {
+	srv DispatchServiceInterface
+}
+
+func NewDispatchHandler(srv DispatchServiceInterface) *DispatchHandler {
+	return &DispatchHandler{srv: srv}
+}
+
+type DispatchServiceInterface interface {
+	Dispatch(ctx context.Context, deliveryId uuid.UUID) (*model.DispatchResult, error)
+}
+
+// Dispatch runs the dispatcher for one delivery
+// @Summary Dispatch
+// @Description Scores available couriers and assigns or offers the delivery to the best one
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.DeliveryId true "Delivery to dispatch"
+// @Success 200 {object} model.DispatchResult "Dispatch result"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Delivery is already taken or has a pending offer"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/dispatch [post]
+func (h *DispatchHandler) Dispatch(c echo.Context) error {
+	Id := &model.DeliveryId{}
+	err := c.Bind(Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"Id": Id}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	result, err := h.srv.Dispatch(c.Request().Context(), Id.Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"deliveryId": Id.Id}).Errorf("Dispatch: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("Dispatch: %v", err))
+	}
+	return c.JSON(http.StatusOK, result)
+}
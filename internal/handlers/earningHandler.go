@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type EarningHandler struct {
+	srv EarningServiceInterface
+}
+
+func NewEarningHandler(srv EarningServiceInterface) *EarningHandler {
+	return &EarningHandler{srv: srv}
+}
+
+type EarningServiceInterface interface {
+	AdjustEarnings(ctx context.Context, adminId uuid.UUID, adjustment *model.EarningAdjustmentRequest) (*model.Earning, error)
+	GetMyStatements(ctx context.Context, userId uuid.UUID, filter *model.StatementFilter) ([]*model.Statement, error)
+	GetMyStatement(ctx context.Context, userId uuid.UUID, statementId uuid.UUID) (*model.Statement, error)
+	GetStatements(ctx context.Context, filter *model.StatementFilter) ([]*model.Statement, error)
+}
+
+// statementFilter reads the period and the optional from and to query parameters
+func statementFilter(c echo.Context) (*model.StatementFilter, error) {
+	period, err := periodFilter(c)
+	if err != nil {
+		return nil, err
+	}
+	return &model.StatementFilter{Period: c.QueryParam("period"), From: period.From, To: period.To}, nil
+}
+
+// csvText keeps spreadsheets from evaluating free text as a formula by prefixing cells
+// that start with a formula character with an apostrophe
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// statementsCSV writes one row per statement, amounts with two decimals
+func statementsCSV(statements []*model.Statement) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	amount := func(value float64) string { return strconv.FormatFloat(value, 'f', 2, 64) }
+	err := w.Write([]string{"statement_id", "courier_userid", "name", "surname", "period", "period_start", "period_end", "deliveries",
+		"delivery_amount", "bonus_amount", "adjustment_amount", "penalty_amount", "total"})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range statements {
+		err = w.Write([]string{s.Id.String(), s.UserId.String(), csvText(s.Name), csvText(s.Surname), s.Period, s.PeriodStart.Format(time.RFC3339),
+			s.PeriodEnd.Format(time.RFC3339), strconv.Itoa(s.Deliveries), amount(s.DeliveryAmount), amount(s.BonusAmount),
+			amount(s.AdjustmentAmount), amount(s.PenaltyAmount), amount(s.Total)})
+		if err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// GetMyStatements lists the statements of the authorized courier
+// @Summary GetMyStatements
+// @Description Returns the weekly or monthly earnings statements of the authorized courier, newest first
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Produce json
+// @Param period query string false "week (default) or month"
+// @Param from query string false "Only periods starting after this time (RFC 3339)"
+// @Param to query string false "Only periods starting before this time (RFC 3339)"
+// @Success 200 {array} model.Statement "Statements"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/statements [get]
+func (h *EarningHandler) GetMyStatements(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	filter, err := statementFilter(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	statements, err := h.srv.GetMyStatements(c.Request().Context(), userId, filter)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GetMyStatements: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetMyStatements: %v", err))
+	}
+	return c.JSON(http.StatusOK, statements)
+}
+
+// GetMyStatement returns a statement of the authorized courier with its entries
+// @Summary GetMyStatement
+// @Description Returns a statement of the authorized courier with the earnings it sums up
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Statement id"
+// @Success 200 {object} model.Statement "Statement with entries"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Statement not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/statement/{id} [get]
+func (h *EarningHandler) GetMyStatement(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	statementId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	statement, err := h.srv.GetMyStatement(c.Request().Context(), userId, statementId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId, "statementId": statementId}).Errorf("GetMyStatement: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetMyStatement: %v", err))
+	}
+	return c.JSON(http.StatusOK, statement)
+}
+
+// AdjustEarnings books an adjustment or penalty
+// @Summary AdjustEarnings
+// @Description Books a manual adjustment (positive or negative) or a penalty (given as a positive amount) for a courier
+// @Tags Earnings
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.EarningAdjustmentRequest true "Adjustment"
+// @Success 201 {object} model.Earning "Booked earning"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /admin/earning_adjustment [post]
+func (h *EarningHandler) AdjustEarnings(c echo.Context) error {
+	adminId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": adminId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	adjustment := &model.EarningAdjustmentRequest{}
+	err = c.Bind(adjustment)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"adjustment": adjustment}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	earning, err := h.srv.AdjustEarnings(c.Request().Context(), adminId, adjustment)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"adjustment": adjustment}).Errorf("AdjustEarnings: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("AdjustEarnings: %v", err))
+	}
+	return c.JSON(http.StatusCreated, earning)
+}
+
+// GetStatements lists statements of all couriers
+// @Summary GetStatements
+// @Description Returns the weekly or monthly statements of all couriers, newest period first
+// @Tags Earnings
+// @Security ApiKeyAuth
+// @Produce json
+// @Param period query string false "week (default) or month"
+// @Param from query string false "Only periods starting after this time (RFC 3339)"
+// @Param to query string false "Only periods starting before this time (RFC 3339)"
+// @Success 200 {array} model.Statement "Statements"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /admin/statements [get]
+func (h *EarningHandler) GetStatements(c echo.Context) error {
+	filter, err := statementFilter(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	statements, err := h.srv.GetStatements(c.Request().Context(), filter)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"filter": filter}).Errorf("GetStatements: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetStatements: %v", err))
+	}
+	return c.JSON(http.StatusOK, statements)
+}
+
+// ExportStatements exports statements of all couriers as CSV
+// @Summary ExportStatements
+// @Description Returns the weekly or monthly statements of all couriers as a CSV file for payouts
+// @Tags Earnings
+// @Security ApiKeyAuth
+// @Produce text/csv
+// @Param period query string false "week (default) or month"
+// @Param from query string false "Only periods starting after this time (RFC 3339)"
+// @Param to query string false "Only periods starting before this time (RFC 3339)"
+// @Success 200 {file} file "Statements"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /admin/statements_csv [get]
+func (h *EarningHandler) ExportStatements(c echo.Context) error {
+	filter, err := statementFilter(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	statements, err := h.srv.GetStatements(c.Request().Context(), filter)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"filter": filter}).Errorf("GetStatements: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetStatements: %v", err))
+	}
+	data, err := statementsCSV(statements)
+	if err != nil {
+		logrus.Errorf("statementsCSV: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("statementsCSV: %v", err))
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=statements-%s.csv", filter.Period))
+	return c.Blob(http.StatusOK, "text/csv", data)
+}
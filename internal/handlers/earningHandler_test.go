@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStatementsCSVEscapesFormulas checks that courier names can not inject spreadsheet formulas
+func TestStatementsCSVEscapesFormulas(t *testing.T) {
+	data, err := statementsCSV([]*model.Statement{{Id: uuid.New(), Name: "=HYPERLINK(\"http://x\")", Surname: "-Smith"}})
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"'=HYPERLINK(""http://x"")",'-Smith`)
+	require.Equal(t, "Anna", csvText("Anna"))
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// heartbeatInterval keeps idle streams open through proxies
+const heartbeatInterval = 15 * time.Second
+
+type EventHandler struct {
+	srv EventServiceInterface
+}
+
+func NewEventHandler(srv EventServiceInterface) *EventHandler {
+	return &EventHandler{srv: srv}
+}
+
+type EventServiceInterface interface {
+	Subscribe(ctx context.Context, filter *model.EventFilter) (<-chan *model.DeliveryEvent, func(), error)
+	IssueTicket(ctx context.Context, userId uuid.UUID, role string) (*model.EventTicket, error)
+}
+
+// IssueTicket returns a ticket that opens the event stream
+// @Summary IssueTicket
+// @Description Returns a ticket that opens the event stream of the caller in the ticket query parameter, for clients such as browser EventSource that can not set headers. The ticket expires after a minute and is not accepted as an access token
+// @Tags Events
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} model.EventTicket "Ticket"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /events/ticket [post]
+func (h *EventHandler) IssueTicket(c echo.Context) error {
+	token := strings.Split(c.Request().Header.Get("Authorization"), " ")[1]
+	userId, err := middleware.GetPayloadFromToken(token)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	role, err := middleware.GetRoleFromToken(token)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetRoleFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetRoleFromToken: %v", err))
+	}
+	ticket, err := h.srv.IssueTicket(c.Request().Context(), userId, role)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("IssueTicket: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("IssueTicket: %v", err))
+	}
+	return c.JSON(http.StatusOK, ticket)
+}
+
+// StreamEvents streams delivery updates as Server-Sent Events
+// @Summary StreamEvents
+// @Description Streams status changes, assignments and courier positions of the deliveries the caller may see: managers and admins see all, clients their own orders and couriers their assigned deliveries. Clients that can not set headers pass a ticket from /events/ticket in the ticket query parameter instead
+// @Tags Events
+// @Security ApiKeyAuth
+// @Produce text/event-stream
+// @Param ticket query string false "Ticket for clients that can not set headers"
+// @Success 200 {object} model.DeliveryEvent "Stream of events"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /events [get]
+func (h *EventHandler) StreamEvents(c echo.Context) error {
+	token := strings.Split(c.Request().Header.Get("Authorization"), " ")[1]
+	userId, err := middleware.GetPayloadFromToken(token)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	role, err := middleware.GetRoleFromToken(token)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetRoleFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetRoleFromToken: %v", err))
+	}
+	filter := &model.EventFilter{}
+	switch role {
+	case middleware.Manager, middleware.Admin:
+		filter.All = true
+	case middleware.Client:
+		filter.ClientId = &userId
+	case middleware.Courier:
+		filter.CourierUserId = &userId
+	}
+
+	ctx := c.Request().Context()
+	events, unsubscribe, err := h.srv.Subscribe(ctx, filter)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("Subscribe: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("Subscribe: %v", err))
+	}
+	defer unsubscribe()
+
+	response := c.Response()
+	response.Header().Set(echo.HeaderContentType, "text/event-stream")
+	response.Header().Set(echo.HeaderCacheControl, "no-cache")
+	response.Header().Set(echo.HeaderConnection, "keep-alive")
+	response.Header().Set("X-Accel-Buffering", "no")
+	response.WriteHeader(http.StatusOK)
+	response.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(response, ": ping\n\n")
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			var data []byte
+			data, err = json.Marshal(event)
+			if err != nil {
+				logrus.WithFields(logrus.Fields{"deliveryId": event.DeliveryId}).Errorf("Marshal: %v", err)
+				continue
+			}
+			_, err = fmt.Fprintf(response, "event: %s\ndata: %s\n\n", event.Type, data)
+		}
+		if err != nil {
+			logrus.WithFields(logrus.Fields{"userId": userId}).Debugf("StreamEvents: %v", err)
+			return nil
+		}
+		response.Flush()
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/handlers/mocks"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStreamEventsForClient checks that a client subscribes to their own deliveries and gets SSE frames
+func TestStreamEventsForClient(t *testing.T) {
+	srv := mocks.NewEventServiceInterface(t)
+	clientId := uuid.New()
+	events := make(chan *model.DeliveryEvent, 1)
+	events <- &model.DeliveryEvent{Type: model.DeliveryEventStatus, DeliveryId: uuid.New(), ClientId: &clientId, Status: model.DeliveryStatusPickedUp}
+	close(events)
+	srv.On("Subscribe", mock.Anything, mock.MatchedBy(func(filter *model.EventFilter) bool {
+		return !filter.All && filter.ClientId != nil && *filter.ClientId == clientId
+	})).Return((<-chan *model.DeliveryEvent)(events), func() {}, nil)
+
+	c, rec := newTestContext(t, http.MethodGet, "/events", "", clientId, "Client")
+	err := NewEventHandler(srv).StreamEvents(c)
+
+	require.NoError(t, err)
+	require.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	require.Contains(t, rec.Body.String(), "event: status\ndata: ")
+	require.Contains(t, rec.Body.String(), `"status":"picked_up"`)
+}
+
+// TestIssueTicket checks that the ticket is issued for the caller's user and role
+func TestIssueTicket(t *testing.T) {
+	srv := mocks.NewEventServiceInterface(t)
+	courierUserId := uuid.New()
+	srv.On("IssueTicket", mock.Anything, courierUserId, "Courier").Return(&model.EventTicket{Ticket: "test_ticket"}, nil)
+
+	c, rec := newTestContext(t, http.MethodPost, "/events/ticket", "", courierUserId, "Courier")
+	err := NewEventHandler(srv).IssueTicket(c)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"ticket":"test_ticket"`)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTimeQueryParam checks that only RFC 3339 values with an offset are accepted and converted to UTC
+func TestTimeQueryParam(t *testing.T) {
+	cases := []struct {
+		value string
+		want  *time.Time
+		valid bool
+	}{
+		{"", nil, true},
+		{"2024-12-13T09:00:00Z", ptrTime(time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)), true},
+		{"2024-12-13T12:00:00+03:00", ptrTime(time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)), true},
+		{"2024-12-13T09:00:00.5-01:30", ptrTime(time.Date(2024, time.December, 13, 10, 30, 0, 500_000_000, time.UTC)), true},
+		{"2024-12-13T09:00:00", nil, false},
+		{"2024-12-13", nil, false},
+		{"13.12.2024 09:00", nil, false},
+		{"2024-13-01T09:00:00Z", nil, false},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/courier/getalldeliveries?from="+url.QueryEscape(tc.value), nil)
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+		got, err := timeQueryParam(c, "from")
+		if !tc.valid {
+			require.Error(t, err, tc.value)
+			continue
+		}
+		require.NoError(t, err, tc.value)
+		require.Equal(t, tc.want, got, tc.value)
+		if got != nil {
+			require.Equal(t, time.UTC, got.Location(), tc.value)
+		}
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
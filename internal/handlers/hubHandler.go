@@ -0,0 +1,394 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type HubHandler struct {
+	srv HubServiceInterface
+}
+
+func NewHubHandler(srv HubServiceInterface) *HubHandler {
+	return &HubHandler{srv: srv}
+}
+
+type HubServiceInterface interface {
+	CreateHub(ctx context.Context, hub *model.Hub) (*model.Hub, error)
+	GetHubs(ctx context.Context) ([]*model.Hub, error)
+	SetHubStaff(ctx context.Context, staff *model.HubStaff) error
+	RouteDelivery(ctx context.Context, routing *model.DeliveryRouting) (*model.DeliveryLegs, error)
+	GetDeliveryLegs(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryLegs, error)
+	AssignLeg(ctx context.Context, assignment *model.LegAssignment) (*model.DeliveryLeg, error)
+	ClaimLeg(ctx context.Context, userId uuid.UUID, legId uuid.UUID) (*model.DeliveryLeg, error)
+	GetCourierLegs(ctx context.Context, userId uuid.UUID) ([]*model.DeliveryLeg, error)
+	PickUpLeg(ctx context.Context, userId uuid.UUID, legId uuid.UUID) (*model.DeliveryLeg, error)
+	CheckIn(ctx context.Context, userId uuid.UUID, deliveryId uuid.UUID) (*model.DeliveryLeg, error)
+	CheckOut(ctx context.Context, userId uuid.UUID, deliveryId uuid.UUID) (*model.DeliveryLeg, error)
+	GetHubInventory(ctx context.Context, userId uuid.UUID) ([]*model.DeliveryLeg, error)
+}
+
+// CreateHub adds a hub
+// @Summary CreateHub
+// @Description Adds an active hub where parcels of multi-leg deliveries change couriers
+// @Tags Hubs
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.Hub true "Hub"
+// @Success 201 {object} model.Hub "Created hub"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "Hub code is taken"
+// @Failure 500 {string} string "Internal server error"
+// @Router /admin/hub [post]
+func (h *HubHandler) CreateHub(c echo.Context) error {
+	hub := &model.Hub{}
+	err := c.Bind(hub)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"hub": hub}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	hub, err = h.srv.CreateHub(c.Request().Context(), hub)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"hub": hub}).Errorf("CreateHub: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("CreateHub: %v", err))
+	}
+	return c.JSON(http.StatusCreated, hub)
+}
+
+// GetHubs lists hubs
+// @Summary GetHubs
+// @Description Returns every hub including inactive ones
+// @Tags Hubs
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} model.Hub "Hubs"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /admin/hubs [get]
+func (h *HubHandler) GetHubs(c echo.Context) error {
+	hubs, err := h.srv.GetHubs(c.Request().Context())
+	if err != nil {
+		logrus.Errorf("GetHubs: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetHubs: %v", err))
+	}
+	return c.JSON(http.StatusOK, hubs)
+}
+
+// SetHubStaff assigns a user to a hub
+// @Summary SetHubStaff
+// @Description Assigns a user with the HubStaff role to the hub they scan parcels at, replacing their previous hub
+// @Tags Hubs
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.HubStaff true "Hub staff"
+// @Success 200 {string} string "Staff has been assigned"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Hub not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /admin/hub_staff [put]
+func (h *HubHandler) SetHubStaff(c echo.Context) error {
+	staff := &model.HubStaff{}
+	err := c.Bind(staff)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"staff": staff}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	err = h.srv.SetHubStaff(c.Request().Context(), staff)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"staff": staff}).Errorf("SetHubStaff: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("SetHubStaff: %v", err))
+	}
+	return c.JSON(http.StatusOK, "Staff has been assigned")
+}
+
+// RouteDelivery splits a delivery into legs
+// @Summary RouteDelivery
+// @Description Splits a new unassigned delivery into legs through the given hubs, in order. Each leg gets its own courier
+// @Description and the delivery status is derived from the legs from then on
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.DeliveryRouting true "Routing"
+// @Success 201 {object} model.DeliveryLegs "Legs"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Delivery or hub not found"
+// @Failure 409 {string} string "Delivery is assigned, picked up or already routed"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/delivery_route [post]
+func (h *HubHandler) RouteDelivery(c echo.Context) error {
+	routing := &model.DeliveryRouting{}
+	err := c.Bind(routing)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"routing": routing}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	legs, err := h.srv.RouteDelivery(c.Request().Context(), routing)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"routing": routing}).Errorf("RouteDelivery: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("RouteDelivery: %v", err))
+	}
+	return c.JSON(http.StatusCreated, legs)
+}
+
+// GetDeliveryLegs returns the legs of a delivery
+// @Summary GetDeliveryLegs
+// @Description Returns the legs of a multi-leg delivery with the state derived from them
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Delivery id"
+// @Success 200 {object} model.DeliveryLegs "Legs"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Delivery not found or not routed"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/delivery_legs/{id} [get]
+func (h *HubHandler) GetDeliveryLegs(c echo.Context) error {
+	deliveryId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	legs, err := h.srv.GetDeliveryLegs(c.Request().Context(), deliveryId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"deliveryId": deliveryId}).Errorf("GetDeliveryLegs: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetDeliveryLegs: %v", err))
+	}
+	return c.JSON(http.StatusOK, legs)
+}
+
+// AssignLeg assigns a leg to a courier
+// @Summary AssignLeg
+// @Description Assigns or reassigns a leg that has not been picked up yet to a courier that is not suspended
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.LegAssignment true "Assignment"
+// @Success 200 {object} model.DeliveryLeg "Assigned leg"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Leg or courier not found"
+// @Failure 409 {string} string "Leg is picked up or courier is suspended"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/assign_leg [patch]
+func (h *HubHandler) AssignLeg(c echo.Context) error {
+	assignment := &model.LegAssignment{}
+	err := c.Bind(assignment)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"assignment": assignment}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	leg, err := h.srv.AssignLeg(c.Request().Context(), assignment)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"assignment": assignment}).Errorf("AssignLeg: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("AssignLeg: %v", err))
+	}
+	return c.JSON(http.StatusOK, leg)
+}
+
+// GetMyLegs lists the legs of the courier
+// @Summary GetMyLegs
+// @Description Returns unfinished legs assigned to the authorized courier and free legs they can claim
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} model.DeliveryLeg "Legs"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/legs [get]
+func (h *HubHandler) GetMyLegs(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	legs, err := h.srv.GetCourierLegs(c.Request().Context(), userId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GetCourierLegs: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetCourierLegs: %v", err))
+	}
+	return c.JSON(http.StatusOK, legs)
+}
+
+// ClaimLeg takes a free leg
+// @Summary ClaimLeg
+// @Description Assigns a free leg to the authorized active courier
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.LegId true "Leg"
+// @Success 200 {object} model.DeliveryLeg "Claimed leg"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Leg not found"
+// @Failure 409 {string} string "Leg is already taken or courier is not active"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/claim_leg [patch]
+func (h *HubHandler) ClaimLeg(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	Id := &model.LegId{}
+	err = c.Bind(Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"Id": Id}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	leg, err := h.srv.ClaimLeg(c.Request().Context(), userId, Id.Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId, "legId": Id.Id}).Errorf("ClaimLeg: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("ClaimLeg: %v", err))
+	}
+	return c.JSON(http.StatusOK, leg)
+}
+
+// PickUpLeg picks the parcel up at the pickup address
+// @Summary PickUpLeg
+// @Description Marks the first leg of the authorized courier picked up at the pickup address,
+// @Description parcels are handed out at hubs with /hub/check_out
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.LegId true "Leg"
+// @Success 200 {object} model.DeliveryLeg "Picked up leg"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Leg not found"
+// @Failure 409 {string} string "Leg is not the courier's first leg or already picked up"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/pickup_leg [patch]
+func (h *HubHandler) PickUpLeg(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	Id := &model.LegId{}
+	err = c.Bind(Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"Id": Id}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	leg, err := h.srv.PickUpLeg(c.Request().Context(), userId, Id.Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId, "legId": Id.Id}).Errorf("PickUpLeg: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("PickUpLeg: %v", err))
+	}
+	return c.JSON(http.StatusOK, leg)
+}
+
+// CheckIn scans a parcel arriving at the hub
+// @Summary CheckIn
+// @Description Completes the picked up leg ending at the staff's hub, the parcel then waits for the next leg
+// @Tags Hubs
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.DeliveryId true "Delivery"
+// @Success 200 {object} model.DeliveryLeg "Completed leg"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "User is not assigned to a hub"
+// @Failure 404 {string} string "Delivery not found"
+// @Failure 409 {string} string "Parcel is not on its way to the hub"
+// @Failure 500 {string} string "Internal server error"
+// @Router /hub/check_in [post]
+func (h *HubHandler) CheckIn(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	Id := &model.DeliveryId{}
+	err = c.Bind(Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"Id": Id}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	leg, err := h.srv.CheckIn(c.Request().Context(), userId, Id.Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId, "deliveryId": Id.Id}).Errorf("CheckIn: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("CheckIn: %v", err))
+	}
+	return c.JSON(http.StatusOK, leg)
+}
+
+// CheckOut scans a parcel leaving the hub
+// @Summary CheckOut
+// @Description Hands a parcel waiting at the staff's hub to the courier of the next leg, the leg is picked up
+// @Tags Hubs
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.DeliveryId true "Delivery"
+// @Success 200 {object} model.DeliveryLeg "Picked up leg"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "User is not assigned to a hub"
+// @Failure 404 {string} string "Delivery not found"
+// @Failure 409 {string} string "Parcel is not at the hub or the next leg has no courier"
+// @Failure 500 {string} string "Internal server error"
+// @Router /hub/check_out [post]
+func (h *HubHandler) CheckOut(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	Id := &model.DeliveryId{}
+	err = c.Bind(Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"Id": Id}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	leg, err := h.srv.CheckOut(c.Request().Context(), userId, Id.Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId, "deliveryId": Id.Id}).Errorf("CheckOut: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("CheckOut: %v", err))
+	}
+	return c.JSON(http.StatusOK, leg)
+}
+
+// GetHubInventory lists parcels waiting at the hub
+// @Summary GetHubInventory
+// @Description Returns the next legs of parcels checked in at the staff's hub and not checked out yet
+// @Tags Hubs
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} model.DeliveryLeg "Waiting legs"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "User is not assigned to a hub"
+// @Failure 500 {string} string "Internal server error"
+// @Router /hub/inventory [get]
+func (h *HubHandler) GetHubInventory(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	legs, err := h.srv.GetHubInventory(c.Request().Context(), userId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GetHubInventory: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetHubInventory: %v", err))
+	}
+	return c.JSON(http.StatusOK, legs)
+}
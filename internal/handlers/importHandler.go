@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// maxImportFileSize limits uploaded spreadsheets
+const maxImportFileSize = 10 << 20
+
+type ImportHandler struct {
+	srv ImportServiceInterface
+}
+
+func NewImportHandler(srv ImportServiceInterface) *ImportHandler {
+	return &ImportHandler{srv: srv}
+}
+
+type ImportServiceInterface interface {
+	ImportDeliveries(ctx context.Context, upload *model.DeliveryImport) (*model.ImportReport, error)
+}
+
+// bindImport reads the multipart import form, the format defaults to the extension of the uploaded file
+func bindImport(c echo.Context) (*model.DeliveryImport, error) {
+	upload := &model.DeliveryImport{Format: strings.ToLower(c.FormValue("format"))}
+	var err error
+	if value := c.FormValue("dry_run"); value != "" {
+		upload.DryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("dry_run: %w", err)
+		}
+	}
+	if value := c.FormValue("client_id"); value != "" {
+		clientId, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("client_id: %w", err)
+		}
+		upload.ClientId = &clientId
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("file: %w", err)
+	}
+	if header.Size > maxImportFileSize {
+		return nil, fmt.Errorf("file: larger than %d bytes", maxImportFileSize)
+	}
+	if upload.Format == "" {
+		upload.Format = strings.ToLower(strings.TrimPrefix(filepath.Ext(header.Filename), "."))
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("file: %w", err)
+	}
+	defer file.Close()
+	upload.Data, err = io.ReadAll(io.LimitReader(file, maxImportFileSize))
+	if err != nil {
+		return nil, fmt.Errorf("file: %w", err)
+	}
+	return upload, nil
+}
+
+// ImportDeliveries creates deliveries from a spreadsheet
+// @Summary ImportDeliveries
+// @Description Creates deliveries from a CSV or XLSX file (first sheet), one delivery per row below a header row.
+// @Description Columns: pickup_address_line1, pickup_address_line2, pickup_city, pickup_postcode, pickup_lat, pickup_lon,
+// @Description the same for dropoff, recipient_name, recipient_phone, access_notes, window_start, window_end (RFC 3339 or
+// @Description YYYY-MM-DD HH:MM in UTC), weight_kg, express, cod_amount, delivery_comment, promo_code and client_id, which
+// @Description defaults to the client_id of the form. Every row is validated and priced, the report lists problems per row
+// @Description and column, including rows past the uses left of their promo code. Valid rows are inserted together and
+// @Description rows with problems are skipped, with dry_run nothing is saved. The report gives the id, tracking code and
+// @Description recipient PIN of every inserted delivery, PINs are not shown on the tracking page
+// @Tags Import
+// @Security ApiKeyAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or XLSX file"
+// @Param format formData string false "csv or xlsx, defaults to the file extension"
+// @Param client_id formData string false "Client of the rows without a client_id"
+// @Param dry_run formData boolean false "Only validate and price the rows"
+// @Success 200 {object} model.ImportReport "Dry run or nothing inserted"
+// @Success 201 {object} model.ImportReport "Valid rows inserted"
+// @Failure 400 {string} string "Bad request, unreadable file or header, unknown client"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "A promo code was used up while importing"
+// @Failure 500 {string} string "Internal server error"
+// @Router /delivery/import [post]
+func (h *ImportHandler) ImportDeliveries(c echo.Context) error {
+	upload, err := bindImport(c)
+	if err != nil {
+		logrus.Errorf("bindImport: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	report, err := h.srv.ImportDeliveries(c.Request().Context(), upload)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"format": upload.Format, "dryRun": upload.DryRun}).Errorf("ImportDeliveries: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("ImportDeliveries: %v", err))
+	}
+	if report.Inserted > 0 {
+		return c.JSON(http.StatusCreated, report)
+	}
+	return c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type ManagerHandler struct {
+	srv ManagerServiceInterface
+}
+
+func NewManagerHandler(srv ManagerServiceInterface) *ManagerHandler {
+	return &ManagerHandler{srv: srv}
+}
+
+type ManagerServiceInterface interface {
+	GetCourierRoster(ctx context.Context) ([]*model.CourierLoad, error)
+	AssignDelivery(ctx context.Context, assignment *model.DeliveryAssignment) error
+	UnassignDelivery(ctx context.Context, deliveryId uuid.UUID) error
+	SetCourierStatus(ctx context.Context, update *model.CourierStatusUpdate) error
+	UpdateCourier(ctx context.Context, patch *model.CourierManagerPatch) (*model.Courier, error)
+	CancelDelivery(ctx context.Context, managerId uuid.UUID, request *model.ManagerCancelRequest) (*model.Cancellation, error)
+	GetCancellation(ctx context.Context, deliveryId uuid.UUID) (*model.Cancellation, error)
+}
+
+// GetCouriers returns every courier with their current load
+// @Summary GetCouriers
+// @Description Returns the courier roster with status and number of active deliveries
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} model.CourierLoad "Courier roster"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/couriers [get]
+func (h *ManagerHandler) GetCouriers(c echo.Context) error {
+	couriers, err := h.srv.GetCourierRoster(c.Request().Context())
+	if err != nil {
+		logrus.Errorf("GetCourierRoster: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetCourierRoster: %v", err))
+	}
+	return c.JSON(http.StatusOK, couriers)
+}
+
+// AssignDelivery assigns or reassigns a delivery to a courier
+// @Summary AssignDelivery
+// @Description Assigns a delivery that is not picked up yet to the courier with the given user id, replacing the current courier
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.DeliveryAssignment true "Assignment"
+// @Success 200 {string} string "Delivery has been assigned"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Delivery is picked up or closed, or courier is suspended"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/assign_delivery [patch]
+func (h *ManagerHandler) AssignDelivery(c echo.Context) error {
+	assignment := &model.DeliveryAssignment{}
+	err := c.Bind(assignment)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"assignment": assignment}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	err = h.srv.AssignDelivery(c.Request().Context(), assignment)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"assignment": assignment}).Errorf("AssignDelivery: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("AssignDelivery: %v", err))
+	}
+	return c.JSON(http.StatusOK, "Delivery has been assigned")
+}
+
+// UnassignDelivery removes the courier from a delivery
+// @Summary UnassignDelivery
+// @Description Returns a delivery that is not picked up yet to the pool of available deliveries
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.DeliveryId true "Delivery"
+// @Success 200 {string} string "Delivery has been unassigned"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Delivery is picked up or closed"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/unassign_delivery [patch]
+func (h *ManagerHandler) UnassignDelivery(c echo.Context) error {
+	Id := &model.DeliveryId{}
+	err := c.Bind(Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"Id": Id}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	err = h.srv.UnassignDelivery(c.Request().Context(), Id.Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"deliveryId": Id.Id}).Errorf("UnassignDelivery: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("UnassignDelivery: %v", err))
+	}
+	return c.JSON(http.StatusOK, "Delivery has been unassigned")
+}
+
+// SetCourierStatus changes the status of a courier
+// @Summary SetCourierStatus
+// @Description Sets courier status to active, suspended or off_shift
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.CourierStatusUpdate true "Courier status"
+// @Success 200 {string} string "Courier status has been changed"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/courier_status [patch]
+func (h *ManagerHandler) SetCourierStatus(c echo.Context) error {
+	update := &model.CourierStatusUpdate{}
+	err := c.Bind(update)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"update": update}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	err = h.srv.SetCourierStatus(c.Request().Context(), update)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"update": update}).Errorf("SetCourierStatus: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("SetCourierStatus: %v", err))
+	}
+	return c.JSON(http.StatusOK, "Courier status has been changed")
+}
+
+// UpdateCourier updates the fields present in the request
+// @Summary UpdateCourier
+// @Description Updates name, surname and status of the courier with the given user id, omitted fields are kept
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.CourierManagerPatch true "Fields to update"
+// @Success 200 {object} model.Courier "Updated courier"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Courier has changed meanwhile"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/courier [patch]
+func (h *ManagerHandler) UpdateCourier(c echo.Context) error {
+	patch := &model.CourierManagerPatch{}
+	err := c.Bind(patch)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"patch": patch}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	courier, err := h.srv.UpdateCourier(c.Request().Context(), patch)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": patch.UserId}).Errorf("UpdateCourier: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("UpdateCourier: %v", err))
+	}
+	return c.JSON(http.StatusOK, courier)
+}
+
+// CancelDelivery cancels a delivery at any stage
+// @Summary CancelDelivery
+// @Description Cancels an open delivery and unassigns its courier, who is notified through the event stream.
+// @Description The fee for the current state (created, assigned, picked_up) is charged unless waive_fee is set
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.ManagerCancelRequest true "Delivery to cancel and reason"
+// @Success 200 {object} model.Cancellation "Cancellation with the fee charged"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Delivery is closed or has changed meanwhile"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/cancel_delivery [patch]
+func (h *ManagerHandler) CancelDelivery(c echo.Context) error {
+	managerId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": managerId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	request := &model.ManagerCancelRequest{}
+	err = c.Bind(request)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"request": request}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	cancellation, err := h.srv.CancelDelivery(c.Request().Context(), managerId, request)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"managerId": managerId, "deliveryId": request.Id}).Errorf("CancelDelivery: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("CancelDelivery: %v", err))
+	}
+	return c.JSON(http.StatusOK, cancellation)
+}
+
+// GetCancellation returns who cancelled a delivery and why
+// @Summary GetCancellation
+// @Description Returns the cancellation record of a delivery
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Delivery id"
+// @Success 200 {object} model.Cancellation "Cancellation"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Delivery was not cancelled"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/delivery_cancellation/{id} [get]
+func (h *ManagerHandler) GetCancellation(c echo.Context) error {
+	deliveryId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	cancellation, err := h.srv.GetCancellation(c.Request().Context(), deliveryId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"deliveryId": deliveryId}).Errorf("GetCancellation: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetCancellation: %v", err))
+	}
+	return c.JSON(http.StatusOK, cancellation)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/handlers/mocks"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAssignDeliveryToSuspendedCourier checks that service conflicts are returned as 409
+func TestAssignDeliveryToSuspendedCourier(t *testing.T) {
+	srv := mocks.NewManagerServiceInterface(t)
+	srv.On("AssignDelivery", mock.Anything, mock.AnythingOfType("*model.DeliveryAssignment")).
+		Return(fmt.Errorf("AssignDelivery: %w: courier is suspended", model.ErrConflict))
+
+	body := fmt.Sprintf(`{"delivery_id":"%s","courier_userid":"%s"}`, uuid.New(), uuid.New())
+	c, _ := newTestContext(t, http.MethodPatch, "/manager/assign_delivery", body, uuid.New(), "Manager")
+	err := NewManagerHandler(srv).AssignDelivery(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	require.True(t, ok)
+	require.Equal(t, http.StatusConflict, httpErr.Code)
+}
+
+// TestGetCouriers checks that the roster is returned as is
+func TestGetCouriers(t *testing.T) {
+	srv := mocks.NewManagerServiceInterface(t)
+	roster := []*model.CourierLoad{{Courier: model.Courier{Name: "test_name", Status: model.CourierStatusActive}, ActiveDeliveries: 2}}
+	srv.On("GetCourierRoster", mock.Anything).Return(roster, nil)
+
+	c, rec := newTestContext(t, http.MethodGet, "/manager/couriers", "", uuid.New(), "Manager")
+	err := NewManagerHandler(srv).GetCouriers(c)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"active_deliveries":2`)
+}
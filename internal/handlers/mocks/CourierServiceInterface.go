@@ -53,9 +53,9 @@ func (_m *CourierServiceInterface) CreateDelivery(_a0 context.Context, _a1 *mode
 	return r0
 }
 
-// GetAllDeliveries provides a mock function with given fields: _a0
-func (_m *CourierServiceInterface) GetAllDeliveries(_a0 context.Context) ([]*model.DeliveryGet, error) {
-	ret := _m.Called(_a0)
+// GetAllDeliveries provides a mock function with given fields: _a0, _a1
+func (_m *CourierServiceInterface) GetAllDeliveries(_a0 context.Context, _a1 *model.DeliveryFilter) ([]*model.DeliveryGet, error) {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllDeliveries")
@@ -63,19 +63,49 @@ func (_m *CourierServiceInterface) GetAllDeliveries(_a0 context.Context) ([]*mod
 
 	var r0 []*model.DeliveryGet
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.DeliveryGet, error)); ok {
-		return rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DeliveryFilter) ([]*model.DeliveryGet, error)); ok {
+		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []*model.DeliveryGet); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DeliveryFilter) []*model.DeliveryGet); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.DeliveryGet)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, *model.DeliveryFilter) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDelivery provides a mock function with given fields: ctx, userId, deliveryId
+func (_m *CourierServiceInterface) GetDelivery(ctx context.Context, userId uuid.UUID, deliveryId uuid.UUID) (*model.DeliveryGet, error) {
+	ret := _m.Called(ctx, userId, deliveryId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDelivery")
+	}
+
+	var r0 *model.DeliveryGet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (*model.DeliveryGet, error)); ok {
+		return rf(ctx, userId, deliveryId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) *model.DeliveryGet); ok {
+		r0 = rf(ctx, userId, deliveryId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.DeliveryGet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, userId, deliveryId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -84,34 +114,46 @@ func (_m *CourierServiceInterface) GetAllDeliveries(_a0 context.Context) ([]*mod
 }
 
 // UpdateCourier provides a mock function with given fields: _a0, _a1, _a2
-func (_m *CourierServiceInterface) UpdateCourier(_a0 context.Context, _a1 uuid.UUID, _a2 *model.Courier) error {
+func (_m *CourierServiceInterface) UpdateCourier(_a0 context.Context, _a1 uuid.UUID, _a2 *model.CourierPatch) (*model.Courier, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateCourier")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *model.Courier) error); ok {
+	var r0 *model.Courier
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *model.CourierPatch) (*model.Courier, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *model.CourierPatch) *model.Courier); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Courier)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *model.CourierPatch) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// UpdateDeliveryStatus provides a mock function with given fields: _a0, _a1
-func (_m *CourierServiceInterface) UpdateDeliveryStatus(_a0 context.Context, _a1 *model.DeliveryStatus) error {
-	ret := _m.Called(_a0, _a1)
+// UpdateDeliveryStatus provides a mock function with given fields: ctx, userId, delivery
+func (_m *CourierServiceInterface) UpdateDeliveryStatus(ctx context.Context, userId uuid.UUID, delivery *model.DeliveryStatus) error {
+	ret := _m.Called(ctx, userId, delivery)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateDeliveryStatus")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *model.DeliveryStatus) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *model.DeliveryStatus) error); ok {
+		r0 = rf(ctx, userId, delivery)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type OfferHandler struct {
+	srv OfferServiceInterface
+}
+
+func NewOfferHandler(srv OfferServiceInterface) *OfferHandler {
+	return &OfferHandler{srv: srv}
+}
+
+type OfferServiceInterface interface {
+	GetPendingOffers(ctx context.Context, userId uuid.UUID) ([]*model.OfferView, error)
+	AcceptOffer(ctx context.Context, userId uuid.UUID, offerId uuid.UUID) error
+	DeclineOffer(ctx context.Context, userId uuid.UUID, offerId uuid.UUID) error
+	GetOfferMetrics(ctx context.Context) ([]*model.OfferMetrics, error)
+}
+
+// GetOffers returns pending offers of the courier
+// @Summary GetOffers
+// @Description Returns delivery offers waiting for the authorized courier's answer
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} model.OfferView "Pending offers"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/offers [get]
+func (h *OfferHandler) GetOffers(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	offers, err := h.srv.GetPendingOffers(c.Request().Context(), userId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GetPendingOffers: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetPendingOffers: %v", err))
+	}
+	return c.JSON(http.StatusOK, offers)
+}
+
+// AcceptOffer takes the offered delivery
+// @Summary AcceptOffer
+// @Description Accepts a pending offer, the delivery is assigned to the authorized courier
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.OfferId true "Offer"
+// @Success 200 {string} string "Offer has been accepted"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "Offer expired or delivery is already taken"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/accept_offer [patch]
+func (h *OfferHandler) AcceptOffer(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	Id := &model.OfferId{}
+	err = c.Bind(Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"Id": Id}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	err = h.srv.AcceptOffer(c.Request().Context(), userId, Id.Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId, "offerId": Id.Id}).Errorf("AcceptOffer: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("AcceptOffer: %v", err))
+	}
+	return c.JSON(http.StatusOK, "Offer has been accepted")
+}
+
+// DeclineOffer refuses the offered delivery
+// @Summary DeclineOffer
+// @Description Declines a pending offer, the delivery is offered to the next courier
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.OfferId true "Offer"
+// @Success 200 {string} string "Offer has been declined"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "Offer is not pending"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/decline_offer [patch]
+func (h *OfferHandler) DeclineOffer(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	Id := &model.OfferId{}
+	err = c.Bind(Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"Id": Id}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	err = h.srv.DeclineOffer(c.Request().Context(), userId, Id.Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId, "offerId": Id.Id}).Errorf("DeclineOffer: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("DeclineOffer: %v", err))
+	}
+	return c.JSON(http.StatusOK, "Offer has been declined")
+}
+
+// GetOfferMetrics returns offer acceptance statistics per courier
+// @Summary GetOfferMetrics
+// @Description Returns offered, accepted, declined and expired counts and acceptance rate per courier for the last 30 days
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} model.OfferMetrics "Offer metrics"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/offer_metrics [get]
+func (h *OfferHandler) GetOfferMetrics(c echo.Context) error {
+	metrics, err := h.srv.GetOfferMetrics(c.Request().Context())
+	if err != nil {
+		logrus.Errorf("GetOfferMetrics: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetOfferMetrics: %v", err))
+	}
+	return c.JSON(http.StatusOK, metrics)
+}
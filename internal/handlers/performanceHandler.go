@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type PerformanceHandler struct {
+	srv PerformanceServiceInterface
+}
+
+func NewPerformanceHandler(srv PerformanceServiceInterface) *PerformanceHandler {
+	return &PerformanceHandler{srv: srv}
+}
+
+type PerformanceServiceInterface interface {
+	GetPerformance(ctx context.Context, userId uuid.UUID) (*model.PerformanceBreakdown, error)
+}
+
+// GetMyPerformance returns how the performance indicator of the courier was derived
+// @Summary GetMyPerformance
+// @Description Returns the performance indicator of the authorized courier with on-time, failed, acceptance and rating components
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} model.PerformanceBreakdown "Performance breakdown"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not calculated yet"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/performance [get]
+func (h *PerformanceHandler) GetMyPerformance(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	breakdown, err := h.srv.GetPerformance(c.Request().Context(), userId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GetPerformance: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetPerformance: %v", err))
+	}
+	return c.JSON(http.StatusOK, breakdown)
+}
+
+// GetCourierPerformance returns how the performance indicator of a courier was derived
+// @Summary GetCourierPerformance
+// @Description Returns the performance breakdown of the courier with the given user id
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Param userid path string true "Courier user id"
+// @Success 200 {object} model.PerformanceBreakdown "Performance breakdown"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/courier_performance/{userid} [get]
+func (h *PerformanceHandler) GetCourierPerformance(c echo.Context) error {
+	userId, err := uuid.Parse(c.Param("userid"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	breakdown, err := h.srv.GetPerformance(c.Request().Context(), userId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GetPerformance: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetPerformance: %v", err))
+	}
+	return c.JSON(http.StatusOK, breakdown)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type PositionHandler struct {
+	srv PositionServiceInterface
+}
+
+func NewPositionHandler(srv PositionServiceInterface) *PositionHandler {
+	return &PositionHandler{srv: srv}
+}
+
+type PositionServiceInterface interface {
+	IngestFixes(ctx context.Context, userId uuid.UUID, batch *model.LocationBatch) (*model.LocationBatchResult, error)
+	GetCourierPositions(ctx context.Context) ([]*model.CourierPosition, error)
+	GetDeliveryTrack(ctx context.Context, deliveryId uuid.UUID) ([]*model.TrackPoint, error)
+}
+
+// PostLocations receives GPS fixes from the courier app
+// @Summary PostLocations
+// @Description Stores a batch of up to 500 GPS fixes, invalid fixes are rejected and duplicates ignored
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.LocationBatch true "GPS fixes"
+// @Success 200 {object} model.LocationBatchResult "Accepted and rejected fixes"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/locations [post]
+func (h *PositionHandler) PostLocations(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	batch := &model.LocationBatch{}
+	err = c.Bind(batch)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	result, err := h.srv.IngestFixes(c.Request().Context(), userId, batch)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("IngestFixes: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("IngestFixes: %v", err))
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetCourierPositions returns last known positions of couriers
+// @Summary GetCourierPositions
+// @Description Returns the last known position of every courier who has sent one, newest first
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} model.CourierPosition "Courier positions"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/courier_positions [get]
+func (h *PositionHandler) GetCourierPositions(c echo.Context) error {
+	positions, err := h.srv.GetCourierPositions(c.Request().Context())
+	if err != nil {
+		logrus.Errorf("GetCourierPositions: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetCourierPositions: %v", err))
+	}
+	return c.JSON(http.StatusOK, positions)
+}
+
+// GetDeliveryTrack returns the route driven for a delivery
+// @Summary GetDeliveryTrack
+// @Description Returns downsampled track points of the delivery in time order
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Delivery id"
+// @Success 200 {array} model.TrackPoint "Track"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/delivery_track/{id} [get]
+func (h *PositionHandler) GetDeliveryTrack(c echo.Context) error {
+	deliveryId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	track, err := h.srv.GetDeliveryTrack(c.Request().Context(), deliveryId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"deliveryId": deliveryId}).Errorf("GetDeliveryTrack: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetDeliveryTrack: %v", err))
+	}
+	return c.JSON(http.StatusOK, track)
+}
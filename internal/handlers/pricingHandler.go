@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type PricingHandler struct {
+	srv PricingServiceInterface
+}
+
+func NewPricingHandler(srv PricingServiceInterface) *PricingHandler {
+	return &PricingHandler{srv: srv}
+}
+
+type PricingServiceInterface interface {
+	Quote(ctx context.Context, request *model.QuoteRequest) (*model.Quote, error)
+	CreateTariff(ctx context.Context, tariff *model.Tariff) (*model.Tariff, error)
+	GetTariffs(ctx context.Context, zone string) ([]*model.Tariff, error)
+	CreatePromo(ctx context.Context, promo *model.Promo) (*model.Promo, error)
+	GetDeliveryPrice(ctx context.Context, deliveryId uuid.UUID) (*model.Quote, error)
+}
+
+// Quote prices a delivery without creating it
+// @Summary Quote
+// @Description Prices a delivery with the current tariff of the service zone of its pickup point, or of its drop-off city
+// @Description outside every zone. The promo code is checked but not used up
+// @Tags Pricing
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.QuoteRequest true "Delivery to price"
+// @Success 200 {object} model.Quote "Price with its components"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "No tariff for the zone or the zone is closed"
+// @Failure 500 {string} string "Internal server error"
+// @Router /delivery/quote [post]
+func (h *PricingHandler) Quote(c echo.Context) error {
+	request := &model.QuoteRequest{}
+	err := c.Bind(request)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"request": request}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	quote, err := h.srv.Quote(c.Request().Context(), request)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"request": request}).Errorf("Quote: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("Quote: %v", err))
+	}
+	return c.JSON(http.StatusOK, quote)
+}
+
+// CreateTariff adds a tariff version
+// @Summary CreateTariff
+// @Description Adds the next version of a zone's tariff, it applies from valid_from or immediately; zone "*" is the default tariff
+// @Tags Pricing
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.Tariff true "Tariff, id, version and created_at are ignored"
+// @Success 201 {object} model.Tariff "Stored tariff version"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "Another version was added meanwhile"
+// @Failure 500 {string} string "Internal server error"
+// @Router /delivery/tariff [post]
+func (h *PricingHandler) CreateTariff(c echo.Context) error {
+	tariff := &model.Tariff{}
+	err := c.Bind(tariff)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"tariff": tariff}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	created, err := h.srv.CreateTariff(c.Request().Context(), tariff)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"tariff": tariff}).Errorf("CreateTariff: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("CreateTariff: %v", err))
+	}
+	return c.JSON(http.StatusCreated, created)
+}
+
+// GetTariffs lists tariff versions
+// @Summary GetTariffs
+// @Description Returns all versions of the zone's tariff, or of every zone when zone is omitted, newest version first
+// @Tags Pricing
+// @Security ApiKeyAuth
+// @Produce json
+// @Param zone query string false "Zone"
+// @Success 200 {array} model.Tariff "Tariff versions"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /delivery/tariffs [get]
+func (h *PricingHandler) GetTariffs(c echo.Context) error {
+	tariffs, err := h.srv.GetTariffs(c.Request().Context(), c.QueryParam("zone"))
+	if err != nil {
+		logrus.Errorf("GetTariffs: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetTariffs: %v", err))
+	}
+	return c.JSON(http.StatusOK, tariffs)
+}
+
+// CreatePromo adds a promo code
+// @Summary CreatePromo
+// @Description Adds a promo code with either a percentage or a fixed discount, codes are stored in upper case
+// @Tags Pricing
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.Promo true "Promo code, used is ignored"
+// @Success 201 {object} model.Promo "Stored promo code"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "Code already exists"
+// @Failure 500 {string} string "Internal server error"
+// @Router /delivery/promo [post]
+func (h *PricingHandler) CreatePromo(c echo.Context) error {
+	promo := &model.Promo{}
+	err := c.Bind(promo)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"promo": promo}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	created, err := h.srv.CreatePromo(c.Request().Context(), promo)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"promo": promo}).Errorf("CreatePromo: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("CreatePromo: %v", err))
+	}
+	return c.JSON(http.StatusCreated, created)
+}
+
+// GetDeliveryPrice returns the frozen price of a delivery
+// @Summary GetDeliveryPrice
+// @Description Returns the price components frozen when the delivery was created
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Delivery id"
+// @Success 200 {object} model.Quote "Frozen price"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Delivery has no price"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/delivery_price/{id} [get]
+func (h *PricingHandler) GetDeliveryPrice(c echo.Context) error {
+	deliveryId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	quote, err := h.srv.GetDeliveryPrice(c.Request().Context(), deliveryId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"deliveryId": deliveryId}).Errorf("GetDeliveryPrice: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetDeliveryPrice: %v", err))
+	}
+	return c.JSON(http.StatusOK, quote)
+}
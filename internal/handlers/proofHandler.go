@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type ProofHandler struct {
+	srv ProofServiceInterface
+}
+
+func NewProofHandler(srv ProofServiceInterface) *ProofHandler {
+	return &ProofHandler{srv: srv}
+}
+
+type ProofServiceInterface interface {
+	CompleteDelivery(ctx context.Context, userId uuid.UUID, completion *model.DeliveryCompletion) ([]*model.DeliveryProof, error)
+	GetDeliveryProofs(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryProof, error)
+	OpenProof(ctx context.Context, proofId uuid.UUID) (*model.DeliveryProof, io.ReadCloser, error)
+	ReissuePin(ctx context.Context, deliveryId uuid.UUID) (string, error)
+}
+
+// bindCompletion reads the multipart delivery completion form
+func bindCompletion(c echo.Context) (*model.DeliveryCompletion, error) {
+	completion := &model.DeliveryCompletion{Pin: c.FormValue("pin")}
+	var err error
+	completion.DeliveryId, err = uuid.Parse(c.FormValue("delivery_id"))
+	if err != nil {
+		return nil, fmt.Errorf("delivery_id: %w", err)
+	}
+	if value := c.FormValue("captured_at"); value != "" {
+		completion.CapturedAt, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("captured_at: %w", err)
+		}
+	}
+	for name, target := range map[string]**float64{"lat": &completion.Lat, "lon": &completion.Lon, "collected_amount": &completion.CollectedAmount} {
+		value := c.FormValue(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		*target = &parsed
+	}
+	for _, kind := range []string{model.ProofPhoto, model.ProofSignature} {
+		header, err := c.FormFile(kind)
+		if errors.Is(err, http.ErrMissingFile) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", kind, err)
+		}
+		if header.Size > model.MaxProofBytes {
+			return nil, fmt.Errorf("%s: larger than %d bytes", kind, model.MaxProofBytes)
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", kind, err)
+		}
+		// one byte over the limit is enough to reject the file without reading all of it
+		data, err := io.ReadAll(io.LimitReader(file, model.MaxProofBytes+1))
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", kind, err)
+		}
+		if len(data) > model.MaxProofBytes {
+			return nil, fmt.Errorf("%s: larger than %d bytes", kind, model.MaxProofBytes)
+		}
+		completion.Artifacts = append(completion.Artifacts, model.ProofArtifact{Kind: kind, Data: data})
+	}
+	return completion, nil
+}
+
+// CompleteDelivery marks the courier's delivery delivered with proof
+// @Summary CompleteDelivery
+// @Description Marks a picked up delivery of the courier delivered. Depending on configuration a photo, a signature image
+// @Description and/or the recipient's one-time PIN are required, the PIN only for deliveries it was issued for. Capture time and GPS are stored with each proof
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param delivery_id formData string true "Delivery id"
+// @Param pin formData string false "PIN given by the recipient"
+// @Param captured_at formData string false "Capture time, RFC 3339, defaults to now"
+// @Param lat formData number false "Latitude where the proof was captured"
+// @Param lon formData number false "Longitude where the proof was captured"
+// @Param collected_amount formData number false "Cash taken from the recipient, required for cash on delivery. The courier owes the COD amount, a smaller amount is reported as a shortfall"
+// @Param photo formData file false "Photo of the handed over parcel"
+// @Param signature formData file false "Recipient signature image"
+// @Success 200 {array} model.DeliveryProof "Recorded proofs"
+// @Failure 400 {string} string "Bad request or missing proof"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Delivery not found"
+// @Failure 409 {string} string "Delivery is not picked up or the PIN is locked, managers reissue locked PINs"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/deliver [post]
+func (h *ProofHandler) CompleteDelivery(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	completion, err := bindCompletion(c)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("bindCompletion: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	proofs, err := h.srv.CompleteDelivery(c.Request().Context(), userId, completion)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId, "deliveryId": completion.DeliveryId}).Errorf("CompleteDelivery: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("CompleteDelivery: %v", err))
+	}
+	return c.JSON(http.StatusOK, proofs)
+}
+
+// GetDeliveryProofs returns proof metadata of a delivery
+// @Summary GetDeliveryProofs
+// @Description Returns the proofs recorded for a delivery, files are downloaded by proof id
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Delivery id"
+// @Success 200 {array} model.DeliveryProof "Proofs"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/delivery_proof/{id} [get]
+func (h *ProofHandler) GetDeliveryProofs(c echo.Context) error {
+	deliveryId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	proofs, err := h.srv.GetDeliveryProofs(c.Request().Context(), deliveryId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"deliveryId": deliveryId}).Errorf("GetDeliveryProofs: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetDeliveryProofs: %v", err))
+	}
+	return c.JSON(http.StatusOK, proofs)
+}
+
+// GetProofFile streams a stored photo or signature
+// @Summary GetProofFile
+// @Description Returns the image stored for a photo or signature proof
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce image/jpeg,image/png
+// @Param id path string true "Proof id"
+// @Success 200 {file} file "Proof image"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Proof or file not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/delivery_proof_file/{id} [get]
+func (h *ProofHandler) GetProofFile(c echo.Context) error {
+	proofId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	proof, blob, err := h.srv.OpenProof(c.Request().Context(), proofId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"proofId": proofId}).Errorf("OpenProof: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("OpenProof: %v", err))
+	}
+	defer blob.Close()
+	return c.Stream(http.StatusOK, proof.ContentType, blob)
+}
+
+// ReissuePin issues a new recipient PIN for a delivery
+// @Summary ReissuePin
+// @Description Replaces the PIN of an open delivery, which also unlocks a PIN locked after too many wrong attempts.
+// @Description The new PIN is returned only here, the manager passes it to the recipient
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.DeliveryId true "Delivery"
+// @Success 200 {object} model.DeliveryPin "New PIN"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Delivery not found"
+// @Failure 409 {string} string "Delivery is closed"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/reissue_delivery_pin [patch]
+func (h *ProofHandler) ReissuePin(c echo.Context) error {
+	Id := &model.DeliveryId{}
+	err := c.Bind(Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"Id": Id}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	pin, err := h.srv.ReissuePin(c.Request().Context(), Id.Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"deliveryId": Id.Id}).Errorf("ReissuePin: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("ReissuePin: %v", err))
+	}
+	return c.JSON(http.StatusOK, &model.DeliveryPin{Pin: pin})
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type RatingHandler struct {
+	srv RatingServiceInterface
+}
+
+func NewRatingHandler(srv RatingServiceInterface) *RatingHandler {
+	return &RatingHandler{srv: srv}
+}
+
+type RatingServiceInterface interface {
+	RateAsClient(ctx context.Context, clientId uuid.UUID, request *model.RatingRequest) (*model.Rating, error)
+	RateByTrackingCode(ctx context.Context, code string, request *model.RatingRequest) (*model.Rating, error)
+	ModerateRating(ctx context.Context, managerId uuid.UUID, moderation *model.RatingModeration) (*model.Rating, error)
+	GetCourierRatings(ctx context.Context, userId uuid.UUID) (*model.CourierRatings, error)
+	GetRatingAlerts(ctx context.Context, since *time.Time) ([]*model.Rating, error)
+}
+
+// RateDelivery rates the courier of one of the client's deliveries
+// @Summary RateDelivery
+// @Description Rates the courier of a delivered delivery from 1 to 5 with optional tags and comment.
+// @Description A delivery is rated once and only within the rating period after delivery
+// @Tags Client methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.RatingRequest true "Rating"
+// @Success 201 {object} model.Rating "Stored rating"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Delivery not found"
+// @Failure 409 {string} string "Delivery is not delivered, already rated or the rating period has ended"
+// @Failure 500 {string} string "Internal server error"
+// @Router /client/rate_delivery [post]
+func (h *RatingHandler) RateDelivery(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	request := &model.RatingRequest{}
+	err = c.Bind(request)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"request": request}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	rating, err := h.srv.RateAsClient(c.Request().Context(), userId, request)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId, "deliveryId": request.Id}).Errorf("RateAsClient: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("RateAsClient: %v", err))
+	}
+	return c.JSON(http.StatusCreated, rating)
+}
+
+// RateByTrackingCode rates the courier of a delivery by its tracking code
+// @Summary RateByTrackingCode
+// @Description Rates the courier of a delivered delivery from 1 to 5 with optional tags and comment, no authorization required.
+// @Description The delivery id of the body is ignored, a delivery is rated once and only within the rating period after delivery
+// @Tags Tracking
+// @Accept json
+// @Produce json
+// @Param code path string true "Tracking code"
+// @Param input body model.RatingRequest true "Rating"
+// @Success 201 {object} model.Rating "Stored rating"
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Unknown or revoked code"
+// @Failure 409 {string} string "Delivery is not delivered, already rated or the rating period has ended"
+// @Failure 429 {string} string "Too many requests"
+// @Failure 500 {string} string "Internal server error"
+// @Router /track/{code}/rating [post]
+func (h *RatingHandler) RateByTrackingCode(c echo.Context) error {
+	request := &model.RatingRequest{}
+	err := c.Bind(request)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+	}
+	rating, err := h.srv.RateByTrackingCode(c.Request().Context(), c.Param("code"), request)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"ip": c.RealIP()}).Errorf("RateByTrackingCode: %v", err)
+		// the error text is not returned so that internal details stay private
+		return echo.NewHTTPError(errorStatus(err), http.StatusText(errorStatus(err)))
+	}
+	// the rater is anonymous, only the score, tags and comment are echoed back
+	return c.JSON(http.StatusCreated, &model.Rating{Id: rating.Id, Score: rating.Score, Tags: rating.Tags, Comment: rating.Comment, CreatedAt: rating.CreatedAt})
+}
+
+// ModerateRating hides or publishes a rating
+// @Summary ModerateRating
+// @Description Hides a rating from the courier's aggregates, alerts and performance, or publishes it again
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.RatingModeration true "Moderation"
+// @Success 200 {object} model.Rating "Moderated rating"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Rating not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/rating_moderation [patch]
+func (h *RatingHandler) ModerateRating(c echo.Context) error {
+	managerId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": managerId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	moderation := &model.RatingModeration{}
+	err = c.Bind(moderation)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"moderation": moderation}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	rating, err := h.srv.ModerateRating(c.Request().Context(), managerId, moderation)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"moderation": moderation}).Errorf("ModerateRating: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("ModerateRating: %v", err))
+	}
+	return c.JSON(http.StatusOK, rating)
+}
+
+// GetCourierRatings returns the rating aggregates of a courier
+// @Summary GetCourierRatings
+// @Description Returns the count, average, score distribution and tag counts of the courier's published ratings
+// @Description together with their latest ratings including hidden ones
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Param userid path string true "Courier user id"
+// @Success 200 {object} model.CourierRatings "Rating aggregates"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/courier_ratings/{userid} [get]
+func (h *RatingHandler) GetCourierRatings(c echo.Context) error {
+	userId, err := uuid.Parse(c.Param("userid"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	ratings, err := h.srv.GetCourierRatings(c.Request().Context(), userId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GetCourierRatings: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetCourierRatings: %v", err))
+	}
+	return c.JSON(http.StatusOK, ratings)
+}
+
+// GetRatingAlerts returns the low rating feed
+// @Summary GetRatingAlerts
+// @Description Returns published ratings at or below the configured low score, newest first
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Param since query string false "Only ratings created after this time (RFC 3339)"
+// @Success 200 {array} model.Rating "Low ratings"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/rating_alerts [get]
+func (h *RatingHandler) GetRatingAlerts(c echo.Context) error {
+	since, err := timeQueryParam(c, "since")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("since: %v", err))
+	}
+	ratings, err := h.srv.GetRatingAlerts(c.Request().Context(), since)
+	if err != nil {
+		logrus.Errorf("GetRatingAlerts: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetRatingAlerts: %v", err))
+	}
+	return c.JSON(http.StatusOK, ratings)
+}
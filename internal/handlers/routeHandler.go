@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type RouteHandler struct {
+	srv RouteServiceInterface
+}
+
+func NewRouteHandler(srv RouteServiceInterface) *RouteHandler {
+	return &RouteHandler{srv: srv}
+}
+
+type RouteServiceInterface interface {
+	GetRoute(ctx context.Context, userId uuid.UUID) (*model.RoutePlan, error)
+}
+
+// GetMyRoute returns the suggested order of the courier's stops
+// @Summary GetMyRoute
+// @Description Plans pickups and drop-offs of the authorized courier's active deliveries, pickups come before their drop-offs and delivery windows are kept where possible
+// @Tags Courier Bussiness logic
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} model.RoutePlan "Planned route"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /courier/route [get]
+func (h *RouteHandler) GetMyRoute(c echo.Context) error {
+	userId, err := middleware.GetPayloadFromToken(strings.Split(c.Request().Header.Get("Authorization"), " ")[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"id": userId}).Errorf("GetPayloadFromToken: %v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("GetPayloadFromToken: %v", err))
+	}
+	plan, err := h.srv.GetRoute(c.Request().Context(), userId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GetRoute: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetRoute: %v", err))
+	}
+	return c.JSON(http.StatusOK, plan)
+}
+
+// PreviewCourierRoute returns the suggested order of a courier's stops
+// @Summary PreviewCourierRoute
+// @Description Plans the route of the courier with the given user id
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Param userid path string true "Courier user id"
+// @Success 200 {object} model.RoutePlan "Planned route"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/courier_route/{userid} [get]
+func (h *RouteHandler) PreviewCourierRoute(c echo.Context) error {
+	userId, err := uuid.Parse(c.Param("userid"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	plan, err := h.srv.GetRoute(c.Request().Context(), userId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GetRoute: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetRoute: %v", err))
+	}
+	return c.JSON(http.StatusOK, plan)
+}
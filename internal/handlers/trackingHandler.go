@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type TrackingHandler struct {
+	srv TrackingServiceInterface
+}
+
+func NewTrackingHandler(srv TrackingServiceInterface) *TrackingHandler {
+	return &TrackingHandler{srv: srv}
+}
+
+type TrackingServiceInterface interface {
+	Track(ctx context.Context, code string) (*model.TrackingView, error)
+	ReissueCode(ctx context.Context, deliveryId uuid.UUID) (string, error)
+	RevokeCodes(ctx context.Context, deliveryId uuid.UUID) error
+}
+
+// Track returns the public view of a delivery by its tracking code
+// @Summary Track
+// @Description Returns status, timeline, ETA and courier first name for a tracking code, no authorization required.
+// @Description A returned delivery also shows the leg taking the parcel back to the sender
+// @Tags Tracking
+// @Produce json
+// @Param code path string true "Tracking code"
+// @Success 200 {object} model.TrackingView "Delivery tracking info"
+// @Failure 404 {string} string "Unknown or revoked code"
+// @Failure 429 {string} string "Too many requests"
+// @Failure 500 {string} string "Internal server error"
+// @Router /track/{code} [get]
+func (h *TrackingHandler) Track(c echo.Context) error {
+	view, err := h.srv.Track(c.Request().Context(), c.Param("code"))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"ip": c.RealIP()}).Errorf("Track: %v", err)
+		// the error text is not returned so that internal details stay private
+		return echo.NewHTTPError(errorStatus(err), http.StatusText(errorStatus(err)))
+	}
+	return c.JSON(http.StatusOK, view)
+}
+
+// ReissueCode replaces the tracking code of a delivery
+// @Summary ReissueCode
+// @Description Revokes the current tracking code of a delivery and issues a new one
+// @Tags Tracking
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.DeliveryId true "Delivery"
+// @Success 200 {object} model.TrackingCode "New tracking code"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Delivery not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /delivery/reissue_tracking_code [patch]
+func (h *TrackingHandler) ReissueCode(c echo.Context) error {
+	Id := &model.DeliveryId{}
+	err := c.Bind(Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"Id": Id}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	code, err := h.srv.ReissueCode(c.Request().Context(), Id.Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"deliveryId": Id.Id}).Errorf("ReissueCode: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("ReissueCode: %v", err))
+	}
+	return c.JSON(http.StatusOK, &model.TrackingCode{Code: code})
+}
+
+// RevokeCodes disables public tracking of a delivery
+// @Summary RevokeCodes
+// @Description Revokes every tracking code of a delivery
+// @Tags Tracking
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.DeliveryId true "Delivery"
+// @Success 200 {string} string "Tracking codes have been revoked"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /delivery/revoke_tracking_code [patch]
+func (h *TrackingHandler) RevokeCodes(c echo.Context) error {
+	Id := &model.DeliveryId{}
+	err := c.Bind(Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"Id": Id}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	err = h.srv.RevokeCodes(c.Request().Context(), Id.Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"deliveryId": Id.Id}).Errorf("RevokeCodes: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("RevokeCodes: %v", err))
+	}
+	return c.JSON(http.StatusOK, "Tracking codes have been revoked")
+}
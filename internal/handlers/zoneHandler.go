@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type ZoneHandler struct {
+	srv ZoneServiceInterface
+}
+
+func NewZoneHandler(srv ZoneServiceInterface) *ZoneHandler {
+	return &ZoneHandler{srv: srv}
+}
+
+type ZoneServiceInterface interface {
+	CreateZone(ctx context.Context, zone *model.Zone) (*model.Zone, error)
+	UpdateZone(ctx context.Context, zone *model.Zone) (*model.Zone, error)
+	GetZones(ctx context.Context) ([]*model.Zone, error)
+	Locate(ctx context.Context, point model.GeoPoint) (*model.Zone, error)
+	SetCourierZones(ctx context.Context, request *model.CourierZones) ([]*model.Zone, error)
+	GetCourierZones(ctx context.Context, userId uuid.UUID) ([]*model.Zone, error)
+}
+
+// CreateZone adds a service zone
+// @Summary CreateZone
+// @Description Adds an active service zone bounded by a GeoJSON Polygon or MultiPolygon ([lon, lat] positions).
+// @Description Deliveries picked up inside the zone belong to it, are priced with the tariff of the zone code
+// @Description and can only be created while the zone is open
+// @Tags Zones
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.Zone true "Zone"
+// @Success 201 {object} model.Zone "Created zone"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "Zone code is taken"
+// @Failure 500 {string} string "Internal server error"
+// @Router /admin/zone [post]
+func (h *ZoneHandler) CreateZone(c echo.Context) error {
+	zone := &model.Zone{}
+	err := c.Bind(zone)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"zone": zone}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	zone, err = h.srv.CreateZone(c.Request().Context(), zone)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"zone": zone}).Errorf("CreateZone: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("CreateZone: %v", err))
+	}
+	return c.JSON(http.StatusCreated, zone)
+}
+
+// UpdateZone replaces a service zone
+// @Summary UpdateZone
+// @Description Replaces the code, name, boundary, hours and active flag of a zone, existing deliveries keep their zone
+// @Tags Zones
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Zone id"
+// @Param input body model.Zone true "Zone"
+// @Success 200 {object} model.Zone "Updated zone"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Zone not found"
+// @Failure 409 {string} string "Zone code is taken"
+// @Failure 500 {string} string "Internal server error"
+// @Router /admin/zone/{id} [put]
+func (h *ZoneHandler) UpdateZone(c echo.Context) error {
+	zoneId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	zone := &model.Zone{}
+	err = c.Bind(zone)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"zone": zone}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	zone.Id = zoneId
+	zone, err = h.srv.UpdateZone(c.Request().Context(), zone)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"zoneId": zoneId}).Errorf("UpdateZone: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("UpdateZone: %v", err))
+	}
+	return c.JSON(http.StatusOK, zone)
+}
+
+// GetZones lists service zones
+// @Summary GetZones
+// @Description Returns every service zone including inactive ones
+// @Tags Zones
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} model.Zone "Zones"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Router /admin/zones [get]
+func (h *ZoneHandler) GetZones(c echo.Context) error {
+	zones, err := h.srv.GetZones(c.Request().Context())
+	if err != nil {
+		logrus.Errorf("GetZones: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetZones: %v", err))
+	}
+	return c.JSON(http.StatusOK, zones)
+}
+
+// LocateZone returns the zone of a point
+// @Summary LocateZone
+// @Description Returns the active zone containing the point, the smallest one when zones overlap
+// @Tags Zones
+// @Security ApiKeyAuth
+// @Produce json
+// @Param lat query number true "Latitude"
+// @Param lon query number true "Longitude"
+// @Success 200 {object} model.Zone "Zone"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Point is outside every zone"
+// @Failure 500 {string} string "Internal server error"
+// @Router /admin/zone_lookup [get]
+func (h *ZoneHandler) LocateZone(c echo.Context) error {
+	point := model.GeoPoint{}
+	err := echo.QueryParamsBinder(c).MustFloat64("lat", &point.Lat).MustFloat64("lon", &point.Lon).BindError()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	zone, err := h.srv.Locate(c.Request().Context(), point)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"point": point}).Errorf("Locate: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("Locate: %v", err))
+	}
+	if zone == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Locate: point is outside every zone")
+	}
+	return c.JSON(http.StatusOK, zone)
+}
+
+// SetCourierZones sets the home zones of a courier
+// @Summary SetCourierZones
+// @Description Replaces the home zones of a courier, they then only see and claim deliveries of those zones
+// @Description and deliveries without a zone. An empty list lets the courier work everywhere
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body model.CourierZones true "Home zones"
+// @Success 200 {array} model.Zone "Home zones"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier or zone not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/courier_zones [put]
+func (h *ZoneHandler) SetCourierZones(c echo.Context) error {
+	request := &model.CourierZones{}
+	err := c.Bind(request)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"request": request}).Errorf("Bind: %v", err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Bind: %v", err))
+	}
+	zones, err := h.srv.SetCourierZones(c.Request().Context(), request)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"request": request}).Errorf("SetCourierZones: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("SetCourierZones: %v", err))
+	}
+	return c.JSON(http.StatusOK, zones)
+}
+
+// GetCourierZones returns the home zones of a courier
+// @Summary GetCourierZones
+// @Description Returns the home zones of the courier with the given user id
+// @Tags Manager methods
+// @Security ApiKeyAuth
+// @Produce json
+// @Param userid path string true "Courier user id"
+// @Success 200 {array} model.Zone "Home zones"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Courier not found"
+// @Failure 500 {string} string "Internal server error"
+// @Router /manager/courier_zones/{userid} [get]
+func (h *ZoneHandler) GetCourierZones(c echo.Context) error {
+	userId, err := uuid.Parse(c.Param("userid"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Parse: %v", err))
+	}
+	zones, err := h.srv.GetCourierZones(c.Request().Context(), userId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userId": userId}).Errorf("GetCourierZones: %v", err)
+		return echo.NewHTTPError(errorStatus(err), fmt.Sprintf("GetCourierZones: %v", err))
+	}
+	return c.JSON(http.StatusOK, zones)
+}
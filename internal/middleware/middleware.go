@@ -14,6 +14,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/liza/labwork_45/internal/config"
+	"github.com/liza/labwork_45/internal/model"
 )
 
 // tokenClaims struct consists od JWT claims
@@ -24,159 +25,90 @@ type tokenClaims struct {
 
 // const for middlware
 const (
-	Bearer  = "Bearer"
-	Admin   = "Admin"
-	Courier = "Courier"
-	Client  = "Client"
-	Manager = "Manager"
+	Bearer   = "Bearer"
+	Admin    = "Admin"
+	Courier  = "Courier"
+	Client   = "Client"
+	Manager  = "Manager"
+	HubStaff = "HubStaff"
 )
 
 // UserIdentity is a middleware function that validates access token
 func UserIdentity() echo.MiddlewareFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			cfg := config.Config{}
-			err := env.Parse(&cfg)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid env variable")
-			}
-			// Checking for auth header
-			authHeader := c.Request().Header.Get("Authorization")
-			if authHeader == "" {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Missing authorization header")
-			}
-			// checking for auth header format
-			headerParts := strings.Split(authHeader, " ")
-			if len(headerParts) != 2 || headerParts[0] != Bearer {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid authorization header format")
-			}
-			// checking for valid access token
-			token, err := ValidateToken(headerParts[1], cfg.SigningKey)
-			if err != nil || !token.Valid {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid token")
-			}
-			// checking for valid role
-			roleCheck, err := RoleValidation(headerParts[1], Client)
-			if err != nil || !roleCheck {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid role")
-			}
-			// getting payload from access token
-			id, err := GetPayloadFromToken(headerParts[1])
-			fmt.Println("middleware id working --> ", id)
-			if err != nil {
-				return err
-			}
-
-			// checking for token expiration
-			if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-				exp := claims["exp"].(float64)
-				if exp < float64(time.Now().Unix()) {
-					return echo.NewHTTPError(http.StatusUnauthorized, "Token is expired")
-				}
-			}
-			return next(c)
-		}
-	}
+	return RoleIdentity(Client)
 }
 
-// UserIdentity is a middleware function that validates access token
+// CourierIdentity is a middleware function that validates access token of a courier
 func CourierIdentity() echo.MiddlewareFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			cfg := config.Config{}
-			err := env.Parse(&cfg)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid env variable")
-			}
-			// Checking for auth header
-			authHeader := c.Request().Header.Get("Authorization")
-			if authHeader == "" {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Missing authorization header")
-			}
-			// checking for auth header format
-			headerParts := strings.Split(authHeader, " ")
-			if len(headerParts) != 2 || headerParts[0] != Bearer {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid authorization header format")
-			}
-			// checking for valid access token
-			token, err := ValidateToken(headerParts[1], cfg.SigningKey)
-			if err != nil || !token.Valid {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid token")
-			}
-			// checking for valid role
-			roleCheck, err := RoleValidation(headerParts[1], Courier)
-			if err != nil || !roleCheck {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid role")
-			}
-			// getting payload from access token
-			id, err := GetPayloadFromToken(headerParts[1])
-			fmt.Println("middleware id working --> ", id)
-			if err != nil {
-				return err
-			}
-
-			// checking for token expiration
-			if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-				exp := claims["exp"].(float64)
-				if exp < float64(time.Now().Unix()) {
-					return echo.NewHTTPError(http.StatusUnauthorized, "Token is expired")
-				}
-			}
-			return next(c)
-		}
-	}
+	return RoleIdentity(Courier)
 }
 
 // ManagerIdentity is a middleware function that validates access token
 func ManagerIdentity() echo.MiddlewareFunc {
+	return RoleIdentity(Manager)
+}
+
+// HubStaffIdentity is a middleware function that validates access token
+func HubStaffIdentity() echo.MiddlewareFunc {
+	return RoleIdentity(HubStaff)
+}
+
+// AdminIdentity is a middleware function that validates access token
+func AdminIdentity() echo.MiddlewareFunc {
+	return RoleIdentity(Admin)
+}
+
+// AnyIdentity is a middleware function that validates access token of any role
+func AnyIdentity() echo.MiddlewareFunc {
+	return RoleIdentity(Admin, Courier, Client, Manager, HubStaff)
+}
+
+// EventsIdentity validates access token of any role like AnyIdentity, browser EventSource can not set headers
+// so the stream may instead be opened with a ticket from /events/ticket in the ticket query parameter
+func EventsIdentity() echo.MiddlewareFunc {
+	identity := AnyIdentity()
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		validated := identity(next)
 		return func(c echo.Context) error {
+			ticket := c.QueryParam("ticket")
+			if c.Request().Header.Get("Authorization") != "" || ticket == "" {
+				return validated(c)
+			}
 			cfg := config.Config{}
 			err := env.Parse(&cfg)
 			if err != nil {
 				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid env variable")
 			}
-			// Checking for auth header
-			authHeader := c.Request().Header.Get("Authorization")
-			if authHeader == "" {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Missing authorization header")
-			}
-			// checking for auth header format
-			headerParts := strings.Split(authHeader, " ")
-			if len(headerParts) != 2 || headerParts[0] != Bearer {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid authorization header format")
-			}
-			// checking for valid access token
-			token, err := ValidateToken(headerParts[1], cfg.SigningKey)
-			if err != nil || !token.Valid {
-				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid token")
+			// checking for valid unexpired ticket
+			token, err := ValidateToken(ticket, cfg.SigningKey)
+			if err != nil || !token.Valid || !isEventTicket(token) {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid ticket")
 			}
-			// checking for valid role
-			roleCheck, err := RoleValidation(headerParts[1], Manager)
-			if err != nil || !roleCheck {
+			// checking for known role
+			role, err := GetRoleFromToken(ticket)
+			if err != nil || !hasRole(role, []string{Admin, Courier, Client, Manager, HubStaff}) {
 				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid role")
 			}
-			// getting payload from access token
-			id, err := GetPayloadFromToken(headerParts[1])
-			fmt.Println("middleware id working --> ", id)
+			_, err = GetPayloadFromToken(ticket)
 			if err != nil {
-				return err
-			}
-
-			// checking for token expiration
-			if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-				exp := claims["exp"].(float64)
-				if exp < float64(time.Now().Unix()) {
-					return echo.NewHTTPError(http.StatusUnauthorized, "Token is expired")
-				}
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid ticket")
 			}
+			// the ticket carries the user and role of the access token it was issued for,
+			// moving it into the header lets the handler read them the usual way
+			c.Request().Header.Set("Authorization", Bearer+" "+ticket)
 			return next(c)
 		}
 	}
 }
 
-// AdminIdentity is a middleware function that validates access token
-func AdminIdentity() echo.MiddlewareFunc {
+// isEventTicket reports whether the token is an event stream ticket rather than an access token
+func isEventTicket(token *jwt.Token) bool {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	return ok && claims.VerifyAudience(model.EventTicketAudience, true)
+}
+
+// RoleIdentity is a middleware function that validates access token of a user with one of the given roles
+func RoleIdentity(roles ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			cfg := config.Config{}
@@ -194,21 +126,20 @@ func AdminIdentity() echo.MiddlewareFunc {
 			if len(headerParts) != 2 || headerParts[0] != Bearer {
 				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid authorization header format")
 			}
-			// checking for valid access token
+			// checking for valid access token, event stream tickets are not one
 			token, err := ValidateToken(headerParts[1], cfg.SigningKey)
-			if err != nil || !token.Valid {
+			if err != nil || !token.Valid || isEventTicket(token) {
 				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid token")
 			}
 			// checking for valid role
-			roleCheck, err := RoleValidation(headerParts[1], Admin)
-			if err != nil || !roleCheck {
+			role, err := GetRoleFromToken(headerParts[1])
+			if err != nil || !hasRole(role, roles) {
 				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid role")
 			}
-			// getting payload from access token
-			id, err := GetPayloadFromToken(headerParts[1])
-			fmt.Println("middleware id working --> ", id)
+			// checking for user id in access token
+			_, err = GetPayloadFromToken(headerParts[1])
 			if err != nil {
-				return err
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid token")
 			}
 
 			// checking for token expiration
@@ -223,6 +154,16 @@ func AdminIdentity() echo.MiddlewareFunc {
 	}
 }
 
+// hasRole reports whether role is one of roles
+func hasRole(role string, roles []string) bool {
+	for _, r := range roles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
 // RoleValidation is used to validate the role
 func RoleValidation(tokenString string, neededRole string) (bool, error) {
 	parts := strings.Split(tokenString, ".")
@@ -260,6 +201,24 @@ func ValidateToken(tokenString, signingKey string) (*jwt.Token, error) {
 	return token, nil
 }
 
+// GetRoleFromToken returns the role claim of the given token
+func GetRoleFromToken(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid token format")
+	}
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("DecodeString: %w", err)
+	}
+	var claims tokenClaims
+	err = json.Unmarshal(payloadBytes, &claims)
+	if err != nil {
+		return "", fmt.Errorf("Unmarshal(): %w", err)
+	}
+	return claims.Role, nil
+}
+
 // GetPayloadFromToken returns a payload from the given token
 func GetPayloadFromToken(token string) (uuid.UUID, error) {
 	parts := strings.Split(token, ".")
@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/liza/labwork_45/internal/middleware"
+	"github.com/liza/labwork_45/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+// serve runs a request through the middleware and reports the status and whether the handler was reached
+func serve(identity echo.MiddlewareFunc, target, authorization string) (int, bool) {
+	reached := false
+	handler := identity(func(c echo.Context) error {
+		reached = true
+		return c.NoContent(http.StatusOK)
+	})
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	err := handler(echo.New().NewContext(req, rec))
+	if httpErr, ok := err.(*echo.HTTPError); ok {
+		return httpErr.Code, reached
+	}
+	return rec.Code, reached
+}
+
+// TestEventTicket checks that a ticket opens the event stream in the query but is never accepted as an access token,
+// and that access tokens are not accepted in the query
+func TestEventTicket(t *testing.T) {
+	t.Setenv("SIGNING_KEY", "test_key")
+	userId := uuid.New()
+	ticket, err := service.GenerateEventTicket("test_key", middleware.Client, userId, time.Now())
+	require.NoError(t, err)
+	access, _, err := service.GenerateAccessAndRefreshTokens("test_key", middleware.Client, userId)
+	require.NoError(t, err)
+
+	code, reached := serve(middleware.EventsIdentity(), "/events?ticket="+ticket.Ticket, "")
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, reached)
+
+	code, reached = serve(middleware.EventsIdentity(), "/events?ticket="+access, "")
+	require.Equal(t, http.StatusUnauthorized, code)
+	require.False(t, reached)
+
+	code, _ = serve(middleware.EventsIdentity(), "/events?access_token="+access, "")
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	code, _ = serve(middleware.EventsIdentity(), "/events", middleware.Bearer+" "+access)
+	require.Equal(t, http.StatusOK, code)
+
+	code, reached = serve(middleware.UserIdentity(), "/client/getdeliveries", middleware.Bearer+" "+ticket.Ticket)
+	require.Equal(t, http.StatusUnauthorized, code)
+	require.False(t, reached)
+
+	expired, err := service.GenerateEventTicket("test_key", middleware.Client, userId, time.Now().Add(-2*time.Minute))
+	require.NoError(t, err)
+	code, _ = serve(middleware.EventsIdentity(), "/events?ticket="+expired.Ticket, "")
+	require.Equal(t, http.StatusUnauthorized, code)
+}
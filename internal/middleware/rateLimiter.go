@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
+)
+
+// NewIPExtractor returns how client IPs are read for rate limiting and logs. Without trusted proxies the
+// peer address is used, otherwise X-Forwarded-For is trusted only when it was set by one of the given CIDR ranges
+func NewIPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	options := make([]echo.TrustOption, 0, len(trustedProxies))
+	for _, cidr := range trustedProxies {
+		_, ipRange, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("ParseCIDR(): %w", err)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+// RateLimit is a middleware function that limits requests per client IP,
+// rps is the sustained rate per second and burst is the number of requests allowed at once
+func RateLimit(rps float64, burst int) echo.MiddlewareFunc {
+	store := echomiddleware.NewRateLimiterMemoryStoreWithConfig(echomiddleware.RateLimiterMemoryStoreConfig{
+		Rate:  rate.Limit(rps),
+		Burst: burst,
+	})
+	return echomiddleware.RateLimiterWithConfig(echomiddleware.RateLimiterConfig{
+		Store: store,
+		IdentifierExtractor: func(c echo.Context) (string, error) {
+			return c.RealIP(), nil
+		},
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			return echo.NewHTTPError(http.StatusTooManyRequests, "Too many requests")
+		},
+	})
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons a courier can give for a failed delivery attempt
+const (
+	AttemptRecipientAbsent = "recipient_absent"
+	AttemptAddressNotFound = "address_not_found"
+	AttemptAccessDenied    = "access_denied"
+	AttemptRefused         = "refused"
+	AttemptUnsafeLocation  = "unsafe_location"
+	AttemptOther           = "other"
+)
+
+// FailedAttempt is reported by the courier who could not hand the delivery over
+type FailedAttempt struct {
+	DeliveryId uuid.UUID `json:"delivery_id"`
+	Reason     string    `json:"reason"`
+	Comment    string    `json:"comment"`
+	Lat        *float64  `json:"lat"`
+	Lon        *float64  `json:"lon"`
+}
+
+// DeliveryAttempt is a recorded failed attempt, Number counts attempts of the delivery from 1
+type DeliveryAttempt struct {
+	Id          uuid.UUID `json:"id"`
+	DeliveryId  uuid.UUID `json:"delivery_id"`
+	CourierId   uuid.UUID `json:"courier_id"`
+	Number      int       `json:"number"`
+	Reason      string    `json:"reason"`
+	Comment     string    `json:"comment"`
+	AttemptedAt time.Time `json:"attempted_at"`
+	Lat         *float64  `json:"lat"`
+	Lon         *float64  `json:"lon"`
+}
+
+// AttemptOutcome tells the courier what happens to the delivery after the attempt:
+// it is either rescheduled into a new window, still carried by the courier, or returned to the sender on a new delivery leg
+// that is tracked with the tracking code of the original delivery
+type AttemptOutcome struct {
+	Attempt          *DeliveryAttempt `json:"attempt"`
+	DeliveryStatus   string           `json:"delivery_status"`
+	WindowStart      *time.Time       `json:"window_start"`
+	WindowEnd        *time.Time       `json:"window_end"`
+	ReturnDeliveryId *uuid.UUID       `json:"return_delivery_id,omitempty"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cancellation reason codes
+const (
+	CancelNoLongerNeeded = "no_longer_needed"
+	CancelWrongDetails   = "wrong_details"
+	CancelDuplicate      = "duplicate"
+	CancelTooLate        = "too_late"
+	CancelOperational    = "operational"
+	CancelOther          = "other"
+)
+
+// Delivery states a cancellation fee is configured for
+const (
+	CancelStateCreated  = "created"
+	CancelStateAssigned = "assigned"
+	CancelStatePickedUp = "picked_up"
+)
+
+// Roles allowed to cancel a delivery, couriers report failed attempts instead
+const (
+	CancelledByClient  = "client"
+	CancelledByManager = "manager"
+)
+
+// CancelRequest names the delivery to cancel and why, Comment is required for CancelOther
+type CancelRequest struct {
+	DeliveryId
+	Reason  string `json:"reason"`
+	Comment string `json:"comment"`
+}
+
+// ManagerCancelRequest lets a manager cancel without charging the client
+type ManagerCancelRequest struct {
+	CancelRequest
+	WaiveFee bool `json:"waive_fee"`
+}
+
+// Cancellation records who cancelled a delivery, in which state and for what fee,
+// CourierId is the courier who was unassigned by the cancellation
+type Cancellation struct {
+	DeliveryId  uuid.UUID  `json:"delivery_id"`
+	CancelledBy uuid.UUID  `json:"cancelled_by"`
+	Role        string     `json:"role"`
+	Reason      string     `json:"reason"`
+	Comment     string     `json:"comment"`
+	State       string     `json:"state"`
+	Fee         float64    `json:"fee"`
+	CourierId   *uuid.UUID `json:"courier_id"`
+	CancelledAt time.Time  `json:"cancelled_at"`
+}
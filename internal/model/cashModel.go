@@ -0,0 +1,67 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of courier cash ledger entries, collections add to what the courier holds and handovers subtract
+const (
+	CashEntryCollection = "collection"
+	CashEntryHandover   = "handover"
+)
+
+// CashEntry is one movement of cash held by a courier, Amount is negative for handovers
+type CashEntry struct {
+	Id         uuid.UUID  `json:"id"`
+	CourierId  uuid.UUID  `json:"courier_id"`
+	Kind       string     `json:"kind"`
+	Amount     float64    `json:"amount"`
+	DeliveryId *uuid.UUID `json:"delivery_id,omitempty"`
+	HandoverId *uuid.UUID `json:"handover_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CashLedger is the courier's cash balance with the entries of the requested period
+type CashLedger struct {
+	CourierId uuid.UUID    `json:"courier_id"`
+	Balance   float64      `json:"balance"`
+	Entries   []*CashEntry `json:"entries"`
+}
+
+// CashHandoverRequest is the cash a manager counted when the courier handed it over at shift end
+type CashHandoverRequest struct {
+	CourierUserId uuid.UUID `json:"courier_userid"`
+	Counted       float64   `json:"counted"`
+	Comment       string    `json:"comment"`
+}
+
+// CashHandover reconciles the counted cash with the courier's balance. A negative discrepancy is a shortage,
+// it stays on the courier's balance until it is handed over later
+type CashHandover struct {
+	Id           uuid.UUID `json:"id"`
+	CourierId    uuid.UUID `json:"courier_id"`
+	ManagerId    uuid.UUID `json:"manager_id"`
+	Expected     float64   `json:"expected"`
+	Counted      float64   `json:"counted"`
+	Discrepancy  float64   `json:"discrepancy"`
+	Comment      string    `json:"comment"`
+	HandedOverAt time.Time `json:"handed_over_at"`
+}
+
+// CourierCash is the outstanding cash of one courier
+type CourierCash struct {
+	CourierId      uuid.UUID  `json:"courier_id"`
+	UserId         uuid.UUID  `json:"userid"`
+	Name           string     `json:"name"`
+	Surname        string     `json:"surname"`
+	Outstanding    float64    `json:"outstanding"`
+	Collections    int        `json:"collections"`
+	LastHandoverAt *time.Time `json:"last_handover_at"`
+	// LifetimeDiscrepancies is the sum of every handover discrepancy the courier ever had, settled or not,
+	// a shortage still owed is part of Outstanding because only the counted cash leaves the balance
+	LifetimeDiscrepancies float64 `json:"lifetime_discrepancies"`
+	// Shortfall is the COD amount the courier reported as not collected on delivered deliveries
+	Shortfall float64 `json:"shortfall"`
+}
@@ -1,12 +1,96 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Courier statuses
+const (
+	CourierStatusActive    = "active"
+	CourierStatusSuspended = "suspended"
+	CourierStatusOffShift  = "off_shift"
+)
 
 type Courier struct {
-	Id                   uuid.UUID `json:"id"`
-	UserId               uuid.UUID `json:"userid"`
-	Name                 string    `json:"name"`
-	Surname              string    `json:"surname"`
-	Status               string    `json:"status"`
-	Perfomance_indicator int       `json:"perfomance_indicator"`
+	Id      uuid.UUID `json:"id"`
+	UserId  uuid.UUID `json:"userid"`
+	Name    string    `json:"name"`
+	Surname string    `json:"surname"`
+	Status  string    `json:"status"`
+	// Perfomance_indicator is computed from delivery outcomes and ignored on updates
+	Perfomance_indicator int `json:"perfomance_indicator"`
+}
+
+// CourierPatch changes only the fields that are present in the request
+type CourierPatch struct {
+	Name    *string `json:"name"`
+	Surname *string `json:"surname"`
+	Status  *string `json:"status"`
+}
+
+// CourierManagerPatch is a CourierPatch applied by a manager to the courier with the given user id
+type CourierManagerPatch struct {
+	UserId uuid.UUID `json:"userid"`
+	CourierPatch
+}
+
+// CourierLoad is a courier together with the number of deliveries they currently hold
+type CourierLoad struct {
+	Courier
+	ActiveDeliveries int `json:"active_deliveries"`
+}
+
+// CourierStatusUpdate changes the status of the courier with the given user id
+type CourierStatusUpdate struct {
+	UserId uuid.UUID `json:"userid"`
+	Status string    `json:"status"`
+}
+
+// DeliveryAssignment assigns a delivery to the courier with the given user id
+type DeliveryAssignment struct {
+	DeliveryId    uuid.UUID `json:"delivery_id"`
+	CourierUserId uuid.UUID `json:"courier_userid"`
+}
+
+// CourierCapacity is what a courier currently carries against their limits,
+// MaxWeightKg is nil when the manager set no weight limit, the vehicle type adds its own limits
+type CourierCapacity struct {
+	ActiveDeliveries    int      `json:"active_deliveries"`
+	MaxActiveDeliveries int      `json:"max_active_deliveries"`
+	ActiveWeightKg      float64  `json:"active_weight_kg"`
+	MaxWeightKg         *float64 `json:"max_weight_kg"`
+	OnShift             bool     `json:"on_shift"`
+	VehicleType         string   `json:"vehicle_type"`
+	ActiveVolumeL       float64  `json:"active_volume_l"`
+}
+
+// CourierCapacityUpdate sets limits of the courier with the given user id,
+// the vehicle type is kept when it is omitted
+type CourierCapacityUpdate struct {
+	UserId              uuid.UUID `json:"userid"`
+	MaxActiveDeliveries int       `json:"max_active_deliveries"`
+	MaxWeightKg         *float64  `json:"max_weight_kg"`
+	VehicleType         *string   `json:"vehicle_type"`
+}
+
+// CourierShift is a planned working period of a courier
+type CourierShift struct {
+	Id        uuid.UUID `json:"id"`
+	CourierId uuid.UUID `json:"courier_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+}
+
+// CourierShiftCreate plans a shift for the courier with the given user id
+type CourierShiftCreate struct {
+	UserId   uuid.UUID `json:"userid"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// ShiftId identifies a shift in manager requests
+type ShiftId struct {
+	Id uuid.UUID `json:"id"`
 }
@@ -1,21 +1,98 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
+// Delivery statuses that drive the delivery timestamps
+const (
+	DeliveryStatusCreated   = "created"
+	DeliveryStatusPickedUp  = "picked_up"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusCancelled = "cancelled"
+	// DeliveryStatusReturned closes a delivery whose attempts are exhausted, the parcel goes back on a return leg
+	DeliveryStatusReturned = "returned"
+)
+
+// Delivery timestamps are stored as timestamptz and always returned in UTC.
+// Incoming values must carry an explicit offset (RFC 3339)
 type Delivery struct {
-	Id              uuid.UUID `json:"id"`
-	CourierId       uuid.UUID `json:"courier_id"`
-	DeliveryDate    string    `json:"delivery_date"`
-	DeliveryStatus  string    `json:"delivery_status"`
-	DeliveryComment string    `json:"delivery_comment"`
+	Id              uuid.UUID  `json:"id"`
+	CourierId       uuid.UUID  `json:"courier_id"`
+	ClientId        *uuid.UUID `json:"client_id"`
+	TrackingCode    string     `json:"tracking_code"`
+	DeliveryStatus  string     `json:"delivery_status"`
+	DeliveryComment string     `json:"delivery_comment"`
+	CreatedAt       time.Time  `json:"created_at"`
+	WindowStart     *time.Time `json:"window_start"`
+	WindowEnd       *time.Time `json:"window_end"`
+	PickedUpAt      *time.Time `json:"picked_up_at"`
+	DeliveredAt     *time.Time `json:"delivered_at"`
+	Pickup          Location   `json:"pickup"`
+	Dropoff         Location   `json:"dropoff"`
+	Recipient       Recipient  `json:"recipient"`
+	WeightKg        float64    `json:"weight_kg"`
+	// Items are optional, when present WeightKg and the totals are computed from them
+	Items []*DeliveryItem `json:"items"`
+	DeliveryTotals
+	Express   bool   `json:"express"`
+	PromoCode string `json:"promo_code"`
+	// Price is quoted when the delivery is created and never changes afterwards
+	Price *Quote `json:"price"`
+	// CodAmount is the cash the courier collects from the recipient, zero when the delivery is prepaid
+	CodAmount float64 `json:"cod_amount"`
+	// ZoneId is the service zone containing the pickup point, it is set when the delivery is priced
+	ZoneId *uuid.UUID `json:"zone_id"`
+	// Pin is the one-time code the recipient gives the courier, it is returned only to whoever created
+	// the delivery, who passes it to the recipient
+	Pin string `json:"-"`
+	// ReturnOf links a return-to-sender leg to the delivery that failed, it is never set by callers
+	ReturnOf *uuid.UUID `json:"-"`
 }
 type DeliveryGet struct {
-	Id              uuid.UUID `json:"id"`
-	DeliveryDate    string    `json:"delivery_date"`
-	DeliveryStatus  string    `json:"delivery_status"`
-	DeliveryComment string    `json:"delivery_comment"`
+	Id              uuid.UUID  `json:"id"`
+	CourierId       *uuid.UUID `json:"courier_id"`
+	ClientId        *uuid.UUID `json:"client_id"`
+	DeliveryStatus  string     `json:"delivery_status"`
+	DeliveryComment string     `json:"delivery_comment"`
+	CreatedAt       time.Time  `json:"created_at"`
+	WindowStart     *time.Time `json:"window_start"`
+	WindowEnd       *time.Time `json:"window_end"`
+	PickedUpAt      *time.Time `json:"picked_up_at"`
+	DeliveredAt     *time.Time `json:"delivered_at"`
+	Pickup          Location   `json:"pickup"`
+	Dropoff         Location   `json:"dropoff"`
+	Recipient       Recipient  `json:"recipient"`
+	WeightKg        float64    `json:"weight_kg"`
+	Attempts        int        `json:"attempts"`
+	ReturnOf        *uuid.UUID `json:"return_of"`
+	DeliveryTotals
+	Express bool `json:"express"`
+	// Price is the frozen total, nil for deliveries created before pricing
+	Price     *float64 `json:"price"`
+	CodAmount float64  `json:"cod_amount"`
+	// CodCollected is the cash recorded when a cash on delivery was delivered
+	CodCollected *float64 `json:"cod_collected"`
+	// ZoneId is nil for deliveries picked up outside every service zone
+	ZoneId *uuid.UUID `json:"zone_id"`
+	// Legs is the number of legs of a delivery routed through hubs, zero for direct deliveries.
+	// The status, courier and timestamps of a multi-leg delivery are derived from its legs
+	Legs int `json:"legs"`
+	// Items are only loaded for the delivery detail
+	Items []*DeliveryItem `json:"items,omitempty"`
+	// ETA is the estimated drop-off time, nil until the courier's position is known
+	ETA *time.Time `json:"eta"`
+}
+
+// DeliveryFilter limits deliveries by their promised window, nil bounds are ignored.
+// With CourierUserId set only deliveries of the courier's home zones and deliveries
+// without a zone are kept, couriers without home zones see every zone
+type DeliveryFilter struct {
+	From          *time.Time
+	To            *time.Time
+	CourierUserId *uuid.UUID
 }
 
 type DeliveryStatus struct {
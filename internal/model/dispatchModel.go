@@ -0,0 +1,80 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Offer statuses
+const (
+	OfferStatusPending  = "pending"
+	OfferStatusAccepted = "accepted"
+	OfferStatusDeclined = "declined"
+	OfferStatusExpired  = "expired"
+	// OfferStatusWithdrawn closes offers of deliveries that were cancelled, split into legs or assigned by a manager,
+	// it does not count against the courier
+	OfferStatusWithdrawn = "withdrawn"
+)
+
+// Dispatch outcomes
+const (
+	DispatchAssigned    = "assigned"
+	DispatchOffered     = "offered"
+	DispatchNoCandidate = "no_candidate"
+)
+
+// DispatchCandidate is a courier considered by the dispatcher,
+// Position is nil when the courier location is unknown
+type DispatchCandidate struct {
+	Courier
+	CourierCapacity
+	Position *GeoPoint `json:"position"`
+}
+
+// DeliveryOffer proposes a delivery to a courier until ExpiresAt
+type DeliveryOffer struct {
+	Id          uuid.UUID  `json:"id"`
+	DeliveryId  uuid.UUID  `json:"delivery_id"`
+	CourierId   uuid.UUID  `json:"courier_id"`
+	Status      string     `json:"status"`
+	Score       float64    `json:"score"`
+	OfferedAt   time.Time  `json:"offered_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RespondedAt *time.Time `json:"responded_at"`
+}
+
+// DispatchResult describes what the dispatcher did with a delivery
+type DispatchResult struct {
+	DeliveryId uuid.UUID  `json:"delivery_id"`
+	Outcome    string     `json:"outcome"`
+	CourierId  *uuid.UUID `json:"courier_id,omitempty"`
+	OfferId    *uuid.UUID `json:"offer_id,omitempty"`
+	Score      float64    `json:"score"`
+}
+
+// OfferView is a pending offer shown to the courier together with the delivery route
+type OfferView struct {
+	DeliveryOffer
+	Pickup      Location   `json:"pickup"`
+	Dropoff     Location   `json:"dropoff"`
+	WindowStart *time.Time `json:"window_start"`
+	WindowEnd   *time.Time `json:"window_end"`
+}
+
+// OfferId identifies an offer in courier requests
+type OfferId struct {
+	Id uuid.UUID `json:"id"`
+}
+
+// OfferMetrics summarizes how a courier responds to offers
+type OfferMetrics struct {
+	CourierId      uuid.UUID `json:"courier_id"`
+	Name           string    `json:"name"`
+	Surname        string    `json:"surname"`
+	Offered        int       `json:"offered"`
+	Accepted       int       `json:"accepted"`
+	Declined       int       `json:"declined"`
+	Expired        int       `json:"expired"`
+	AcceptanceRate float64   `json:"acceptance_rate"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of courier earnings, delivery and on-time bonus earnings are accrued automatically,
+// adjustments and penalties are booked by admins and penalties are stored as negative amounts
+const (
+	EarningDelivery    = "delivery"
+	EarningOnTimeBonus = "on_time_bonus"
+	EarningAdjustment  = "adjustment"
+	EarningPenalty     = "penalty"
+)
+
+// Statement periods, weeks start on Monday and periods are counted in UTC
+const (
+	StatementWeek  = "week"
+	StatementMonth = "month"
+)
+
+// Earning is one entry of the courier earnings ledger, LegId is set for a leg of a multi-leg delivery
+type Earning struct {
+	Id         uuid.UUID  `json:"id"`
+	CourierId  uuid.UUID  `json:"courier_id"`
+	DeliveryId *uuid.UUID `json:"delivery_id,omitempty"`
+	LegId      *uuid.UUID `json:"leg_id,omitempty"`
+	Kind       string     `json:"kind"`
+	Amount     float64    `json:"amount"`
+	Comment    string     `json:"comment,omitempty"`
+	CreatedBy  *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// EarningAdjustmentRequest is a manual correction, Amount of a penalty is given as a positive number
+type EarningAdjustmentRequest struct {
+	CourierUserId uuid.UUID `json:"courier_userid"`
+	Kind          string    `json:"kind"`
+	Amount        float64   `json:"amount"`
+	Comment       string    `json:"comment"`
+}
+
+// Statement sums the earnings booked within a closed period, Entries are only loaded for the statement detail
+type Statement struct {
+	Id               uuid.UUID  `json:"id"`
+	CourierId        uuid.UUID  `json:"courier_id"`
+	UserId           uuid.UUID  `json:"userid"`
+	Name             string     `json:"name"`
+	Surname          string     `json:"surname"`
+	Period           string     `json:"period"`
+	PeriodStart      time.Time  `json:"period_start"`
+	PeriodEnd        time.Time  `json:"period_end"`
+	Deliveries       int        `json:"deliveries"`
+	DeliveryAmount   float64    `json:"delivery_amount"`
+	BonusAmount      float64    `json:"bonus_amount"`
+	AdjustmentAmount float64    `json:"adjustment_amount"`
+	PenaltyAmount    float64    `json:"penalty_amount"`
+	Total            float64    `json:"total"`
+	GeneratedAt      time.Time  `json:"generated_at"`
+	Entries          []*Earning `json:"entries,omitempty"`
+}
+
+// StatementFilter selects statements of one period kind starting within the bounds, nil bounds are ignored
+type StatementFilter struct {
+	Period string
+	From   *time.Time
+	To     *time.Time
+}
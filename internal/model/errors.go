@@ -0,0 +1,11 @@
+package model
+
+import "errors"
+
+// Errors returned by the service layer, handlers map them to HTTP status codes
+var (
+	ErrValidation = errors.New("validation error")
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrForbidden  = errors.New("forbidden")
+)
@@ -0,0 +1,28 @@
+package model
+
+import "github.com/google/uuid"
+
+// Vehicle types of couriers, each has its own speed profile
+const (
+	VehicleFoot    = "foot"
+	VehicleBicycle = "bicycle"
+	VehicleScooter = "scooter"
+	VehicleCar     = "car"
+)
+
+// CourierRoute is what the ETAs of a courier's active deliveries are estimated from,
+// Position is nil when the courier has not sent a location yet
+type CourierRoute struct {
+	CourierId   uuid.UUID
+	VehicleType string
+	Position    *GeoPoint
+	Deliveries  []*DeliveryGet
+}
+
+// ZoneSpeed is the average speed from pickup to drop-off of recent deliveries
+// made with a vehicle type in a zone, the zone is the drop-off city
+type ZoneSpeed struct {
+	Zone        string
+	VehicleType string
+	SpeedKmh    float64
+}
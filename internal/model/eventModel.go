@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Delivery event types
+const (
+	DeliveryEventCreated    = "created"
+	DeliveryEventStatus     = "status"
+	DeliveryEventAssignment = "assignment"
+	DeliveryEventPosition   = "position"
+)
+
+// EventTicketAudience marks tokens that only open an event stream
+const EventTicketAudience = "events"
+
+// EventTicket opens an event stream until it expires, for clients that can not set the Authorization header
+type EventTicket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// DeliveryEvent is a change of a delivery published to subscribers,
+// CourierId is the courier table id, PreviousCourierId is set when the courier changed
+// and Position is only set for position events
+type DeliveryEvent struct {
+	Type              string     `json:"type"`
+	DeliveryId        uuid.UUID  `json:"delivery_id"`
+	ClientId          *uuid.UUID `json:"client_id"`
+	CourierId         *uuid.UUID `json:"courier_id"`
+	PreviousCourierId *uuid.UUID `json:"previous_courier_id,omitempty"`
+	Status            string     `json:"status"`
+	Position          *GeoPoint  `json:"position,omitempty"`
+	At                time.Time  `json:"at"`
+}
+
+// EventFilter selects the deliveries a subscriber may see: every delivery,
+// the deliveries of a client or the deliveries of a courier given by user id
+type EventFilter struct {
+	All           bool
+	ClientId      *uuid.UUID
+	CourierUserId *uuid.UUID
+}
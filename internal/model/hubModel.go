@@ -0,0 +1,88 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Hub is a warehouse where parcels change couriers between legs
+type Hub struct {
+	Id        uuid.UUID `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Location  Location  `json:"location"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// HubStaff assigns the user with the given id to the hub they scan parcels at
+type HubStaff struct {
+	UserId uuid.UUID `json:"userid"`
+	HubId  uuid.UUID `json:"hub_id"`
+}
+
+// DeliveryLeg is one courier's part of a multi-leg delivery. FromHubId is nil for the leg starting at the
+// pickup address and ToHubId is nil for the leg ending at the drop-off address. Legs use the delivery statuses
+// created, picked_up, delivered and cancelled, a leg ending at a hub is delivered when the hub checks the parcel in
+type DeliveryLeg struct {
+	Id          uuid.UUID  `json:"id"`
+	DeliveryId  uuid.UUID  `json:"delivery_id"`
+	Position    int        `json:"position"`
+	FromHubId   *uuid.UUID `json:"from_hub_id"`
+	ToHubId     *uuid.UUID `json:"to_hub_id"`
+	CourierId   *uuid.UUID `json:"courier_id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	PickedUpAt  *time.Time `json:"picked_up_at"`
+	DeliveredAt *time.Time `json:"delivered_at"`
+	// Version guards concurrent changes of the leg
+	Version int `json:"-"`
+}
+
+// DeliveredLeg is a delivered leg with the points it connects, its courier is paid for the distance between them
+type DeliveredLeg struct {
+	Id          uuid.UUID
+	DeliveryId  uuid.UUID
+	CourierId   uuid.UUID
+	From        GeoPoint
+	To          GeoPoint
+	DeliveredAt time.Time
+	// WindowEnd is the promised window of the delivery, only the final leg is delivered within it
+	WindowEnd *time.Time
+	Final     bool
+}
+
+// DeliveryRouting splits a new delivery into legs through the given hubs, in order
+type DeliveryRouting struct {
+	DeliveryId uuid.UUID   `json:"delivery_id"`
+	HubIds     []uuid.UUID `json:"hub_ids"`
+}
+
+// LegAssignment assigns a leg to the courier with the given user id
+type LegAssignment struct {
+	LegId         uuid.UUID `json:"leg_id"`
+	CourierUserId uuid.UUID `json:"courier_userid"`
+}
+
+// LegId identifies a leg
+type LegId struct {
+	Id uuid.UUID `json:"id"`
+}
+
+// LegProgress is the delivery state derived from its legs. CourierId is the courier of the first unfinished leg,
+// AtHubId is set while the parcel waits at a hub
+type LegProgress struct {
+	Status      string     `json:"status"`
+	CourierId   *uuid.UUID `json:"courier_id"`
+	PickedUpAt  *time.Time `json:"picked_up_at"`
+	DeliveredAt *time.Time `json:"delivered_at"`
+	AtHubId     *uuid.UUID `json:"at_hub_id"`
+}
+
+// DeliveryLegs is a multi-leg delivery with its derived state
+type DeliveryLegs struct {
+	DeliveryId uuid.UUID `json:"delivery_id"`
+	LegProgress
+	Legs []*DeliveryLeg `json:"legs"`
+}
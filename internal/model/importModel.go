@@ -0,0 +1,45 @@
+package model
+
+import "github.com/google/uuid"
+
+// import file formats
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatXLSX = "xlsx"
+)
+
+// DeliveryImport is an uploaded spreadsheet of deliveries, one delivery per row below a header row.
+// ClientId is the client of every row that has no client_id of its own, DryRun validates and prices every row without saving anything
+type DeliveryImport struct {
+	Format   string
+	Data     []byte
+	ClientId *uuid.UUID
+	DryRun   bool
+}
+
+// ImportRowError is a problem found in a spreadsheet row, Column is empty when it concerns the whole row
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportedDelivery is a delivery created from a spreadsheet row
+type ImportedDelivery struct {
+	Row          int     `json:"row"`
+	Id           string  `json:"id,omitempty"`
+	TrackingCode string  `json:"tracking_code,omitempty"`
+	Pin          string  `json:"pin,omitempty"`
+	Price        float64 `json:"price"`
+}
+
+// ImportReport is the outcome of an import. Rows are numbered as in the spreadsheet, the header being row 1.
+// Valid rows are inserted together unless DryRun is set, rows listed in Errors are never inserted
+type ImportReport struct {
+	DryRun     bool                `json:"dry_run"`
+	Rows       int                 `json:"rows"`
+	Valid      int                 `json:"valid"`
+	Inserted   int                 `json:"inserted"`
+	Deliveries []*ImportedDelivery `json:"deliveries"`
+	Errors     []*ImportRowError   `json:"errors"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "github.com/google/uuid"
+
+// Temperature requirements of items, the strictest item decides for the whole delivery
+const (
+	TemperatureAmbient = "ambient"
+	TemperatureChilled = "chilled"
+	TemperatureFrozen  = "frozen"
+)
+
+// DeliveryItem is one line of the delivery contents, weight and declared value are per unit
+type DeliveryItem struct {
+	Id            uuid.UUID `json:"id"`
+	DeliveryId    uuid.UUID `json:"delivery_id"`
+	Description   string    `json:"description"`
+	Quantity      int       `json:"quantity"`
+	WeightKg      float64   `json:"weight_kg"`
+	LengthCm      float64   `json:"length_cm"`
+	WidthCm       float64   `json:"width_cm"`
+	HeightCm      float64   `json:"height_cm"`
+	DeclaredValue float64   `json:"declared_value"`
+	Fragile       bool      `json:"fragile"`
+	Temperature   string    `json:"temperature"`
+}
+
+// DeliveryTotals are computed from the items when the delivery is created and stored on it,
+// MaxSideCm is the longest side of any item and decides which vehicles can carry the delivery
+type DeliveryTotals struct {
+	ItemCount     int     `json:"item_count"`
+	VolumeL       float64 `json:"volume_l"`
+	MaxSideCm     float64 `json:"max_side_cm"`
+	DeclaredValue float64 `json:"declared_value"`
+	Fragile       bool    `json:"fragile"`
+	Temperature   string  `json:"temperature"`
+}
@@ -0,0 +1,29 @@
+package model
+
+// Location is a structured address with WGS 84 coordinates
+type Location struct {
+	AddressLine1 string  `json:"address_line1"`
+	AddressLine2 string  `json:"address_line2"`
+	City         string  `json:"city"`
+	Postcode     string  `json:"postcode"`
+	Lat          float64 `json:"lat"`
+	Lon          float64 `json:"lon"`
+}
+
+// Recipient holds contact details of the person receiving the delivery
+type Recipient struct {
+	Name        string `json:"name"`
+	Phone       string `json:"phone"`
+	AccessNotes string `json:"access_notes"`
+}
+
+// GeoPoint is a bare WGS 84 coordinate pair
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Point returns coordinates of the location
+func (l Location) Point() GeoPoint {
+	return GeoPoint{Lat: l.Lat, Lon: l.Lon}
+}
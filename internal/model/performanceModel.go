@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PerformanceStats are raw delivery outcomes of a courier over the rolling window
+type PerformanceStats struct {
+	CourierId uuid.UUID
+	// Completed counts deliveries that were delivered or failed after pickup
+	Completed     int
+	OnTime        int
+	Failed        int
+	Offered       int
+	Accepted      int
+	Ratings       int
+	AverageRating *float64
+}
+
+// PerformanceBreakdown shows how the performance indicator of a courier was derived,
+// a rate is nil when there was nothing to measure and then it does not affect the indicator
+type PerformanceBreakdown struct {
+	CourierId      uuid.UUID `json:"courier_id"`
+	Indicator      int       `json:"performance_indicator"`
+	OnTimeRate     *float64  `json:"on_time_rate"`
+	FailedRate     *float64  `json:"failed_rate"`
+	AcceptanceRate *float64  `json:"acceptance_rate"`
+	AverageRating  *float64  `json:"average_rating"`
+	Completed      int       `json:"completed"`
+	OnTime         int       `json:"on_time"`
+	Failed         int       `json:"failed"`
+	Offered        int       `json:"offered"`
+	Accepted       int       `json:"accepted"`
+	Ratings        int       `json:"ratings"`
+	WindowStart    time.Time `json:"window_start"`
+	CalculatedAt   time.Time `json:"calculated_at"`
+}
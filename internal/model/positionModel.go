@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LocationFix is a single GPS reading sent by the courier app,
+// RecordedAt is the device time of the reading and must carry an offset
+type LocationFix struct {
+	Lat        float64   `json:"lat"`
+	Lon        float64   `json:"lon"`
+	AccuracyM  *float64  `json:"accuracy_m"`
+	SpeedMps   *float64  `json:"speed_mps"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// Point returns coordinates of the fix
+func (f LocationFix) Point() GeoPoint {
+	return GeoPoint{Lat: f.Lat, Lon: f.Lon}
+}
+
+// LocationBatch is a group of fixes uploaded at once
+type LocationBatch struct {
+	Fixes []LocationFix `json:"fixes"`
+}
+
+// LocationBatchResult reports how many fixes of a batch were kept
+type LocationBatchResult struct {
+	Accepted int `json:"accepted"`
+	Rejected int `json:"rejected"`
+}
+
+// CourierPosition is the last known position of a courier
+type CourierPosition struct {
+	CourierId uuid.UUID `json:"courier_id"`
+	UserId    uuid.UUID `json:"userid"`
+	Name      string    `json:"name"`
+	Surname   string    `json:"surname"`
+	Status    string    `json:"status"`
+	LocationFix
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// TrackPoint is a downsampled point of the route driven for a delivery
+type TrackPoint struct {
+	DeliveryId uuid.UUID `json:"delivery_id"`
+	Lat        float64   `json:"lat"`
+	Lon        float64   `json:"lon"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
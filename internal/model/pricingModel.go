@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTariffZone holds the tariff used in zones without a tariff of their own
+const DefaultTariffZone = "*"
+
+// Tariff is one version of the pricing rules of a zone, a zone is the drop-off city as for ETAs.
+// The version with the latest ValidFrom that has started applies, older versions are kept for
+// deliveries priced with them. Night hours are counted in TimeZone and may wrap past midnight
+type Tariff struct {
+	Id             uuid.UUID `json:"id"`
+	Zone           string    `json:"zone"`
+	Version        int       `json:"version"`
+	ValidFrom      time.Time `json:"valid_from"`
+	BaseFee        float64   `json:"base_fee"`
+	PerKm          float64   `json:"per_km"`
+	PerKg          float64   `json:"per_kg"`
+	MinPrice       float64   `json:"min_price"`
+	ExpressPct     float64   `json:"express_pct"`
+	FragileFee     float64   `json:"fragile_fee"`
+	NightPct       float64   `json:"night_pct"`
+	NightStartHour int       `json:"night_start_hour"`
+	NightEndHour   int       `json:"night_end_hour"`
+	TimeZone       string    `json:"time_zone"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Promo is a discount code, either PercentOff or AmountOff is set,
+// MaxUses is nil for codes that can be used any number of times
+type Promo struct {
+	Code       string     `json:"code"`
+	PercentOff float64    `json:"percent_off"`
+	AmountOff  float64    `json:"amount_off"`
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidTo    *time.Time `json:"valid_to"`
+	MaxUses    *int       `json:"max_uses"`
+	Used       int        `json:"used"`
+}
+
+// QuoteRequest describes a delivery to price, weight is computed from the items when they are given
+type QuoteRequest struct {
+	Pickup      Location        `json:"pickup"`
+	Dropoff     Location        `json:"dropoff"`
+	WeightKg    float64         `json:"weight_kg"`
+	Items       []*DeliveryItem `json:"items"`
+	Express     bool            `json:"express"`
+	WindowStart *time.Time      `json:"window_start"`
+	PromoCode   string          `json:"promo_code"`
+}
+
+// Quote is the price of a delivery broken down into its components, amounts are rounded to cents
+type Quote struct {
+	DeliveryId       uuid.UUID `json:"delivery_id,omitempty"`
+	TariffId         uuid.UUID `json:"tariff_id"`
+	Zone             string    `json:"zone"`
+	TariffVersion    int       `json:"tariff_version"`
+	DistanceKm       float64   `json:"distance_km"`
+	WeightKg         float64   `json:"weight_kg"`
+	BaseFee          float64   `json:"base_fee"`
+	DistanceFee      float64   `json:"distance_fee"`
+	WeightFee        float64   `json:"weight_fee"`
+	ExpressSurcharge float64   `json:"express_surcharge"`
+	FragileSurcharge float64   `json:"fragile_surcharge"`
+	NightSurcharge   float64   `json:"night_surcharge"`
+	Discount         float64   `json:"discount"`
+	PromoCode        string    `json:"promo_code,omitempty"`
+	Total            float64   `json:"total"`
+	QuotedAt         time.Time `json:"quoted_at"`
+}
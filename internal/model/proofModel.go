@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Proof kinds that can be required for the delivered transition
+const (
+	ProofPhoto     = "photo"
+	ProofSignature = "signature"
+	ProofPin       = "pin"
+)
+
+// DeliveryProof is evidence recorded with a delivery event, PIN proofs have no stored artifact
+type DeliveryProof struct {
+	Id          uuid.UUID `json:"id"`
+	DeliveryId  uuid.UUID `json:"delivery_id"`
+	CourierId   uuid.UUID `json:"courier_id"`
+	Event       string    `json:"event"`
+	Kind        string    `json:"kind"`
+	BlobKey     string    `json:"-"`
+	ContentType string    `json:"content_type,omitempty"`
+	SizeBytes   int64     `json:"size_bytes,omitempty"`
+	Sha256      string    `json:"sha256,omitempty"`
+	CapturedAt  time.Time `json:"captured_at"`
+	Lat         *float64  `json:"lat"`
+	Lon         *float64  `json:"lon"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// MaxProofBytes limits the size of one uploaded photo or signature image
+const MaxProofBytes = 10 << 20
+
+// ProofArtifact is an uploaded photo or signature image
+type ProofArtifact struct {
+	Kind string
+	Data []byte
+}
+
+// DeliveryCompletion is what the courier submits to mark a delivery delivered
+type DeliveryCompletion struct {
+	DeliveryId uuid.UUID
+	Pin        string
+	CapturedAt time.Time
+	Lat        *float64
+	Lon        *float64
+	Artifacts  []ProofArtifact
+	// CollectedAmount is the cash taken from the recipient, required for cash on delivery
+	CollectedAmount *float64
+}
+
+// DeliveryPin is returned to the manager when a new PIN is issued, the manager passes it to the recipient
+type DeliveryPin struct {
+	Pin string `json:"pin"`
+}
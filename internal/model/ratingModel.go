@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Who rated a delivery, the owning client or a holder of its tracking code
+const (
+	RatedByClient   = "client"
+	RatedByTracking = "tracking"
+)
+
+// Rating moderation states, hidden ratings are left out of aggregates and performance
+const (
+	RatingPublished = "published"
+	RatingHidden    = "hidden"
+)
+
+// Rating tags a customer can attach to a rating
+const (
+	RatingTagOnTime       = "on_time"
+	RatingTagFriendly     = "friendly"
+	RatingTagCareful      = "careful"
+	RatingTagProfessional = "professional"
+	RatingTagLate         = "late"
+	RatingTagRude         = "rude"
+	RatingTagDamaged      = "damaged"
+	RatingTagNoContact    = "no_contact"
+)
+
+// RatingRequest rates the courier of a delivered delivery, the id is ignored when rating by tracking code
+type RatingRequest struct {
+	DeliveryId
+	Score   int      `json:"score"`
+	Tags    []string `json:"tags"`
+	Comment string   `json:"comment"`
+}
+
+// Rating is the customer's feedback on a delivery, RatedBy is nil for tracking code holders
+type Rating struct {
+	Id             uuid.UUID  `json:"id"`
+	DeliveryId     uuid.UUID  `json:"delivery_id"`
+	CourierId      uuid.UUID  `json:"courier_id"`
+	Score          int        `json:"score"`
+	Tags           []string   `json:"tags"`
+	Comment        string     `json:"comment"`
+	Source         string     `json:"source"`
+	RatedBy        *uuid.UUID `json:"rated_by,omitempty"`
+	Status         string     `json:"status"`
+	ModeratedBy    *uuid.UUID `json:"moderated_by,omitempty"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty"`
+	ModerationNote string     `json:"moderation_note,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// RatingModeration hides or publishes a rating
+type RatingModeration struct {
+	Id     uuid.UUID `json:"id"`
+	Status string    `json:"status"`
+	Note   string    `json:"note"`
+}
+
+// CourierRatings aggregates the published ratings of a courier, Distribution counts scores 1 to 5
+type CourierRatings struct {
+	CourierId    uuid.UUID      `json:"courier_id"`
+	Ratings      int            `json:"ratings"`
+	Average      *float64       `json:"average"`
+	Distribution [5]int         `json:"distribution"`
+	Tags         map[string]int `json:"tags"`
+	Hidden       int            `json:"hidden"`
+	Recent       []*Rating      `json:"recent"`
+}
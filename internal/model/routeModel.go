@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Route stop kinds
+const (
+	StopPickup  = "pickup"
+	StopDropoff = "dropoff"
+)
+
+// RouteStop is a planned visit, windows are only set for drop-offs
+type RouteStop struct {
+	DeliveryId  uuid.UUID  `json:"delivery_id"`
+	Kind        string     `json:"kind"`
+	Location    Location   `json:"location"`
+	WindowStart *time.Time `json:"window_start"`
+	WindowEnd   *time.Time `json:"window_end"`
+	Arrival     time.Time  `json:"arrival"`
+	Late        bool       `json:"late"`
+}
+
+// RoutePlan is the suggested order of a courier's remaining stops
+type RoutePlan struct {
+	CourierId  uuid.UUID   `json:"courier_id"`
+	Stops      []RouteStop `json:"stops"`
+	DistanceKm float64     `json:"distance_km"`
+	FinishAt   time.Time   `json:"finish_at"`
+	LateStops  int         `json:"late_stops"`
+}
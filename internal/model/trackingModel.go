@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// TrackingAttemptFailed is the timeline status of a failed delivery attempt, other steps use delivery statuses
+const TrackingAttemptFailed = "attempt_failed"
+
+// TrackingEvent is a single step of the public delivery timeline
+type TrackingEvent struct {
+	Status string    `json:"status"`
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// TrackingView is the redacted delivery view shown to holders of a tracking code,
+// it must never contain internal identifiers, recipient contacts or the delivery PIN
+type TrackingView struct {
+	Status           string          `json:"status"`
+	CourierFirstName string          `json:"courier_first_name,omitempty"`
+	WindowStart      *time.Time      `json:"window_start"`
+	WindowEnd        *time.Time      `json:"window_end"`
+	ETA              *time.Time      `json:"eta"`
+	Timeline         []TrackingEvent `json:"timeline"`
+	// Return follows the parcel back to the sender once the delivery is returned
+	Return *TrackingReturn `json:"return,omitempty"`
+}
+
+// TrackingReturn is the redacted view of the return-to-sender leg of a delivery
+type TrackingReturn struct {
+	Status      string     `json:"status"`
+	WindowEnd   *time.Time `json:"window_end"`
+	ETA         *time.Time `json:"eta"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+}
+
+// TrackingCode is returned to the delivery owner when a code is issued
+type TrackingCode struct {
+	Code string `json:"tracking_code"`
+}
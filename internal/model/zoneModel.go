@@ -0,0 +1,48 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GeoJSON geometry types accepted for zone boundaries
+const (
+	GeometryPolygon      = "Polygon"
+	GeometryMultiPolygon = "MultiPolygon"
+)
+
+// Geometry is a GeoJSON Polygon or MultiPolygon, positions are [lon, lat] in WGS 84
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates" swaggertype:"array,number"`
+}
+
+// ZoneHours is the opening time of a zone on a weekday (0 is Sunday) as "HH:MM" in the zone's time zone,
+// Close "24:00" is midnight. A zone without hours is always open, weekdays left out are closed
+type ZoneHours struct {
+	Weekday time.Weekday `json:"weekday" swaggertype:"integer"`
+	Open    string       `json:"open"`
+	Close   string       `json:"close"`
+}
+
+// Zone is a service district, deliveries belong to the zone containing their pickup point.
+// Code is also the tariff zone, so a zone is priced with the tariff of the same name
+type Zone struct {
+	Id        uuid.UUID   `json:"id"`
+	Code      string      `json:"code"`
+	Name      string      `json:"name"`
+	Boundary  Geometry    `json:"boundary"`
+	TimeZone  string      `json:"time_zone"`
+	Hours     []ZoneHours `json:"hours"`
+	Active    bool        `json:"active"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// CourierZones sets the home zones of the courier with the given user id, an empty list lifts the restriction
+type CourierZones struct {
+	UserId  uuid.UUID   `json:"userid"`
+	ZoneIds []uuid.UUID `json:"zone_ids"`
+}
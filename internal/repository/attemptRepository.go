@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// RecordFailedAttempt stores the attempt and either moves the delivery into the new window, the courier keeps
+// carrying it, or, when returnLeg is given, closes it as returned, cancels its open hub legs and inserts the return leg
+// assigned to the same courier.
+// The delivery must be picked up by the courier and have attempt.Number-1 attempts, otherwise model.ErrConflict is returned
+func (db *PsqlConnection) RecordFailedAttempt(ctx context.Context, attempt *model.DeliveryAttempt, windowStart, windowEnd time.Time, returnLeg *model.Delivery) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, "UPDATE labwork.delivery SET attempts=$1, eta=NULL WHERE id=$2 AND courier_id=$3 AND delivery_status=$4 AND attempts=$5",
+		attempt.Number, attempt.DeliveryId, attempt.CourierId, model.DeliveryStatusPickedUp, attempt.Number-1)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: delivery changed", model.ErrConflict)
+	}
+	_, err = tx.Exec(ctx, "INSERT INTO labwork.delivery_attempt (id, delivery_id, courier_id, number, reason, comment, attempted_at, lat, lon) "+
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		attempt.Id, attempt.DeliveryId, attempt.CourierId, attempt.Number, attempt.Reason, attempt.Comment, attempt.AttemptedAt, attempt.Lat, attempt.Lon)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+
+	if returnLeg == nil {
+		_, err = tx.Exec(ctx, "UPDATE labwork.delivery SET window_start=$1, window_end=$2 WHERE id=$3", windowStart, windowEnd, attempt.DeliveryId)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+	} else {
+		_, err = tx.Exec(ctx, "UPDATE labwork.delivery SET delivery_status=$1 WHERE id=$2", model.DeliveryStatusReturned, attempt.DeliveryId)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+		_, err = tx.Exec(ctx, "UPDATE labwork.delivery_leg SET status=$1, version = version + 1 WHERE delivery_id=$2 AND status IN ($3, $4)",
+			model.DeliveryStatusCancelled, attempt.DeliveryId, model.DeliveryStatusCreated, model.DeliveryStatusPickedUp)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+		err = insertDelivery(ctx, tx, returnLeg)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, "UPDATE labwork.delivery SET courier_id=$1, picked_up_at=$2 WHERE id=$3", attempt.CourierId, returnLeg.PickedUpAt, returnLeg.Id)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	return nil
+}
+
+// GetDeliveryAttempts returns failed attempts of the delivery, first attempt first
+func (db *PsqlConnection) GetDeliveryAttempts(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryAttempt, error) {
+	query := "SELECT id, delivery_id, courier_id, number, reason, comment, attempted_at, lat, lon FROM labwork.delivery_attempt WHERE delivery_id=$1 ORDER BY number"
+	rows, err := db.pool.Query(ctx, query, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.DeliveryAttempt
+
+	for rows.Next() {
+		attempt := &model.DeliveryAttempt{}
+		err := rows.Scan(&attempt.Id, &attempt.DeliveryId, &attempt.CourierId, &attempt.Number, &attempt.Reason, &attempt.Comment, &attempt.AttemptedAt, &attempt.Lat, &attempt.Lon)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, attempt)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// GetCourierCapacity returns the load and limits of the courier at the given moment
+func (db *PsqlConnection) GetCourierCapacity(ctx context.Context, courierId uuid.UUID, now time.Time) (*model.CourierCapacity, error) {
+	capacity := &model.CourierCapacity{}
+	err := scanCapacity(db.pool.QueryRow(ctx, "SELECT "+courierCapacityColumns+" FROM labwork.courier c WHERE c.id = $2", now, courierId), capacity)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return capacity, nil
+}
+
+func scanCapacity(row pgx.Row, capacity *model.CourierCapacity) error {
+	return row.Scan(&capacity.ActiveDeliveries, &capacity.MaxActiveDeliveries, &capacity.ActiveWeightKg, &capacity.MaxWeightKg,
+		&capacity.OnShift, &capacity.VehicleType, &capacity.ActiveVolumeL)
+}
+
+// checkCapacityLocked locks the courier row, so assignments to the same courier run one after another, and hands
+// the courier's capacity and the delivery to check. The capacity is read after the lock is taken, so it includes
+// deliveries assigned by the transaction that held the lock before
+func checkCapacityLocked(ctx context.Context, tx pgx.Tx, courierId, deliveryId uuid.UUID, now time.Time,
+	check func(*model.CourierCapacity, *model.DeliveryGet) error) error {
+	_, err := tx.Exec(ctx, "SELECT 1 FROM labwork.courier WHERE id=$1 FOR UPDATE", courierId)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	capacity := &model.CourierCapacity{}
+	err = scanCapacity(tx.QueryRow(ctx, "SELECT "+courierCapacityColumns+" FROM labwork.courier c WHERE c.id = $2", now, courierId), capacity)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("QueryRow(): %w", err)
+	}
+	delivery := &model.DeliveryGet{}
+	err = scanDelivery(tx.QueryRow(ctx, "SELECT "+deliveryColumns+" FROM labwork.delivery d WHERE d.id=$1", deliveryId), delivery)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("QueryRow(): %w", err)
+	}
+	err = check(capacity, delivery)
+	if err != nil {
+		return fmt.Errorf("check: %w", err)
+	}
+	return nil
+}
+
+// UpdateCourierCapacity returns model.ErrNotFound when there is no courier for the user
+func (db *PsqlConnection) UpdateCourierCapacity(ctx context.Context, update *model.CourierCapacityUpdate) error {
+	query := "UPDATE labwork.courier SET max_active_deliveries=$1, max_weight_kg=$2, vehicle_type=COALESCE($3, vehicle_type) WHERE userid=$4"
+	result, err := db.pool.Exec(ctx, query, update.MaxActiveDeliveries, update.MaxWeightKg, update.VehicleType, update.UserId)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w", model.ErrNotFound)
+	}
+	return nil
+}
+
+// UpdateCourierStatusFrom changes the status only if it still equals from, it reports whether a row was changed
+func (db *PsqlConnection) UpdateCourierStatusFrom(ctx context.Context, courierId uuid.UUID, from string, to string) (bool, error) {
+	update, err := db.pool.Exec(ctx, "UPDATE labwork.courier SET status=$1 WHERE id=$2 AND status=$3", to, courierId, from)
+	if err != nil {
+		return false, fmt.Errorf("Exec(): %w", err)
+	}
+	return update.RowsAffected() == 1, nil
+}
+
+func (db *PsqlConnection) InsertCourierShift(ctx context.Context, shift *model.CourierShift) error {
+	insert := "INSERT INTO labwork.courier_shift (id, courier_id, starts_at, ends_at) VALUES ($1, $2, $3, $4)"
+	_, err := db.pool.Exec(ctx, insert, shift.Id, shift.CourierId, shift.StartsAt, shift.EndsAt)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	return nil
+}
+
+// DeleteCourierShift returns model.ErrNotFound when there is no such shift
+func (db *PsqlConnection) DeleteCourierShift(ctx context.Context, shiftId uuid.UUID) error {
+	result, err := db.pool.Exec(ctx, "DELETE FROM labwork.courier_shift WHERE id=$1", shiftId)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w", model.ErrNotFound)
+	}
+	return nil
+}
+
+// GetCourierShifts returns shifts of the courier that end after the given moment
+func (db *PsqlConnection) GetCourierShifts(ctx context.Context, courierId uuid.UUID, from time.Time) ([]*model.CourierShift, error) {
+	query := "SELECT id, courier_id, starts_at, ends_at FROM labwork.courier_shift WHERE courier_id=$1 AND ends_at > $2 ORDER BY starts_at"
+	rows, err := db.pool.Query(ctx, query, courierId, from)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.CourierShift
+
+	for rows.Next() {
+		shift := &model.CourierShift{}
+		err := rows.Scan(&shift.Id, &shift.CourierId, &shift.StartsAt, &shift.EndsAt)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, shift)
+	}
+	return result, rows.Err()
+}
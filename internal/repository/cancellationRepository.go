@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// CancelDelivery cancels the delivery and its unfinished legs, unassigns its courier and withdraws pending offers in one transaction.
+// The delivery must still have the status and courier of expected, otherwise model.ErrConflict is returned
+func (db *PsqlConnection) CancelDelivery(ctx context.Context, cancellation *model.Cancellation, expected *model.DeliveryGet) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, "UPDATE labwork.delivery SET delivery_status=$1, courier_id=NULL, eta=NULL "+
+		"WHERE id=$2 AND delivery_status=$3 AND courier_id IS NOT DISTINCT FROM $4",
+		model.DeliveryStatusCancelled, cancellation.DeliveryId, expected.DeliveryStatus, expected.CourierId)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: delivery changed", model.ErrConflict)
+	}
+	_, err = tx.Exec(ctx, "INSERT INTO labwork.delivery_cancellation (delivery_id, cancelled_by, role, reason, comment, state, fee, courier_id, cancelled_at) "+
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		cancellation.DeliveryId, cancellation.CancelledBy, cancellation.Role, cancellation.Reason, cancellation.Comment, cancellation.State,
+		cancellation.Fee, cancellation.CourierId, cancellation.CancelledAt)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	err = withdrawPendingOffers(ctx, tx, cancellation.DeliveryId, cancellation.CancelledAt)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, "UPDATE labwork.delivery_leg SET status=$1, version = version + 1 WHERE delivery_id=$2 AND status <> $3",
+		model.DeliveryStatusCancelled, cancellation.DeliveryId, model.DeliveryStatusDelivered)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	return nil
+}
+
+// GetCancellation returns model.ErrNotFound for deliveries that were not cancelled
+func (db *PsqlConnection) GetCancellation(ctx context.Context, deliveryId uuid.UUID) (*model.Cancellation, error) {
+	cancellation := &model.Cancellation{}
+	query := "SELECT delivery_id, cancelled_by, role, reason, comment, state, fee, courier_id, cancelled_at FROM labwork.delivery_cancellation WHERE delivery_id=$1"
+	err := db.pool.QueryRow(ctx, query, deliveryId).Scan(&cancellation.DeliveryId, &cancellation.CancelledBy, &cancellation.Role, &cancellation.Reason,
+		&cancellation.Comment, &cancellation.State, &cancellation.Fee, &cancellation.CourierId, &cancellation.CancelledAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return cancellation, nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// insertCashEntry books a courier cash movement within tx
+func insertCashEntry(ctx context.Context, tx pgx.Tx, entry *model.CashEntry) error {
+	query := "INSERT INTO labwork.courier_cash_entry (id, courier_id, kind, amount, delivery_id, handover_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)"
+	_, err := tx.Exec(ctx, query, entry.Id, entry.CourierId, entry.Kind, entry.Amount, entry.DeliveryId, entry.HandoverId, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	return nil
+}
+
+// GetCashBalance returns the cash the courier holds according to the ledger
+func (db *PsqlConnection) GetCashBalance(ctx context.Context, courierId uuid.UUID) (float64, error) {
+	var balance float64
+	err := db.pool.QueryRow(ctx, "SELECT COALESCE(SUM(amount), 0) FROM labwork.courier_cash_entry WHERE courier_id=$1", courierId).Scan(&balance)
+	if err != nil {
+		return 0, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return balance, nil
+}
+
+// GetCashEntries returns the courier's ledger entries created within the filter bounds, oldest first
+func (db *PsqlConnection) GetCashEntries(ctx context.Context, courierId uuid.UUID, filter *model.DeliveryFilter) ([]*model.CashEntry, error) {
+	query := "SELECT id, courier_id, kind, amount, delivery_id, handover_id, created_at FROM labwork.courier_cash_entry " +
+		"WHERE courier_id=$1 AND ($2::timestamptz IS NULL OR created_at >= $2) AND ($3::timestamptz IS NULL OR created_at <= $3) ORDER BY created_at"
+	rows, err := db.pool.Query(ctx, query, courierId, filter.From, filter.To)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.CashEntry
+
+	for rows.Next() {
+		entry := &model.CashEntry{}
+		err := rows.Scan(&entry.Id, &entry.CourierId, &entry.Kind, &entry.Amount, &entry.DeliveryId, &entry.HandoverId, &entry.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, entry)
+	}
+	return result, rows.Err()
+}
+
+// InsertCashHandover stores the handover and books the counted cash off the courier's balance,
+// it returns model.ErrConflict when the balance is no longer the expected one
+func (db *PsqlConnection) InsertCashHandover(ctx context.Context, handover *model.CashHandover) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// the courier row is locked so that concurrent handovers of the same courier are serialized
+	_, err = tx.Exec(ctx, "SELECT 1 FROM labwork.courier WHERE id=$1 FOR UPDATE", handover.CourierId)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	var balance float64
+	err = tx.QueryRow(ctx, "SELECT COALESCE(SUM(amount), 0) FROM labwork.courier_cash_entry WHERE courier_id=$1", handover.CourierId).Scan(&balance)
+	if err != nil {
+		return fmt.Errorf("QueryRow(): %w", err)
+	}
+	if balance != handover.Expected {
+		return fmt.Errorf("QueryRow(): %w: cash balance has changed meanwhile", model.ErrConflict)
+	}
+	query := "INSERT INTO labwork.cash_handover (id, courier_id, manager_id, expected, counted, discrepancy, comment, handed_over_at) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
+	_, err = tx.Exec(ctx, query, handover.Id, handover.CourierId, handover.ManagerId, handover.Expected, handover.Counted, handover.Discrepancy,
+		handover.Comment, handover.HandedOverAt)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	err = insertCashEntry(ctx, tx, &model.CashEntry{Id: uuid.New(), CourierId: handover.CourierId, Kind: model.CashEntryHandover, Amount: -handover.Counted,
+		HandoverId: &handover.Id, CreatedAt: handover.HandedOverAt})
+	if err != nil {
+		return err
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	return nil
+}
+
+// GetCashHandovers returns the courier's handovers, newest first
+func (db *PsqlConnection) GetCashHandovers(ctx context.Context, courierId uuid.UUID) ([]*model.CashHandover, error) {
+	query := "SELECT id, courier_id, manager_id, expected, counted, discrepancy, comment, handed_over_at FROM labwork.cash_handover " +
+		"WHERE courier_id=$1 ORDER BY handed_over_at DESC"
+	rows, err := db.pool.Query(ctx, query, courierId)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.CashHandover
+
+	for rows.Next() {
+		handover := &model.CashHandover{}
+		err := rows.Scan(&handover.Id, &handover.CourierId, &handover.ManagerId, &handover.Expected, &handover.Counted, &handover.Discrepancy,
+			&handover.Comment, &handover.HandedOverAt)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, handover)
+	}
+	return result, rows.Err()
+}
+
+// GetOutstandingCash returns couriers whose cash balance is not settled, largest balance first
+func (db *PsqlConnection) GetOutstandingCash(ctx context.Context) ([]*model.CourierCash, error) {
+	query := "SELECT c.id, c.userid, COALESCE(c.name, ''), COALESCE(c.surname, ''), " +
+		"COALESCE(SUM(e.amount), 0), COUNT(e.id) FILTER (WHERE e.kind = 'collection'), " +
+		"(SELECT MAX(h.handed_over_at) FROM labwork.cash_handover h WHERE h.courier_id = c.id), " +
+		"(SELECT COALESCE(SUM(h.discrepancy), 0) FROM labwork.cash_handover h WHERE h.courier_id = c.id), " +
+		"(SELECT COALESCE(SUM(d.cod_amount - d.cod_collected), 0) FROM labwork.delivery d " +
+		"WHERE d.courier_id = c.id AND d.delivery_status = 'delivered' AND d.cod_collected < d.cod_amount) " +
+		"FROM labwork.courier c JOIN labwork.courier_cash_entry e ON e.courier_id = c.id " +
+		"GROUP BY c.id HAVING SUM(e.amount) <> 0 ORDER BY SUM(e.amount) DESC"
+	rows, err := db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.CourierCash
+
+	for rows.Next() {
+		cash := &model.CourierCash{}
+		err := rows.Scan(&cash.CourierId, &cash.UserId, &cash.Name, &cash.Surname, &cash.Outstanding, &cash.Collections,
+			&cash.LastHandoverAt, &cash.LifetimeDiscrepancies, &cash.Shortfall)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, cash)
+	}
+	return result, rows.Err()
+}
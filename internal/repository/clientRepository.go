@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+func (db *PsqlConnection) GetDeliveriesByClientID(ctx context.Context, clientId uuid.UUID) ([]*model.DeliveryGet, error) {
+	query := "SELECT " + deliveryColumns + " FROM labwork.delivery WHERE client_id=$1 ORDER BY created_at DESC"
+	rows, err := db.pool.Query(ctx, query, clientId)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.DeliveryGet
+
+	for rows.Next() {
+		delivery := &model.DeliveryGet{}
+		err := scanDelivery(rows, delivery)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, delivery)
+	}
+	return result, rows.Err()
+}
+
+// GetDeliveryByID returns model.ErrNotFound when there is no delivery with the given id
+func (db *PsqlConnection) GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error) {
+	delivery := &model.DeliveryGet{}
+	query := "SELECT " + deliveryColumns + " FROM labwork.delivery WHERE id=$1"
+	err := scanDelivery(db.pool.QueryRow(ctx, query, deliveryId), delivery)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return delivery, nil
+}
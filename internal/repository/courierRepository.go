@@ -2,59 +2,190 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/liza/labwork_45/internal/model"
 )
 
 func (db *PsqlConnection) GetCourierByUserID(ctx context.Context, userId uuid.UUID) (*model.Courier, error) {
 	courier := &model.Courier{}
-	query := "SELECT id, userid, name, surname, status, performance_indicator FROM labwork.courier WHERE userid=$1"
+	// couriers created on signup have no profile yet
+	query := "SELECT id, userid, COALESCE(name, ''), COALESCE(surname, ''), COALESCE(status, ''), COALESCE(performance_indicator, 0) FROM labwork.courier WHERE userid=$1"
 	err := db.pool.QueryRow(ctx, query, userId).Scan(&courier.Id, &courier.UserId, &courier.Name, &courier.Surname, &courier.Status, &courier.Perfomance_indicator)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return courier, nil
+}
+
+// PatchCourier updates the given fields only if the status is still currentStatus,
+// it returns model.ErrConflict when the courier has changed since it was read
+func (db *PsqlConnection) PatchCourier(ctx context.Context, userId uuid.UUID, patch *model.CourierPatch, currentStatus string) (*model.Courier, error) {
+	courier := &model.Courier{}
+	query := "UPDATE labwork.courier SET name=COALESCE($1, name), surname=COALESCE($2, surname), status=COALESCE($3, status) " +
+		"WHERE userid=$4 AND COALESCE(status, '')=$5 " +
+		"RETURNING id, userid, COALESCE(name, ''), COALESCE(surname, ''), COALESCE(status, ''), COALESCE(performance_indicator, 0)"
+	err := db.pool.QueryRow(ctx, query, patch.Name, patch.Surname, patch.Status, userId, currentStatus).
+		Scan(&courier.Id, &courier.UserId, &courier.Name, &courier.Surname, &courier.Status, &courier.Perfomance_indicator)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w: courier has changed meanwhile", model.ErrConflict)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("QueryRow(): %w", err)
 	}
 	return courier, nil
 }
 
-func (db *PsqlConnection) UpdateCourierInfo(ctx context.Context, userId uuid.UUID, courier *model.Courier) error {
-	query := "UPDATE labwork.courier SET name=$1, surname=$2, status=$3, performance_indicator=$4 WHERE userid=$5"
-	update, err := db.pool.Exec(ctx, query, courier.Name, courier.Surname, courier.Status, courier.Perfomance_indicator, userId)
-	if err != nil && update.RowsAffected() == 0 {
+// PickUpDelivery marks the courier's delivery picked up, it returns model.ErrConflict when the delivery
+// is no longer assigned to the courier or is not waiting for pickup
+func (db *PsqlConnection) PickUpDelivery(ctx context.Context, deliveryId uuid.UUID, courierId uuid.UUID, at time.Time) error {
+	tag, err := db.pool.Exec(ctx, "UPDATE labwork.delivery SET delivery_status=$1, picked_up_at=$2 WHERE id=$3 AND courier_id=$4 AND delivery_status=$5",
+		model.DeliveryStatusPickedUp, at, deliveryId, courierId, model.DeliveryStatusCreated)
+	if err != nil {
 		return fmt.Errorf("Exec(): %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: delivery changed", model.ErrConflict)
+	}
 	return nil
 }
 
-func (db *PsqlConnection) UpdateStatus(ctx context.Context, delivery *model.DeliveryStatus) error {
-	_, err := db.pool.Exec(ctx, "UPDATE labwork.delivery SET delivery_status=$1 WHERE id=$2", delivery.DeliveryStatus, delivery.Id)
+// UpdateDeliveryCourier sets or clears the courier of a delivery that is not picked up yet and withdraws
+// its pending offers in one transaction, returns model.ErrConflict when the delivery left the created status
+func (db *PsqlConnection) UpdateDeliveryCourier(ctx context.Context, deliveryId uuid.UUID, courierId *uuid.UUID, now time.Time) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, "UPDATE labwork.delivery SET courier_id=$1 WHERE id=$2 AND delivery_status=$3", courierId, deliveryId, model.DeliveryStatusCreated)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: delivery changed", model.ErrConflict)
+	}
+	err = withdrawPendingOffers(ctx, tx, deliveryId, now)
 	if err != nil {
 		return err
 	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
 	return nil
 }
 
-func (db *PsqlConnection) UpdateDeliveryCourier(ctx context.Context, deliveryId uuid.UUID, courieerId uuid.UUID) error {
-	_, err := db.pool.Exec(ctx, "UPDATE labwork.delivery SET courier_id=$1 WHERE id=$2", courieerId, deliveryId)
+// deliveryColumns lists delivery columns in the order scanDelivery expects
+const deliveryColumns = "id, courier_id, client_id, delivery_status, COALESCE(delivery_comment, ''), created_at, window_start, window_end, picked_up_at, delivered_at, " +
+	"pickup_address_line1, pickup_address_line2, pickup_city, pickup_postcode, COALESCE(pickup_lat, 0), COALESCE(pickup_lon, 0), " +
+	"dropoff_address_line1, dropoff_address_line2, dropoff_city, dropoff_postcode, COALESCE(dropoff_lat, 0), COALESCE(dropoff_lon, 0), " +
+	"recipient_name, recipient_phone, access_notes, weight_kg, attempts, return_of, eta, " +
+	"item_count, volume_l, max_side_cm, declared_value, fragile, temperature, express, price, cod_amount, cod_collected, zone_id, legs"
+
+// deliveryListColumns selects the same as deliveryColumns but leaves the recipient empty,
+// pool listings are seen by every courier while the recipient is only shown to the assigned one
+var deliveryListColumns = strings.Replace(deliveryColumns, "recipient_name, recipient_phone, access_notes", "'', '', ''", 1)
+
+// scanDelivery scans a row selected with deliveryColumns or deliveryListColumns
+func scanDelivery(row pgx.Row, delivery *model.DeliveryGet) error {
+	return row.Scan(&delivery.Id, &delivery.CourierId, &delivery.ClientId, &delivery.DeliveryStatus, &delivery.DeliveryComment, &delivery.CreatedAt,
+		&delivery.WindowStart, &delivery.WindowEnd, &delivery.PickedUpAt, &delivery.DeliveredAt,
+		&delivery.Pickup.AddressLine1, &delivery.Pickup.AddressLine2, &delivery.Pickup.City, &delivery.Pickup.Postcode, &delivery.Pickup.Lat, &delivery.Pickup.Lon,
+		&delivery.Dropoff.AddressLine1, &delivery.Dropoff.AddressLine2, &delivery.Dropoff.City, &delivery.Dropoff.Postcode, &delivery.Dropoff.Lat, &delivery.Dropoff.Lon,
+		&delivery.Recipient.Name, &delivery.Recipient.Phone, &delivery.Recipient.AccessNotes, &delivery.WeightKg, &delivery.Attempts, &delivery.ReturnOf, &delivery.ETA,
+		&delivery.ItemCount, &delivery.VolumeL, &delivery.MaxSideCm, &delivery.DeclaredValue, &delivery.Fragile, &delivery.Temperature,
+		&delivery.Express, &delivery.Price, &delivery.CodAmount, &delivery.CodCollected, &delivery.ZoneId, &delivery.Legs)
+}
+
+// InsertDelivery stores the delivery together with its tracking code and PIN in one transaction
+func (db *PsqlConnection) InsertDelivery(ctx context.Context, delivery *model.Delivery) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = insertDelivery(ctx, tx, delivery)
 	if err != nil {
 		return err
 	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
 	return nil
 }
 
-func (db *PsqlConnection) InsertDelivery(ctx context.Context, delivery *model.Delivery) error {
+// insertDelivery inserts the delivery, its items, price, tracking code and PIN within tx and sets delivery.Id
+func insertDelivery(ctx context.Context, tx pgx.Tx, delivery *model.Delivery) error {
 	id := uuid.New()
-	insert := "INSERT INTO labwork.delivery (id, delivery_date, delivery_status, delivery_comment) VALUES ($1, $2, $3, $4)"
-	query, err := db.pool.Exec(ctx, insert, id, delivery.DeliveryDate, delivery.DeliveryStatus, delivery.DeliveryComment)
-	if err != nil && !query.Insert() {
+	insert := "INSERT INTO labwork.delivery (id, client_id, delivery_status, delivery_comment, created_at, window_start, window_end, " +
+		"pickup_address_line1, pickup_address_line2, pickup_city, pickup_postcode, pickup_lat, pickup_lon, " +
+		"dropoff_address_line1, dropoff_address_line2, dropoff_city, dropoff_postcode, dropoff_lat, dropoff_lon, " +
+		"recipient_name, recipient_phone, access_notes, weight_kg, return_of, " +
+		"item_count, volume_l, max_side_cm, declared_value, fragile, temperature, express, price, cod_amount, zone_id) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, " +
+		"$25, $26, $27, $28, $29, $30, $31, $32, $33, $34)"
+	var price *float64
+	if delivery.Price != nil {
+		price = &delivery.Price.Total
+	}
+	_, err := tx.Exec(ctx, insert, id, delivery.ClientId, delivery.DeliveryStatus, delivery.DeliveryComment, delivery.CreatedAt, delivery.WindowStart, delivery.WindowEnd,
+		delivery.Pickup.AddressLine1, delivery.Pickup.AddressLine2, delivery.Pickup.City, delivery.Pickup.Postcode, delivery.Pickup.Lat, delivery.Pickup.Lon,
+		delivery.Dropoff.AddressLine1, delivery.Dropoff.AddressLine2, delivery.Dropoff.City, delivery.Dropoff.Postcode, delivery.Dropoff.Lat, delivery.Dropoff.Lon,
+		delivery.Recipient.Name, delivery.Recipient.Phone, delivery.Recipient.AccessNotes, delivery.WeightKg, delivery.ReturnOf,
+		delivery.ItemCount, delivery.VolumeL, delivery.MaxSideCm, delivery.DeclaredValue, delivery.Fragile, delivery.Temperature, delivery.Express, price, delivery.CodAmount, delivery.ZoneId)
+	if err != nil {
 		return fmt.Errorf("Exec(): %w", err)
 	}
+	if delivery.Price != nil {
+		err = insertDeliveryPrice(ctx, tx, id, delivery.Price)
+		if err != nil {
+			return err
+		}
+	}
+	for i, item := range delivery.Items {
+		item.Id = uuid.New()
+		item.DeliveryId = id
+		_, err = tx.Exec(ctx, "INSERT INTO labwork.delivery_item (id, delivery_id, position, description, quantity, weight_kg, length_cm, width_cm, height_cm, "+
+			"declared_value, fragile, temperature) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)",
+			item.Id, item.DeliveryId, i, item.Description, item.Quantity, item.WeightKg, item.LengthCm, item.WidthCm, item.HeightCm,
+			item.DeclaredValue, item.Fragile, item.Temperature)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+	}
+	if delivery.TrackingCode != "" {
+		_, err = tx.Exec(ctx, "INSERT INTO labwork.tracking_code (code, delivery_id, created_at) VALUES ($1, $2, $3)", delivery.TrackingCode, id, delivery.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+	}
+	if delivery.Pin != "" {
+		_, err = tx.Exec(ctx, "INSERT INTO labwork.delivery_pin (delivery_id, pin) VALUES ($1, $2)", id, delivery.Pin)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+	}
+	delivery.Id = id
 	return nil
 }
 
-func (db *PsqlConnection) GetAllDeliveries(ctx context.Context) ([]*model.DeliveryGet, error) {
-	rows, err := db.pool.Query(ctx, "SELECT id, delivery_date, delivery_status, delivery_comment FROM labwork.delivery")
+func (db *PsqlConnection) GetAllDeliveries(ctx context.Context, filter *model.DeliveryFilter) ([]*model.DeliveryGet, error) {
+	query := "SELECT " + deliveryListColumns + " FROM labwork.delivery " +
+		"WHERE ($1::timestamptz IS NULL OR window_end >= $1) AND ($2::timestamptz IS NULL OR window_start <= $2) " +
+		"AND ($3::uuid IS NULL OR zone_id IS NULL OR " + homeZoneCondition + ") ORDER BY window_start NULLS LAST, created_at"
+	rows, err := db.pool.Query(ctx, query, filter.From, filter.To, filter.CourierUserId)
 	if err != nil {
 		return nil, fmt.Errorf("Query(): %w", err)
 	}
@@ -64,7 +195,7 @@ func (db *PsqlConnection) GetAllDeliveries(ctx context.Context) ([]*model.Delive
 
 	for rows.Next() {
 		delivery := &model.DeliveryGet{}
-		err := rows.Scan(&delivery.Id, &delivery.DeliveryDate, &delivery.DeliveryStatus, &delivery.DeliveryComment)
+		err := scanDelivery(rows, delivery)
 		if err != nil {
 			return nil, fmt.Errorf("Scan(): %w", err)
 		}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// courierCapacityColumns selects CourierCapacity of courier c at moment $1
+const courierCapacityColumns = "(SELECT COUNT(*) FROM labwork.delivery d WHERE d.courier_id = c.id AND d.delivery_status NOT IN ('delivered', 'cancelled', 'returned')), " +
+	"c.max_active_deliveries, " +
+	"(SELECT COALESCE(SUM(d.weight_kg), 0) FROM labwork.delivery d WHERE d.courier_id = c.id AND d.delivery_status NOT IN ('delivered', 'cancelled', 'returned')), " +
+	"c.max_weight_kg, " +
+	"EXISTS (SELECT 1 FROM labwork.courier_shift s WHERE s.courier_id = c.id AND s.starts_at <= $1 AND s.ends_at > $1), " +
+	"COALESCE(c.vehicle_type, ''), " +
+	"(SELECT COALESCE(SUM(d.volume_l), 0) FROM labwork.delivery d WHERE d.courier_id = c.id AND d.delivery_status NOT IN ('delivered', 'cancelled', 'returned'))"
+
+// GetDispatchCandidates returns active couriers who may serve the zone with their capacity at the given moment,
+// the position of a courier is their last GPS fix if it is recent, otherwise the drop-off of their most recent active delivery
+func (db *PsqlConnection) GetDispatchCandidates(ctx context.Context, now time.Time, zoneId *uuid.UUID) ([]*model.DispatchCandidate, error) {
+	query := "SELECT c.id, c.userid, COALESCE(c.name, ''), COALESCE(c.surname, ''), COALESCE(c.status, ''), COALESCE(c.performance_indicator, 0), " +
+		courierCapacityColumns + ", COALESCE(g.lat, p.dropoff_lat), COALESCE(g.lon, p.dropoff_lon) " +
+		"FROM labwork.courier c LEFT JOIN LATERAL (SELECT dropoff_lat, dropoff_lon FROM labwork.delivery d " +
+		"WHERE d.courier_id = c.id AND d.delivery_status NOT IN ('delivered', 'cancelled', 'returned') AND d.dropoff_lat IS NOT NULL ORDER BY d.created_at DESC LIMIT 1) p ON true " +
+		"LEFT JOIN labwork.courier_position g ON g.courier_id = c.id AND g.recorded_at >= $1::timestamptz - interval '15 minutes' " +
+		"WHERE c.status = $2 AND " + inHomeZones("$3::uuid", "c.id")
+	rows, err := db.pool.Query(ctx, query, now, model.CourierStatusActive, zoneId)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.DispatchCandidate
+
+	for rows.Next() {
+		candidate := &model.DispatchCandidate{}
+		var lat, lon *float64
+		err := rows.Scan(&candidate.Id, &candidate.UserId, &candidate.Name, &candidate.Surname, &candidate.Status, &candidate.Perfomance_indicator,
+			&candidate.ActiveDeliveries, &candidate.MaxActiveDeliveries, &candidate.ActiveWeightKg, &candidate.MaxWeightKg, &candidate.OnShift,
+			&candidate.VehicleType, &candidate.ActiveVolumeL, &lat, &lon)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		if lat != nil && lon != nil {
+			candidate.Position = &model.GeoPoint{Lat: *lat, Lon: *lon}
+		}
+		result = append(result, candidate)
+	}
+	return result, rows.Err()
+}
+
+// GetUndispatchedDeliveryIDs returns new direct deliveries that have neither a courier nor a pending offer
+func (db *PsqlConnection) GetUndispatchedDeliveryIDs(ctx context.Context) ([]uuid.UUID, error) {
+	query := "SELECT id FROM labwork.delivery d WHERE d.courier_id IS NULL AND d.delivery_status = $1 AND d.legs = 0 " +
+		"AND NOT EXISTS (SELECT 1 FROM labwork.delivery_offer o WHERE o.delivery_id = d.id AND o.status = $2) ORDER BY d.created_at"
+	rows, err := db.pool.Query(ctx, query, model.DeliveryStatusCreated, model.OfferStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []uuid.UUID
+
+	for rows.Next() {
+		var id uuid.UUID
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, id)
+	}
+	return result, rows.Err()
+}
+
+// GetLastOfferTimes returns when each courier who had an offer for the delivery was last offered it
+func (db *PsqlConnection) GetLastOfferTimes(ctx context.Context, deliveryId uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	rows, err := db.pool.Query(ctx, "SELECT courier_id, MAX(offered_at) FROM labwork.delivery_offer WHERE delivery_id=$1 GROUP BY courier_id", deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[uuid.UUID]time.Time)
+
+	for rows.Next() {
+		var id uuid.UUID
+		var offeredAt time.Time
+		err := rows.Scan(&id, &offeredAt)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result[id] = offeredAt
+	}
+	return result, rows.Err()
+}
+
+// AssignDeliveryWithinCapacity sets the courier of an open, direct and free delivery once check accepts the
+// courier's capacity, the check and the assignment run in one transaction holding the courier row
+func (db *PsqlConnection) AssignDeliveryWithinCapacity(ctx context.Context, deliveryId uuid.UUID, courierId uuid.UUID, now time.Time,
+	check func(*model.CourierCapacity, *model.DeliveryGet) error) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = checkCapacityLocked(ctx, tx, courierId, deliveryId, now, check)
+	if err != nil {
+		return err
+	}
+	update, err := tx.Exec(ctx, "UPDATE labwork.delivery SET courier_id=$1 WHERE id=$2 AND courier_id IS NULL AND delivery_status=$3 AND legs = 0",
+		courierId, deliveryId, model.DeliveryStatusCreated)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if update.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: delivery is already taken", model.ErrConflict)
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	return nil
+}
+
+// InsertOffer stores a pending offer, a delivery that already has one returns model.ErrConflict
+func (db *PsqlConnection) InsertOffer(ctx context.Context, offer *model.DeliveryOffer) error {
+	insert := "INSERT INTO labwork.delivery_offer (id, delivery_id, courier_id, status, score, offered_at, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7) " +
+		"ON CONFLICT DO NOTHING"
+	tag, err := db.pool.Exec(ctx, insert, offer.Id, offer.DeliveryId, offer.CourierId, offer.Status, offer.Score, offer.OfferedAt, offer.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: delivery already has a pending offer", model.ErrConflict)
+	}
+	return nil
+}
+
+// ExpirePendingOffers marks offers past their deadline as expired and returns their deliveries
+func (db *PsqlConnection) ExpirePendingOffers(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	query := "UPDATE labwork.delivery_offer SET status=$1 WHERE status=$2 AND expires_at <= $3 RETURNING delivery_id"
+	rows, err := db.pool.Query(ctx, query, model.OfferStatusExpired, model.OfferStatusPending, now)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []uuid.UUID
+
+	for rows.Next() {
+		var id uuid.UUID
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, id)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// maxAccrualBatch limits the deliveries accrued in one run, the rest follow in the next run
+const maxAccrualBatch = 1000
+
+// GetUnaccruedDeliveries returns delivered deliveries whose courier has not been credited yet, couriers of multi-leg
+// deliveries are credited per leg and deliveries completed before earnings were introduced are skipped
+func (db *PsqlConnection) GetUnaccruedDeliveries(ctx context.Context) ([]*model.DeliveryGet, error) {
+	query := "SELECT " + deliveryColumns + " FROM labwork.delivery d WHERE d.delivery_status = 'delivered' AND d.courier_id IS NOT NULL " +
+		"AND d.legs = 0 AND d.accrue_earnings " +
+		"AND NOT EXISTS (SELECT 1 FROM labwork.courier_earning e WHERE e.delivery_id = d.id AND e.kind = 'delivery') " +
+		"ORDER BY d.delivered_at LIMIT $1"
+	rows, err := db.pool.Query(ctx, query, maxAccrualBatch)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.DeliveryGet
+
+	for rows.Next() {
+		delivery := &model.DeliveryGet{}
+		err := scanDelivery(rows, delivery)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, delivery)
+	}
+	return result, rows.Err()
+}
+
+// GetUnaccruedLegs returns delivered legs whose courier has not been credited yet. A leg runs from the pickup
+// address or a hub to a hub or the drop-off address
+func (db *PsqlConnection) GetUnaccruedLegs(ctx context.Context) ([]*model.DeliveredLeg, error) {
+	query := "SELECT l.id, l.delivery_id, l.courier_id, COALESCE(fh.lat, d.pickup_lat, 0), COALESCE(fh.lon, d.pickup_lon, 0), " +
+		"COALESCE(th.lat, d.dropoff_lat, 0), COALESCE(th.lon, d.dropoff_lon, 0), l.delivered_at, d.window_end, l.to_hub_id IS NULL " +
+		"FROM labwork.delivery_leg l JOIN labwork.delivery d ON d.id = l.delivery_id " +
+		"LEFT JOIN labwork.hub fh ON fh.id = l.from_hub_id LEFT JOIN labwork.hub th ON th.id = l.to_hub_id " +
+		"WHERE l.status = 'delivered' AND l.courier_id IS NOT NULL AND l.delivered_at IS NOT NULL " +
+		"AND NOT EXISTS (SELECT 1 FROM labwork.courier_earning e WHERE e.leg_id = l.id AND e.kind = 'delivery') " +
+		"ORDER BY l.delivered_at LIMIT $1"
+	rows, err := db.pool.Query(ctx, query, maxAccrualBatch)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.DeliveredLeg
+
+	for rows.Next() {
+		leg := &model.DeliveredLeg{}
+		err := rows.Scan(&leg.Id, &leg.DeliveryId, &leg.CourierId, &leg.From.Lat, &leg.From.Lon, &leg.To.Lat, &leg.To.Lon,
+			&leg.DeliveredAt, &leg.WindowEnd, &leg.Final)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, leg)
+	}
+	return result, rows.Err()
+}
+
+// InsertEarnings books earnings in one transaction, an earning of a delivery or leg that is already booked is skipped
+func (db *PsqlConnection) InsertEarnings(ctx context.Context, earnings []*model.Earning) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := "INSERT INTO labwork.courier_earning (id, courier_id, delivery_id, leg_id, kind, amount, comment, created_by, created_at) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT DO NOTHING"
+	for _, earning := range earnings {
+		_, err = tx.Exec(ctx, query, earning.Id, earning.CourierId, earning.DeliveryId, earning.LegId, earning.Kind, earning.Amount, earning.Comment,
+			earning.CreatedBy, earning.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	return nil
+}
+
+// GetFirstUnstatedEarning returns when the oldest earning not covered by a statement of the period kind was
+// booked, earnings of a courier are covered up to the end of their last statement. It returns nil when all are covered
+func (db *PsqlConnection) GetFirstUnstatedEarning(ctx context.Context, period string) (*time.Time, error) {
+	var first *time.Time
+	query := "SELECT MIN(e.created_at) FROM labwork.courier_earning e WHERE e.created_at >= COALESCE((SELECT MAX(s.period_end) " +
+		"FROM labwork.courier_statement s WHERE s.courier_id = e.courier_id AND s.period = $1), '-infinity'::timestamptz)"
+	err := db.pool.QueryRow(ctx, query, period).Scan(&first)
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return first, nil
+}
+
+// GenerateStatements sums the earnings booked within [start, end) per courier into statements,
+// couriers who already have a statement for the period keep it. It returns the number of new statements
+func (db *PsqlConnection) GenerateStatements(ctx context.Context, period string, start time.Time, end time.Time, generatedAt time.Time) (int64, error) {
+	query := "INSERT INTO labwork.courier_statement (id, courier_id, period, period_start, period_end, deliveries, " +
+		"delivery_amount, bonus_amount, adjustment_amount, penalty_amount, total, generated_at) " +
+		"SELECT gen_random_uuid(), courier_id, $1, $2, $3, COUNT(*) FILTER (WHERE kind = 'delivery'), " +
+		"COALESCE(SUM(amount) FILTER (WHERE kind = 'delivery'), 0), COALESCE(SUM(amount) FILTER (WHERE kind = 'on_time_bonus'), 0), " +
+		"COALESCE(SUM(amount) FILTER (WHERE kind = 'adjustment'), 0), COALESCE(SUM(amount) FILTER (WHERE kind = 'penalty'), 0), SUM(amount), $4 " +
+		"FROM labwork.courier_earning WHERE created_at >= $2 AND created_at < $3 GROUP BY courier_id " +
+		"ON CONFLICT (courier_id, period, period_start) DO NOTHING"
+	tag, err := db.pool.Exec(ctx, query, period, start, end, generatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("Exec(): %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// statementColumns lists statement columns in the order scanStatement expects, s is joined with courier c
+const statementColumns = "s.id, s.courier_id, c.userid, COALESCE(c.name, ''), COALESCE(c.surname, ''), s.period, s.period_start, s.period_end, " +
+	"s.deliveries, s.delivery_amount, s.bonus_amount, s.adjustment_amount, s.penalty_amount, s.total, s.generated_at"
+
+func scanStatement(row pgx.Row, statement *model.Statement) error {
+	return row.Scan(&statement.Id, &statement.CourierId, &statement.UserId, &statement.Name, &statement.Surname, &statement.Period,
+		&statement.PeriodStart, &statement.PeriodEnd, &statement.Deliveries, &statement.DeliveryAmount, &statement.BonusAmount,
+		&statement.AdjustmentAmount, &statement.PenaltyAmount, &statement.Total, &statement.GeneratedAt)
+}
+
+// GetStatements returns statements of the courier, or of all couriers when courierId is nil, newest period first
+func (db *PsqlConnection) GetStatements(ctx context.Context, courierId *uuid.UUID, filter *model.StatementFilter) ([]*model.Statement, error) {
+	query := "SELECT " + statementColumns + " FROM labwork.courier_statement s JOIN labwork.courier c ON c.id = s.courier_id " +
+		"WHERE s.period = $1 AND ($2::uuid IS NULL OR s.courier_id = $2) " +
+		"AND ($3::timestamptz IS NULL OR s.period_start >= $3) AND ($4::timestamptz IS NULL OR s.period_start <= $4) " +
+		"ORDER BY s.period_start DESC, c.surname, c.name"
+	rows, err := db.pool.Query(ctx, query, filter.Period, courierId, filter.From, filter.To)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.Statement
+
+	for rows.Next() {
+		statement := &model.Statement{}
+		err := scanStatement(rows, statement)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, statement)
+	}
+	return result, rows.Err()
+}
+
+func (db *PsqlConnection) GetStatement(ctx context.Context, statementId uuid.UUID) (*model.Statement, error) {
+	statement := &model.Statement{}
+	query := "SELECT " + statementColumns + " FROM labwork.courier_statement s JOIN labwork.courier c ON c.id = s.courier_id WHERE s.id = $1"
+	err := scanStatement(db.pool.QueryRow(ctx, query, statementId), statement)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return statement, nil
+}
+
+// GetEarnings returns the courier's earnings booked within [from, to), oldest first
+func (db *PsqlConnection) GetEarnings(ctx context.Context, courierId uuid.UUID, from time.Time, to time.Time) ([]*model.Earning, error) {
+	query := "SELECT id, courier_id, delivery_id, leg_id, kind, amount, comment, created_by, created_at FROM labwork.courier_earning " +
+		"WHERE courier_id = $1 AND created_at >= $2 AND created_at < $3 ORDER BY created_at, kind"
+	rows, err := db.pool.Query(ctx, query, courierId, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.Earning
+
+	for rows.Next() {
+		earning := &model.Earning{}
+		err := rows.Scan(&earning.Id, &earning.CourierId, &earning.DeliveryId, &earning.LegId, &earning.Kind, &earning.Amount, &earning.Comment,
+			&earning.CreatedBy, &earning.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, earning)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// GetCourierRoute returns the vehicle, last known position and active deliveries of the courier
+func (db *PsqlConnection) GetCourierRoute(ctx context.Context, courierId uuid.UUID) (*model.CourierRoute, error) {
+	route := &model.CourierRoute{CourierId: courierId}
+	var lat, lon *float64
+	query := "SELECT COALESCE(c.vehicle_type, ''), p.lat, p.lon FROM labwork.courier c " +
+		"LEFT JOIN labwork.courier_position p ON p.courier_id = c.id WHERE c.id = $1"
+	err := db.pool.QueryRow(ctx, query, courierId).Scan(&route.VehicleType, &lat, &lon)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	if lat != nil && lon != nil {
+		route.Position = &model.GeoPoint{Lat: *lat, Lon: *lon}
+	}
+
+	query = "SELECT " + deliveryColumns + " FROM labwork.delivery " +
+		"WHERE courier_id = $1 AND delivery_status NOT IN ('delivered', 'cancelled', 'returned') ORDER BY created_at"
+	rows, err := db.pool.Query(ctx, query, courierId)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		delivery := &model.DeliveryGet{}
+		err := scanDelivery(rows, delivery)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		route.Deliveries = append(route.Deliveries, delivery)
+	}
+	return route, rows.Err()
+}
+
+// GetZoneSpeeds returns average pickup to drop-off speeds of deliveries completed since the given moment,
+// zones with fewer than minSamples deliveries are left out
+func (db *PsqlConnection) GetZoneSpeeds(ctx context.Context, since time.Time, minSamples int) ([]*model.ZoneSpeed, error) {
+	query := "SELECT d.dropoff_city, c.vehicle_type, AVG(" +
+		"2 * 6371 * asin(sqrt(power(sin(radians(d.dropoff_lat - d.pickup_lat) / 2), 2) + " +
+		"cos(radians(d.pickup_lat)) * cos(radians(d.dropoff_lat)) * power(sin(radians(d.dropoff_lon - d.pickup_lon) / 2), 2))) " +
+		"/ (EXTRACT(EPOCH FROM d.delivered_at - d.picked_up_at) / 3600)) " +
+		"FROM labwork.delivery d JOIN labwork.courier c ON c.id = d.courier_id " +
+		"WHERE d.delivery_status = 'delivered' AND d.delivered_at >= $1 AND d.delivered_at > d.picked_up_at " +
+		"AND d.pickup_lat IS NOT NULL AND d.dropoff_lat IS NOT NULL AND c.vehicle_type IS NOT NULL " +
+		"GROUP BY d.dropoff_city, c.vehicle_type HAVING COUNT(*) >= $2"
+	rows, err := db.pool.Query(ctx, query, since, minSamples)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.ZoneSpeed
+
+	for rows.Next() {
+		speed := &model.ZoneSpeed{}
+		err := rows.Scan(&speed.Zone, &speed.VehicleType, &speed.SpeedKmh)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, speed)
+	}
+	return result, rows.Err()
+}
+
+// UpdateDeliveryETAs stores estimated drop-off times in one transaction
+func (db *PsqlConnection) UpdateDeliveryETAs(ctx context.Context, etas map[uuid.UUID]time.Time) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for deliveryId, eta := range etas {
+		_, err = tx.Exec(ctx, "UPDATE labwork.delivery SET eta=$1 WHERE id=$2", eta, deliveryId)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	return nil
+}
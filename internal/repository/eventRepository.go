@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// deliveryEventsChannel is filled by the triggers from the V12 migration
+const deliveryEventsChannel = "delivery_events"
+
+// ListenDeliveryEvents holds a connection listening for delivery events and passes every payload to handle,
+// it returns when ctx is cancelled or the connection fails
+func (db *PsqlConnection) ListenDeliveryEvents(ctx context.Context, handle func(payload string)) error {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("Acquire(): %w", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, "LISTEN "+deliveryEventsChannel)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("WaitForNotification(): %w", err)
+		}
+		handle(notification.Payload)
+	}
+}
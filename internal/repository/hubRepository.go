@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+const hubColumns = "id, code, name, address_line1, address_line2, city, postcode, lat, lon, active, created_at"
+
+func scanHub(row pgx.Row, hub *model.Hub) error {
+	return row.Scan(&hub.Id, &hub.Code, &hub.Name, &hub.Location.AddressLine1, &hub.Location.AddressLine2, &hub.Location.City,
+		&hub.Location.Postcode, &hub.Location.Lat, &hub.Location.Lon, &hub.Active, &hub.CreatedAt)
+}
+
+const legColumns = "l.id, l.delivery_id, l.position, l.from_hub_id, l.to_hub_id, l.courier_id, l.status, l.created_at, l.picked_up_at, l.delivered_at, l.version"
+
+func scanLeg(row pgx.Row, leg *model.DeliveryLeg) error {
+	return row.Scan(&leg.Id, &leg.DeliveryId, &leg.Position, &leg.FromHubId, &leg.ToHubId, &leg.CourierId, &leg.Status,
+		&leg.CreatedAt, &leg.PickedUpAt, &leg.DeliveredAt, &leg.Version)
+}
+
+// InsertHub returns model.ErrConflict when the code is taken
+func (db *PsqlConnection) InsertHub(ctx context.Context, hub *model.Hub) error {
+	query := "INSERT INTO labwork.hub (" + hubColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) ON CONFLICT (code) DO NOTHING"
+	tag, err := db.pool.Exec(ctx, query, hub.Id, hub.Code, hub.Name, hub.Location.AddressLine1, hub.Location.AddressLine2, hub.Location.City,
+		hub.Location.Postcode, hub.Location.Lat, hub.Location.Lon, hub.Active, hub.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: hub %s already exists", model.ErrConflict, hub.Code)
+	}
+	return nil
+}
+
+func (db *PsqlConnection) GetHub(ctx context.Context, hubId uuid.UUID) (*model.Hub, error) {
+	hub := &model.Hub{}
+	err := scanHub(db.pool.QueryRow(ctx, "SELECT "+hubColumns+" FROM labwork.hub WHERE id=$1", hubId), hub)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return hub, nil
+}
+
+// GetHubs returns hubs ordered by code
+func (db *PsqlConnection) GetHubs(ctx context.Context) ([]*model.Hub, error) {
+	rows, err := db.pool.Query(ctx, "SELECT "+hubColumns+" FROM labwork.hub ORDER BY code")
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.Hub
+
+	for rows.Next() {
+		hub := &model.Hub{}
+		err := scanHub(rows, hub)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, hub)
+	}
+	return result, rows.Err()
+}
+
+// SetHubStaff assigns the user to the hub, replacing their previous hub
+func (db *PsqlConnection) SetHubStaff(ctx context.Context, staff *model.HubStaff) error {
+	_, err := db.pool.Exec(ctx, "INSERT INTO labwork.hub_staff (user_id, hub_id) VALUES ($1, $2) ON CONFLICT (user_id) DO UPDATE SET hub_id=EXCLUDED.hub_id",
+		staff.UserId, staff.HubId)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	return nil
+}
+
+// GetStaffHub returns model.ErrForbidden for users that are not assigned to a hub
+func (db *PsqlConnection) GetStaffHub(ctx context.Context, userId uuid.UUID) (uuid.UUID, error) {
+	var hubId uuid.UUID
+	err := db.pool.QueryRow(ctx, "SELECT hub_id FROM labwork.hub_staff WHERE user_id=$1", userId).Scan(&hubId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, fmt.Errorf("QueryRow(): %w: user is not assigned to a hub", model.ErrForbidden)
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return hubId, nil
+}
+
+// InsertLegs stores the legs of a delivery, it returns model.ErrConflict when the delivery
+// has been assigned, picked up or routed meanwhile
+func (db *PsqlConnection) InsertLegs(ctx context.Context, deliveryId uuid.UUID, legs []*model.DeliveryLeg) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, "UPDATE labwork.delivery SET legs=$1 WHERE id=$2 AND legs = 0 AND courier_id IS NULL AND delivery_status=$3",
+		len(legs), deliveryId, model.DeliveryStatusCreated)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: delivery changed", model.ErrConflict)
+	}
+	for _, leg := range legs {
+		_, err = tx.Exec(ctx, "INSERT INTO labwork.delivery_leg (id, delivery_id, position, from_hub_id, to_hub_id, status, created_at) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7)", leg.Id, leg.DeliveryId, leg.Position, leg.FromHubId, leg.ToHubId, leg.Status, leg.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+	}
+	// offers for the whole delivery are no longer valid
+	err = withdrawPendingOffers(ctx, tx, deliveryId, legs[0].CreatedAt)
+	if err != nil {
+		return err
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	return nil
+}
+
+func (db *PsqlConnection) GetLeg(ctx context.Context, legId uuid.UUID) (*model.DeliveryLeg, error) {
+	leg := &model.DeliveryLeg{}
+	err := scanLeg(db.pool.QueryRow(ctx, "SELECT "+legColumns+" FROM labwork.delivery_leg l WHERE l.id=$1", legId), leg)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return leg, nil
+}
+
+// GetDeliveryLegs returns the legs of a delivery ordered by position
+func (db *PsqlConnection) GetDeliveryLegs(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryLeg, error) {
+	return db.queryLegs(ctx, "SELECT "+legColumns+" FROM labwork.delivery_leg l WHERE l.delivery_id=$1 ORDER BY l.position", deliveryId)
+}
+
+// GetCourierLegs returns unfinished legs of open deliveries that are free or assigned to the courier
+func (db *PsqlConnection) GetCourierLegs(ctx context.Context, courierId uuid.UUID) ([]*model.DeliveryLeg, error) {
+	query := "SELECT " + legColumns + " FROM labwork.delivery_leg l JOIN labwork.delivery d ON d.id = l.delivery_id " +
+		"WHERE l.status IN ('created', 'picked_up') AND (l.courier_id IS NULL OR l.courier_id = $1) " +
+		"AND d.delivery_status NOT IN ('delivered', 'cancelled', 'returned') ORDER BY l.created_at, l.position"
+	return db.queryLegs(ctx, query, courierId)
+}
+
+// GetHubInventory returns the legs leaving the hub whose previous leg has been checked in there
+func (db *PsqlConnection) GetHubInventory(ctx context.Context, hubId uuid.UUID) ([]*model.DeliveryLeg, error) {
+	query := "SELECT " + legColumns + " FROM labwork.delivery_leg l JOIN labwork.delivery_leg p ON p.delivery_id = l.delivery_id AND p.position = l.position - 1 " +
+		"WHERE l.from_hub_id=$1 AND l.status = 'created' AND p.status = 'delivered' ORDER BY p.delivered_at"
+	return db.queryLegs(ctx, query, hubId)
+}
+
+func (db *PsqlConnection) queryLegs(ctx context.Context, query string, args ...interface{}) ([]*model.DeliveryLeg, error) {
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.DeliveryLeg
+
+	for rows.Next() {
+		leg := &model.DeliveryLeg{}
+		err := scanLeg(rows, leg)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, leg)
+	}
+	return result, rows.Err()
+}
+
+// UpdateLeg stores the leg if nobody changed it meanwhile and copies the derived progress onto its open delivery,
+// otherwise model.ErrConflict is returned
+func (db *PsqlConnection) UpdateLeg(ctx context.Context, leg *model.DeliveryLeg, progress *model.LegProgress) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, "UPDATE labwork.delivery_leg SET courier_id=$1, status=$2, picked_up_at=$3, delivered_at=$4, version = version + 1 "+
+		"WHERE id=$5 AND version=$6", leg.CourierId, leg.Status, leg.PickedUpAt, leg.DeliveredAt, leg.Id, leg.Version)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: leg changed", model.ErrConflict)
+	}
+	tag, err = tx.Exec(ctx, "UPDATE labwork.delivery SET delivery_status=$1, courier_id=$2, picked_up_at=$3, delivered_at=COALESCE($4, delivered_at) "+
+		"WHERE id=$5 AND delivery_status NOT IN ('delivered', 'cancelled', 'returned')",
+		progress.Status, progress.CourierId, progress.PickedUpAt, progress.DeliveredAt, leg.DeliveryId)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: delivery is closed", model.ErrConflict)
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	leg.Version++
+	return nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+var importDeliveryColumns = []string{"id", "client_id", "delivery_status", "delivery_comment", "created_at", "window_start", "window_end",
+	"pickup_address_line1", "pickup_address_line2", "pickup_city", "pickup_postcode", "pickup_lat", "pickup_lon",
+	"dropoff_address_line1", "dropoff_address_line2", "dropoff_city", "dropoff_postcode", "dropoff_lat", "dropoff_lon",
+	"recipient_name", "recipient_phone", "access_notes", "weight_kg", "item_count", "volume_l", "max_side_cm", "declared_value",
+	"fragile", "temperature", "express", "price", "cod_amount", "zone_id"}
+
+var importPriceColumns = []string{"delivery_id", "tariff_id", "distance_km", "weight_kg", "base_fee", "distance_fee", "weight_fee",
+	"express_surcharge", "fragile_surcharge", "night_surcharge", "discount", "promo_code", "total", "quoted_at"}
+
+// ImportDeliveries inserts priced deliveries without items in one transaction, copying batchSize deliveries at a time,
+// and sets their ids. Nothing is inserted when a promo code has run out of uses since the rows were validated
+func (db *PsqlConnection) ImportDeliveries(ctx context.Context, deliveries []*model.Delivery, batchSize int) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, delivery := range deliveries {
+		delivery.Id = uuid.New()
+	}
+	for start := 0; start < len(deliveries); start += batchSize {
+		end := start + batchSize
+		if end > len(deliveries) {
+			end = len(deliveries)
+		}
+		err = copyDeliveries(ctx, tx, deliveries[start:end])
+		if err != nil {
+			return err
+		}
+	}
+	promoUses := make(map[string]int)
+	for _, delivery := range deliveries {
+		if delivery.Price != nil && delivery.Price.PromoCode != "" {
+			promoUses[delivery.Price.PromoCode]++
+		}
+	}
+	for code, uses := range promoUses {
+		tag, err := tx.Exec(ctx, "UPDATE labwork.promo_code SET used = used + $1 WHERE code=$2 AND (max_uses IS NULL OR used + $1 <= max_uses)", uses, code)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("Exec(): %w: promo code %s has fewer than %d uses left", model.ErrConflict, code, uses)
+		}
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	return nil
+}
+
+// copyDeliveries copies deliveries with their prices, tracking codes and PINs within tx
+func copyDeliveries(ctx context.Context, tx pgx.Tx, deliveries []*model.Delivery) error {
+	deliveryRows := make([][]interface{}, 0, len(deliveries))
+	var priceRows, codeRows, pinRows [][]interface{}
+	for _, d := range deliveries {
+		var price *float64
+		if d.Price != nil {
+			price = &d.Price.Total
+			var promo *string
+			if d.Price.PromoCode != "" {
+				promo = &d.Price.PromoCode
+			}
+			d.Price.DeliveryId = d.Id
+			priceRows = append(priceRows, []interface{}{d.Id, d.Price.TariffId, d.Price.DistanceKm, d.Price.WeightKg, d.Price.BaseFee,
+				d.Price.DistanceFee, d.Price.WeightFee, d.Price.ExpressSurcharge, d.Price.FragileSurcharge, d.Price.NightSurcharge,
+				d.Price.Discount, promo, d.Price.Total, d.Price.QuotedAt})
+		}
+		deliveryRows = append(deliveryRows, []interface{}{d.Id, d.ClientId, d.DeliveryStatus, d.DeliveryComment, d.CreatedAt, d.WindowStart, d.WindowEnd,
+			d.Pickup.AddressLine1, d.Pickup.AddressLine2, d.Pickup.City, d.Pickup.Postcode, d.Pickup.Lat, d.Pickup.Lon,
+			d.Dropoff.AddressLine1, d.Dropoff.AddressLine2, d.Dropoff.City, d.Dropoff.Postcode, d.Dropoff.Lat, d.Dropoff.Lon,
+			d.Recipient.Name, d.Recipient.Phone, d.Recipient.AccessNotes, d.WeightKg, d.ItemCount, d.VolumeL, d.MaxSideCm, d.DeclaredValue,
+			d.Fragile, d.Temperature, d.Express, price, d.CodAmount, d.ZoneId})
+		if d.TrackingCode != "" {
+			codeRows = append(codeRows, []interface{}{d.TrackingCode, d.Id, d.CreatedAt})
+		}
+		if d.Pin != "" {
+			pinRows = append(pinRows, []interface{}{d.Id, d.Pin})
+		}
+	}
+	copies := []struct {
+		table   string
+		columns []string
+		rows    [][]interface{}
+	}{
+		{"delivery", importDeliveryColumns, deliveryRows},
+		{"delivery_price", importPriceColumns, priceRows},
+		{"tracking_code", []string{"code", "delivery_id", "created_at"}, codeRows},
+		{"delivery_pin", []string{"delivery_id", "pin"}, pinRows},
+	}
+	for _, c := range copies {
+		if len(c.rows) == 0 {
+			continue
+		}
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{"labwork", c.table}, c.columns, pgx.CopyFromRows(c.rows))
+		if err != nil {
+			return fmt.Errorf("CopyFrom(%s): %w", c.table, err)
+		}
+	}
+	return nil
+}
+
+// IsClient reports whether a user with the client role has the given id
+func (db *PsqlConnection) IsClient(ctx context.Context, userId uuid.UUID) (bool, error) {
+	var known bool
+	err := db.pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM labwork.user WHERE id=$1 AND role='Client')", userId).Scan(&known)
+	if err != nil {
+		return false, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return known, nil
+}
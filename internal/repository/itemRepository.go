@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// GetDeliveryItems returns the contents of a delivery in the order they were given
+func (db *PsqlConnection) GetDeliveryItems(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryItem, error) {
+	query := "SELECT id, delivery_id, description, quantity, weight_kg, length_cm, width_cm, height_cm, declared_value, fragile, temperature " +
+		"FROM labwork.delivery_item WHERE delivery_id=$1 ORDER BY position"
+	rows, err := db.pool.Query(ctx, query, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.DeliveryItem
+
+	for rows.Next() {
+		item := &model.DeliveryItem{}
+		err := rows.Scan(&item.Id, &item.DeliveryId, &item.Description, &item.Quantity, &item.WeightKg, &item.LengthCm, &item.WidthCm, &item.HeightCm,
+			&item.DeclaredValue, &item.Fragile, &item.Temperature)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+func (db *PsqlConnection) GetCourierRoster(ctx context.Context) ([]*model.CourierLoad, error) {
+	query := "SELECT c.id, c.userid, COALESCE(c.name, ''), COALESCE(c.surname, ''), COALESCE(c.status, ''), COALESCE(c.performance_indicator, 0), " +
+		"COUNT(d.id) FROM labwork.courier c " +
+		"LEFT JOIN labwork.delivery d ON d.courier_id = c.id AND d.delivery_status NOT IN ($1, $2, $3) " +
+		"GROUP BY c.id ORDER BY c.surname, c.name"
+	rows, err := db.pool.Query(ctx, query, model.DeliveryStatusDelivered, model.DeliveryStatusCancelled, model.DeliveryStatusReturned)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.CourierLoad
+
+	for rows.Next() {
+		courier := &model.CourierLoad{}
+		err := rows.Scan(&courier.Id, &courier.UserId, &courier.Name, &courier.Surname, &courier.Status, &courier.Perfomance_indicator, &courier.ActiveDeliveries)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, courier)
+	}
+	return result, rows.Err()
+}
+
+// AssignDeliveryCourier sets the courier of a delivery and withdraws its pending offers so that nobody else accepts it
+func (db *PsqlConnection) AssignDeliveryCourier(ctx context.Context, deliveryId uuid.UUID, courierId uuid.UUID, now time.Time) error {
+	return db.UpdateDeliveryCourier(ctx, deliveryId, &courierId, now)
+}
+
+// UnassignDeliveryCourier returns a delivery to the pool and withdraws its pending offers, dispatch offers it anew
+func (db *PsqlConnection) UnassignDeliveryCourier(ctx context.Context, deliveryId uuid.UUID, now time.Time) error {
+	return db.UpdateDeliveryCourier(ctx, deliveryId, nil, now)
+}
+
+// UpdateCourierStatus returns model.ErrNotFound when there is no courier for the user
+func (db *PsqlConnection) UpdateCourierStatus(ctx context.Context, userId uuid.UUID, status string) error {
+	update, err := db.pool.Exec(ctx, "UPDATE labwork.courier SET status=$1 WHERE userid=$2", status, userId)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if update.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w", model.ErrNotFound)
+	}
+	return nil
+}
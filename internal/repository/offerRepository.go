@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+func (db *PsqlConnection) GetPendingOffersByCourier(ctx context.Context, courierId uuid.UUID, now time.Time) ([]*model.OfferView, error) {
+	query := "SELECT o.id, o.delivery_id, o.courier_id, o.status, o.score, o.offered_at, o.expires_at, o.responded_at, " +
+		"d.pickup_address_line1, d.pickup_address_line2, d.pickup_city, d.pickup_postcode, COALESCE(d.pickup_lat, 0), COALESCE(d.pickup_lon, 0), " +
+		"d.dropoff_address_line1, d.dropoff_address_line2, d.dropoff_city, d.dropoff_postcode, COALESCE(d.dropoff_lat, 0), COALESCE(d.dropoff_lon, 0), " +
+		"d.window_start, d.window_end " +
+		"FROM labwork.delivery_offer o JOIN labwork.delivery d ON d.id = o.delivery_id " +
+		"WHERE o.courier_id=$1 AND o.status=$2 AND o.expires_at > $3 ORDER BY o.expires_at"
+	rows, err := db.pool.Query(ctx, query, courierId, model.OfferStatusPending, now)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.OfferView
+
+	for rows.Next() {
+		offer := &model.OfferView{}
+		err := rows.Scan(&offer.Id, &offer.DeliveryId, &offer.CourierId, &offer.Status, &offer.Score, &offer.OfferedAt, &offer.ExpiresAt, &offer.RespondedAt,
+			&offer.Pickup.AddressLine1, &offer.Pickup.AddressLine2, &offer.Pickup.City, &offer.Pickup.Postcode, &offer.Pickup.Lat, &offer.Pickup.Lon,
+			&offer.Dropoff.AddressLine1, &offer.Dropoff.AddressLine2, &offer.Dropoff.City, &offer.Dropoff.Postcode, &offer.Dropoff.Lat, &offer.Dropoff.Lon,
+			&offer.WindowStart, &offer.WindowEnd)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, offer)
+	}
+	return result, rows.Err()
+}
+
+// AcceptOffer closes a pending, not expired offer of the courier and assigns its delivery to them in one transaction
+// once check accepts the courier's capacity, a delivery outside the courier's home zones is not assigned
+func (db *PsqlConnection) AcceptOffer(ctx context.Context, offerId uuid.UUID, courierId uuid.UUID, now time.Time,
+	check func(*model.CourierCapacity, *model.DeliveryGet) error) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var deliveryId uuid.UUID
+	query := "UPDATE labwork.delivery_offer SET status=$1, responded_at=$2 WHERE id=$3 AND courier_id=$4 AND status=$5 AND expires_at > $2 RETURNING delivery_id"
+	err = tx.QueryRow(ctx, query, model.OfferStatusAccepted, now, offerId, courierId, model.OfferStatusPending).Scan(&deliveryId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("QueryRow(): %w: offer is not pending", model.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("QueryRow(): %w", err)
+	}
+	err = checkCapacityLocked(ctx, tx, courierId, deliveryId, now, check)
+	if err != nil {
+		return err
+	}
+	update, err := tx.Exec(ctx, "UPDATE labwork.delivery SET courier_id=$1 WHERE id=$2 AND courier_id IS NULL AND "+inHomeZones("zone_id", "$1"),
+		courierId, deliveryId)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if update.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: delivery is already taken or outside the courier's home zones", model.ErrConflict)
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	return nil
+}
+
+// DeclineOffer closes a pending offer of the courier and returns its delivery
+func (db *PsqlConnection) DeclineOffer(ctx context.Context, offerId uuid.UUID, courierId uuid.UUID, now time.Time) (uuid.UUID, error) {
+	var deliveryId uuid.UUID
+	query := "UPDATE labwork.delivery_offer SET status=$1, responded_at=$2 WHERE id=$3 AND courier_id=$4 AND status=$5 RETURNING delivery_id"
+	err := db.pool.QueryRow(ctx, query, model.OfferStatusDeclined, now, offerId, courierId, model.OfferStatusPending).Scan(&deliveryId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, fmt.Errorf("QueryRow(): %w: offer is not pending", model.ErrConflict)
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return deliveryId, nil
+}
+
+// withdrawPendingOffers closes the pending offers of a delivery within tx
+func withdrawPendingOffers(ctx context.Context, tx pgx.Tx, deliveryId uuid.UUID, now time.Time) error {
+	_, err := tx.Exec(ctx, "UPDATE labwork.delivery_offer SET status=$1, responded_at=$2 WHERE delivery_id=$3 AND status=$4",
+		model.OfferStatusWithdrawn, now, deliveryId, model.OfferStatusPending)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	return nil
+}
+
+// GetOfferMetrics counts closed offers per courier since the given moment
+func (db *PsqlConnection) GetOfferMetrics(ctx context.Context, since time.Time) ([]*model.OfferMetrics, error) {
+	query := "SELECT c.id, COALESCE(c.name, ''), COALESCE(c.surname, ''), COUNT(o.id), " +
+		"COUNT(o.id) FILTER (WHERE o.status = $1), COUNT(o.id) FILTER (WHERE o.status = $2), COUNT(o.id) FILTER (WHERE o.status = $3) " +
+		"FROM labwork.courier c JOIN labwork.delivery_offer o ON o.courier_id = c.id AND o.offered_at >= $4 " +
+		"GROUP BY c.id ORDER BY c.surname, c.name"
+	rows, err := db.pool.Query(ctx, query, model.OfferStatusAccepted, model.OfferStatusDeclined, model.OfferStatusExpired, since)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.OfferMetrics
+
+	for rows.Next() {
+		metrics := &model.OfferMetrics{}
+		err := rows.Scan(&metrics.CourierId, &metrics.Name, &metrics.Surname, &metrics.Offered, &metrics.Accepted, &metrics.Declined, &metrics.Expired)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, metrics)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// GetPerformanceStats counts delivery and offer outcomes of every courier since the given moment,
+// a delivery cancelled after pickup or returned to the sender counts as failed, cancelled deliveries
+// are no longer assigned so they are attributed through the courier recorded with the cancellation,
+// only published ratings count
+func (db *PsqlConnection) GetPerformanceStats(ctx context.Context, since time.Time) ([]*model.PerformanceStats, error) {
+	query := "SELECT c.id, " +
+		"COUNT(d.id) FILTER (WHERE d.delivery_status = 'delivered' AND d.delivered_at >= $1), " +
+		"COUNT(d.id) FILTER (WHERE d.delivery_status = 'delivered' AND d.delivered_at >= $1 AND (d.window_end IS NULL OR d.delivered_at <= d.window_end)), " +
+		"COUNT(d.id) FILTER (WHERE d.delivery_status IN ('cancelled', 'returned') AND d.picked_up_at >= $1), " +
+		"(SELECT COUNT(*) FROM labwork.delivery_cancellation x WHERE x.courier_id = c.id AND x.state = 'picked_up' AND x.cancelled_at >= $1), " +
+		"(SELECT COUNT(*) FROM labwork.delivery_offer o WHERE o.courier_id = c.id AND o.status NOT IN ('pending', 'withdrawn') AND o.offered_at >= $1), " +
+		"(SELECT COUNT(*) FROM labwork.delivery_offer o WHERE o.courier_id = c.id AND o.status = 'accepted' AND o.offered_at >= $1), " +
+		"(SELECT COUNT(*) FROM labwork.delivery_rating r WHERE r.courier_id = c.id AND r.status = 'published' AND r.created_at >= $1), " +
+		"(SELECT AVG(r.score)::float8 FROM labwork.delivery_rating r WHERE r.courier_id = c.id AND r.status = 'published' AND r.created_at >= $1) " +
+		"FROM labwork.courier c LEFT JOIN labwork.delivery d ON d.courier_id = c.id GROUP BY c.id"
+	rows, err := db.pool.Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.PerformanceStats
+
+	for rows.Next() {
+		stats := &model.PerformanceStats{}
+		var delivered, cancelled int
+		err := rows.Scan(&stats.CourierId, &delivered, &stats.OnTime, &stats.Failed, &cancelled, &stats.Offered, &stats.Accepted, &stats.Ratings, &stats.AverageRating)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		stats.Failed += cancelled
+		stats.Completed = delivered + stats.Failed
+		result = append(result, stats)
+	}
+	return result, rows.Err()
+}
+
+// SavePerformance stores breakdowns and copies the indicators to the couriers in one transaction
+func (db *PsqlConnection) SavePerformance(ctx context.Context, breakdowns []*model.PerformanceBreakdown) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	upsert := "INSERT INTO labwork.courier_performance (courier_id, performance_indicator, on_time_rate, failed_rate, acceptance_rate, average_rating, " +
+		"completed, on_time, failed, offered, accepted, ratings, window_start, calculated_at) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) " +
+		"ON CONFLICT (courier_id) DO UPDATE SET performance_indicator=EXCLUDED.performance_indicator, on_time_rate=EXCLUDED.on_time_rate, " +
+		"failed_rate=EXCLUDED.failed_rate, acceptance_rate=EXCLUDED.acceptance_rate, average_rating=EXCLUDED.average_rating, " +
+		"completed=EXCLUDED.completed, on_time=EXCLUDED.on_time, failed=EXCLUDED.failed, offered=EXCLUDED.offered, accepted=EXCLUDED.accepted, " +
+		"ratings=EXCLUDED.ratings, window_start=EXCLUDED.window_start, calculated_at=EXCLUDED.calculated_at"
+	for _, b := range breakdowns {
+		_, err = tx.Exec(ctx, upsert, b.CourierId, b.Indicator, b.OnTimeRate, b.FailedRate, b.AcceptanceRate, b.AverageRating,
+			b.Completed, b.OnTime, b.Failed, b.Offered, b.Accepted, b.Ratings, b.WindowStart, b.CalculatedAt)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+		_, err = tx.Exec(ctx, "UPDATE labwork.courier SET performance_indicator=$1 WHERE id=$2", b.Indicator, b.CourierId)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	return nil
+}
+
+// GetPerformanceBreakdown returns model.ErrNotFound when the indicator has not been calculated yet
+func (db *PsqlConnection) GetPerformanceBreakdown(ctx context.Context, courierId uuid.UUID) (*model.PerformanceBreakdown, error) {
+	b := &model.PerformanceBreakdown{}
+	query := "SELECT courier_id, performance_indicator, on_time_rate, failed_rate, acceptance_rate, average_rating, " +
+		"completed, on_time, failed, offered, accepted, ratings, window_start, calculated_at " +
+		"FROM labwork.courier_performance WHERE courier_id=$1"
+	err := db.pool.QueryRow(ctx, query, courierId).Scan(&b.CourierId, &b.Indicator, &b.OnTimeRate, &b.FailedRate, &b.AcceptanceRate, &b.AverageRating,
+		&b.Completed, &b.OnTime, &b.Failed, &b.Offered, &b.Accepted, &b.Ratings, &b.WindowStart, &b.CalculatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// GetTrackTails returns the last track point of every active delivery of the courier,
+// the value is nil for deliveries without a track yet
+func (db *PsqlConnection) GetTrackTails(ctx context.Context, courierId uuid.UUID) (map[uuid.UUID]*model.TrackPoint, error) {
+	query := "SELECT d.id, t.lat, t.lon, t.recorded_at FROM labwork.delivery d " +
+		"LEFT JOIN LATERAL (SELECT lat, lon, recorded_at FROM labwork.delivery_track WHERE delivery_id = d.id ORDER BY recorded_at DESC LIMIT 1) t ON true " +
+		"WHERE d.courier_id = $1 AND d.delivery_status NOT IN ('delivered', 'cancelled', 'returned')"
+	rows, err := db.pool.Query(ctx, query, courierId)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[uuid.UUID]*model.TrackPoint)
+
+	for rows.Next() {
+		var deliveryId uuid.UUID
+		var lat, lon *float64
+		var recordedAt *time.Time
+		err := rows.Scan(&deliveryId, &lat, &lon, &recordedAt)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result[deliveryId] = nil
+		if recordedAt != nil {
+			result[deliveryId] = &model.TrackPoint{DeliveryId: deliveryId, Lat: *lat, Lon: *lon, RecordedAt: *recordedAt}
+		}
+	}
+	return result, rows.Err()
+}
+
+// SaveLocationBatch stores raw fixes and track points and moves the last known position forward in one transaction.
+// Fixes must be sorted by time, duplicates are ignored and an older fix never replaces a newer position
+func (db *PsqlConnection) SaveLocationBatch(ctx context.Context, courierId uuid.UUID, fixes []model.LocationFix, track []*model.TrackPoint, receivedAt time.Time) error {
+	if len(fixes) == 0 {
+		return nil
+	}
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	insertFix := "INSERT INTO labwork.courier_location (courier_id, recorded_at, lat, lon, accuracy_m, speed_mps, received_at) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (courier_id, recorded_at) DO NOTHING"
+	for _, fix := range fixes {
+		_, err = tx.Exec(ctx, insertFix, courierId, fix.RecordedAt, fix.Lat, fix.Lon, fix.AccuracyM, fix.SpeedMps, receivedAt)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+	}
+
+	last := fixes[len(fixes)-1]
+	upsertPosition := "INSERT INTO labwork.courier_position (courier_id, recorded_at, lat, lon, accuracy_m, speed_mps, received_at) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (courier_id) DO UPDATE SET recorded_at=EXCLUDED.recorded_at, lat=EXCLUDED.lat, lon=EXCLUDED.lon, " +
+		"accuracy_m=EXCLUDED.accuracy_m, speed_mps=EXCLUDED.speed_mps, received_at=EXCLUDED.received_at " +
+		"WHERE labwork.courier_position.recorded_at < EXCLUDED.recorded_at"
+	_, err = tx.Exec(ctx, upsertPosition, courierId, last.RecordedAt, last.Lat, last.Lon, last.AccuracyM, last.SpeedMps, receivedAt)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+
+	insertPoint := "INSERT INTO labwork.delivery_track (delivery_id, recorded_at, lat, lon) VALUES ($1, $2, $3, $4) ON CONFLICT (delivery_id, recorded_at) DO NOTHING"
+	for _, point := range track {
+		_, err = tx.Exec(ctx, insertPoint, point.DeliveryId, point.RecordedAt, point.Lat, point.Lon)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	return nil
+}
+
+// GetCourierPositions returns the last known position of every courier who has sent one
+func (db *PsqlConnection) GetCourierPositions(ctx context.Context) ([]*model.CourierPosition, error) {
+	query := "SELECT c.id, c.userid, COALESCE(c.name, ''), COALESCE(c.surname, ''), COALESCE(c.status, ''), " +
+		"p.lat, p.lon, p.accuracy_m, p.speed_mps, p.recorded_at, p.received_at " +
+		"FROM labwork.courier_position p JOIN labwork.courier c ON c.id = p.courier_id ORDER BY p.recorded_at DESC"
+	rows, err := db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.CourierPosition
+
+	for rows.Next() {
+		position := &model.CourierPosition{}
+		err := rows.Scan(&position.CourierId, &position.UserId, &position.Name, &position.Surname, &position.Status,
+			&position.Lat, &position.Lon, &position.AccuracyM, &position.SpeedMps, &position.RecordedAt, &position.ReceivedAt)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, position)
+	}
+	return result, rows.Err()
+}
+
+func (db *PsqlConnection) GetDeliveryTrack(ctx context.Context, deliveryId uuid.UUID) ([]*model.TrackPoint, error) {
+	query := "SELECT delivery_id, lat, lon, recorded_at FROM labwork.delivery_track WHERE delivery_id=$1 ORDER BY recorded_at"
+	rows, err := db.pool.Query(ctx, query, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.TrackPoint
+
+	for rows.Next() {
+		point := &model.TrackPoint{}
+		err := rows.Scan(&point.DeliveryId, &point.Lat, &point.Lon, &point.RecordedAt)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, point)
+	}
+	return result, rows.Err()
+}
+
+// PruneLocationFixes deletes raw fixes recorded before the given moment, track points are kept
+func (db *PsqlConnection) PruneLocationFixes(ctx context.Context, before time.Time) (int64, error) {
+	result, err := db.pool.Exec(ctx, "DELETE FROM labwork.courier_location WHERE recorded_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("Exec(): %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// tariffColumns lists tariff columns in the order scanTariff expects
+const tariffColumns = "id, zone, version, valid_from, base_fee, per_km, per_kg, min_price, express_pct, fragile_fee, night_pct, " +
+	"night_start_hour, night_end_hour, time_zone, created_at"
+
+func scanTariff(row pgx.Row, tariff *model.Tariff) error {
+	return row.Scan(&tariff.Id, &tariff.Zone, &tariff.Version, &tariff.ValidFrom, &tariff.BaseFee, &tariff.PerKm, &tariff.PerKg, &tariff.MinPrice,
+		&tariff.ExpressPct, &tariff.FragileFee, &tariff.NightPct, &tariff.NightStartHour, &tariff.NightEndHour, &tariff.TimeZone, &tariff.CreatedAt)
+}
+
+// GetActiveTariff returns the latest started tariff version of the zone, falling back to the default zone
+func (db *PsqlConnection) GetActiveTariff(ctx context.Context, zone string, at time.Time) (*model.Tariff, error) {
+	tariff := &model.Tariff{}
+	query := "SELECT " + tariffColumns + " FROM labwork.tariff WHERE zone IN ($1, $2) AND valid_from <= $3 " +
+		"ORDER BY zone = $1 DESC, valid_from DESC, version DESC LIMIT 1"
+	err := scanTariff(db.pool.QueryRow(ctx, query, zone, model.DefaultTariffZone, at), tariff)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return tariff, nil
+}
+
+// InsertTariff stores the tariff as the next version of its zone and sets tariff.Version,
+// it returns model.ErrConflict when another version was added at the same time
+func (db *PsqlConnection) InsertTariff(ctx context.Context, tariff *model.Tariff) error {
+	query := "INSERT INTO labwork.tariff (" + tariffColumns + ") " +
+		"SELECT $1, $2, COALESCE(MAX(version), 0) + 1, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14 FROM labwork.tariff WHERE zone = $2 " +
+		"ON CONFLICT (zone, version) DO NOTHING RETURNING version"
+	err := db.pool.QueryRow(ctx, query, tariff.Id, tariff.Zone, tariff.ValidFrom, tariff.BaseFee, tariff.PerKm, tariff.PerKg, tariff.MinPrice,
+		tariff.ExpressPct, tariff.FragileFee, tariff.NightPct, tariff.NightStartHour, tariff.NightEndHour, tariff.TimeZone, tariff.CreatedAt).Scan(&tariff.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("QueryRow(): %w: tariff has changed meanwhile", model.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("QueryRow(): %w", err)
+	}
+	return nil
+}
+
+// GetTariffs returns tariff versions of the zone, or of all zones when zone is empty, newest first
+func (db *PsqlConnection) GetTariffs(ctx context.Context, zone string) ([]*model.Tariff, error) {
+	query := "SELECT " + tariffColumns + " FROM labwork.tariff WHERE $1 = '' OR zone = $1 ORDER BY zone, version DESC"
+	rows, err := db.pool.Query(ctx, query, zone)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.Tariff
+
+	for rows.Next() {
+		tariff := &model.Tariff{}
+		err := scanTariff(rows, tariff)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, tariff)
+	}
+	return result, rows.Err()
+}
+
+func (db *PsqlConnection) GetPromo(ctx context.Context, code string) (*model.Promo, error) {
+	promo := &model.Promo{}
+	query := "SELECT code, percent_off, amount_off, valid_from, valid_to, max_uses, used FROM labwork.promo_code WHERE code=$1"
+	err := db.pool.QueryRow(ctx, query, code).
+		Scan(&promo.Code, &promo.PercentOff, &promo.AmountOff, &promo.ValidFrom, &promo.ValidTo, &promo.MaxUses, &promo.Used)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return promo, nil
+}
+
+// InsertPromo returns model.ErrConflict when the code already exists
+func (db *PsqlConnection) InsertPromo(ctx context.Context, promo *model.Promo) error {
+	query := "INSERT INTO labwork.promo_code (code, percent_off, amount_off, valid_from, valid_to, max_uses, used) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, 0) ON CONFLICT (code) DO NOTHING"
+	tag, err := db.pool.Exec(ctx, query, promo.Code, promo.PercentOff, promo.AmountOff, promo.ValidFrom, promo.ValidTo, promo.MaxUses)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: promo code %s already exists", model.ErrConflict, promo.Code)
+	}
+	return nil
+}
+
+// insertDeliveryPrice freezes the quote of a new delivery within tx and uses up its promo code,
+// it returns model.ErrConflict when the promo code was used up meanwhile
+func insertDeliveryPrice(ctx context.Context, tx pgx.Tx, deliveryId uuid.UUID, quote *model.Quote) error {
+	if quote.PromoCode != "" {
+		tag, err := tx.Exec(ctx, "UPDATE labwork.promo_code SET used = used + 1 WHERE code=$1 AND (max_uses IS NULL OR used < max_uses)", quote.PromoCode)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("Exec(): %w: promo code %s is used up", model.ErrConflict, quote.PromoCode)
+		}
+	}
+	query := "INSERT INTO labwork.delivery_price (delivery_id, tariff_id, distance_km, weight_kg, base_fee, distance_fee, weight_fee, " +
+		"express_surcharge, fragile_surcharge, night_surcharge, discount, promo_code, total, quoted_at) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''), $13, $14)"
+	_, err := tx.Exec(ctx, query, deliveryId, quote.TariffId, quote.DistanceKm, quote.WeightKg, quote.BaseFee, quote.DistanceFee, quote.WeightFee,
+		quote.ExpressSurcharge, quote.FragileSurcharge, quote.NightSurcharge, quote.Discount, quote.PromoCode, quote.Total, quote.QuotedAt)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	quote.DeliveryId = deliveryId
+	return nil
+}
+
+// GetDeliveryPrice returns the price frozen when the delivery was created
+func (db *PsqlConnection) GetDeliveryPrice(ctx context.Context, deliveryId uuid.UUID) (*model.Quote, error) {
+	quote := &model.Quote{}
+	query := "SELECT p.delivery_id, p.tariff_id, t.zone, t.version, p.distance_km, p.weight_kg, p.base_fee, p.distance_fee, p.weight_fee, " +
+		"p.express_surcharge, p.fragile_surcharge, p.night_surcharge, p.discount, COALESCE(p.promo_code, ''), p.total, p.quoted_at " +
+		"FROM labwork.delivery_price p JOIN labwork.tariff t ON t.id = p.tariff_id WHERE p.delivery_id=$1"
+	err := db.pool.QueryRow(ctx, query, deliveryId).Scan(&quote.DeliveryId, &quote.TariffId, &quote.Zone, &quote.TariffVersion, &quote.DistanceKm,
+		&quote.WeightKg, &quote.BaseFee, &quote.DistanceFee, &quote.WeightFee, &quote.ExpressSurcharge, &quote.FragileSurcharge, &quote.NightSurcharge,
+		&quote.Discount, &quote.PromoCode, &quote.Total, &quote.QuotedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return quote, nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// checkDeliveryPin compares the PIN within tx and counts wrong attempts,
+// used, locked and missing PINs return model.ErrConflict
+func checkDeliveryPin(ctx context.Context, tx pgx.Tx, deliveryId uuid.UUID, pin string, maxAttempts int) (bool, error) {
+	var match bool
+	query := "UPDATE labwork.delivery_pin SET attempts = attempts + CASE WHEN pin=$2 THEN 0 ELSE 1 END " +
+		"WHERE delivery_id=$1 AND used_at IS NULL AND attempts < $3 RETURNING pin=$2"
+	err := tx.QueryRow(ctx, query, deliveryId, pin, maxAttempts).Scan(&match)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, fmt.Errorf("QueryRow(): %w: pin is used or locked", model.ErrConflict)
+	}
+	if err != nil {
+		return false, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return match, nil
+}
+
+// HasDeliveryPin reports whether a PIN was issued for the delivery, return legs and deliveries
+// created before PINs were introduced have none
+func (db *PsqlConnection) HasDeliveryPin(ctx context.Context, deliveryId uuid.UUID) (bool, error) {
+	var exists bool
+	err := db.pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM labwork.delivery_pin WHERE delivery_id=$1)", deliveryId).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return exists, nil
+}
+
+// ReissueDeliveryPin replaces the PIN of the delivery and clears its wrong attempts, a delivery without
+// a PIN gets one
+func (db *PsqlConnection) ReissueDeliveryPin(ctx context.Context, deliveryId uuid.UUID, pin string) error {
+	_, err := db.pool.Exec(ctx, "INSERT INTO labwork.delivery_pin (delivery_id, pin) VALUES ($1, $2) "+
+		"ON CONFLICT (delivery_id) DO UPDATE SET pin=EXCLUDED.pin, attempts=0, used_at=NULL", deliveryId, pin)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	return nil
+}
+
+// CompleteDelivery marks a picked up delivery of the courier delivered, stores its proofs, uses up the PIN and
+// books the owed cash to the courier next to the collected amount. A given PIN is checked in the same transaction,
+// a wrong one is counted and returns model.ErrValidation; a delivery that is not picked up or is carried by
+// someone else returns model.ErrConflict
+func (db *PsqlConnection) CompleteDelivery(ctx context.Context, courierId, deliveryId uuid.UUID, deliveredAt time.Time, pin string, maxPinAttempts int,
+	proofs []*model.DeliveryProof, cash *model.CashEntry, collected *float64) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if pin != "" {
+		match, err := checkDeliveryPin(ctx, tx, deliveryId, pin, maxPinAttempts)
+		if err != nil {
+			return err
+		}
+		if !match {
+			// the wrong attempt is kept although the delivery stays open
+			err = tx.Commit(ctx)
+			if err != nil {
+				return fmt.Errorf("Commit(): %w", err)
+			}
+			return fmt.Errorf("checkDeliveryPin: %w: pin does not match", model.ErrValidation)
+		}
+	}
+	tag, err := tx.Exec(ctx, "UPDATE labwork.delivery SET delivery_status=$1, delivered_at=$2 WHERE id=$3 AND courier_id=$4 AND delivery_status=$5",
+		model.DeliveryStatusDelivered, deliveredAt, deliveryId, courierId, model.DeliveryStatusPickedUp)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: delivery is not picked up", model.ErrConflict)
+	}
+	insert := "INSERT INTO labwork.delivery_proof (id, delivery_id, courier_id, event, kind, blob_key, content_type, size_bytes, sha256, captured_at, lat, lon, created_at) " +
+		"VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, NULLIF($9, ''), $10, $11, $12, $13)"
+	for _, proof := range proofs {
+		_, err = tx.Exec(ctx, insert, proof.Id, proof.DeliveryId, proof.CourierId, proof.Event, proof.Kind, proof.BlobKey, proof.ContentType,
+			proof.SizeBytes, proof.Sha256, proof.CapturedAt, proof.Lat, proof.Lon, proof.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+	}
+	_, err = tx.Exec(ctx, "UPDATE labwork.delivery_pin SET used_at=$1 WHERE delivery_id=$2 AND used_at IS NULL", deliveredAt, deliveryId)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	// the last leg of a multi-leg delivery ends at the drop-off address
+	_, err = tx.Exec(ctx, "UPDATE labwork.delivery_leg SET status=$1, delivered_at=$2, version = version + 1 "+
+		"WHERE delivery_id=$3 AND to_hub_id IS NULL AND status <> $4", model.DeliveryStatusDelivered, deliveredAt, deliveryId, model.DeliveryStatusCancelled)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if cash != nil {
+		_, err = tx.Exec(ctx, "UPDATE labwork.delivery SET cod_collected=$1 WHERE id=$2", collected, deliveryId)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+		err = insertCashEntry(ctx, tx, cash)
+		if err != nil {
+			return err
+		}
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	return nil
+}
+
+// proofColumns lists delivery_proof columns in the order scanProof expects
+const proofColumns = "id, delivery_id, courier_id, event, kind, COALESCE(blob_key, ''), COALESCE(content_type, ''), COALESCE(size_bytes, 0), " +
+	"COALESCE(sha256, ''), captured_at, lat, lon, created_at"
+
+func scanProof(row pgx.Row, proof *model.DeliveryProof) error {
+	return row.Scan(&proof.Id, &proof.DeliveryId, &proof.CourierId, &proof.Event, &proof.Kind, &proof.BlobKey, &proof.ContentType, &proof.SizeBytes,
+		&proof.Sha256, &proof.CapturedAt, &proof.Lat, &proof.Lon, &proof.CreatedAt)
+}
+
+// GetDeliveryProofs returns proofs of the delivery in the order they were recorded
+func (db *PsqlConnection) GetDeliveryProofs(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryProof, error) {
+	rows, err := db.pool.Query(ctx, "SELECT "+proofColumns+" FROM labwork.delivery_proof WHERE delivery_id=$1 ORDER BY created_at, kind", deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.DeliveryProof
+
+	for rows.Next() {
+		proof := &model.DeliveryProof{}
+		err := scanProof(rows, proof)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, proof)
+	}
+	return result, rows.Err()
+}
+
+// GetDeliveryProof returns model.ErrNotFound for unknown proofs
+func (db *PsqlConnection) GetDeliveryProof(ctx context.Context, proofId uuid.UUID) (*model.DeliveryProof, error) {
+	proof := &model.DeliveryProof{}
+	err := scanProof(db.pool.QueryRow(ctx, "SELECT "+proofColumns+" FROM labwork.delivery_proof WHERE id=$1", proofId), proof)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return proof, nil
+}
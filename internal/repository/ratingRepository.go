@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+const ratingColumns = "id, delivery_id, courier_id, score, tags, comment, source, rated_by, status, moderated_by, moderated_at, moderation_note, created_at"
+
+func scanRating(row pgx.Row) (*model.Rating, error) {
+	rating := &model.Rating{}
+	err := row.Scan(&rating.Id, &rating.DeliveryId, &rating.CourierId, &rating.Score, &rating.Tags, &rating.Comment, &rating.Source, &rating.RatedBy,
+		&rating.Status, &rating.ModeratedBy, &rating.ModeratedAt, &rating.ModerationNote, &rating.CreatedAt)
+	return rating, err
+}
+
+// InsertRating returns model.ErrConflict when the delivery has already been rated
+func (db *PsqlConnection) InsertRating(ctx context.Context, rating *model.Rating) error {
+	query := "INSERT INTO labwork.delivery_rating (id, delivery_id, courier_id, score, tags, comment, source, rated_by, status, created_at) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) ON CONFLICT (delivery_id) DO NOTHING"
+	tag, err := db.pool.Exec(ctx, query, rating.Id, rating.DeliveryId, rating.CourierId, rating.Score, rating.Tags, rating.Comment, rating.Source,
+		rating.RatedBy, rating.Status, rating.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: delivery has already been rated", model.ErrConflict)
+	}
+	return nil
+}
+
+// ModerateRating sets the status of a rating and records who moderated it
+func (db *PsqlConnection) ModerateRating(ctx context.Context, moderation *model.RatingModeration, managerId uuid.UUID, at time.Time) (*model.Rating, error) {
+	query := "UPDATE labwork.delivery_rating SET status=$2, moderated_by=$3, moderated_at=$4, moderation_note=$5 WHERE id=$1 RETURNING " + ratingColumns
+	rating, err := scanRating(db.pool.QueryRow(ctx, query, moderation.Id, moderation.Status, managerId, at, moderation.Note))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return rating, nil
+}
+
+// GetCourierRatings aggregates the published ratings of a courier and lists the latest ratings of any status
+func (db *PsqlConnection) GetCourierRatings(ctx context.Context, courierId uuid.UUID, recent int) (*model.CourierRatings, error) {
+	summary := &model.CourierRatings{CourierId: courierId, Tags: map[string]int{}}
+	rows, err := db.pool.Query(ctx, "SELECT status, score, tags FROM labwork.delivery_rating WHERE courier_id=$1", courierId)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+	var total int
+	for rows.Next() {
+		var status string
+		var score int
+		var tags []string
+		err := rows.Scan(&status, &score, &tags)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		if status != model.RatingPublished {
+			summary.Hidden++
+			continue
+		}
+		summary.Ratings++
+		summary.Distribution[score-1]++
+		total += score
+		for _, tag := range tags {
+			summary.Tags[tag]++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Next(): %w", err)
+	}
+	if summary.Ratings > 0 {
+		average := float64(total) / float64(summary.Ratings)
+		summary.Average = &average
+	}
+
+	query := "SELECT " + ratingColumns + " FROM labwork.delivery_rating WHERE courier_id=$1 ORDER BY created_at DESC LIMIT $2"
+	summary.Recent, err = db.queryRatings(ctx, query, courierId, recent)
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// GetLowRatings returns published ratings with a score of at most maxScore, newest first
+func (db *PsqlConnection) GetLowRatings(ctx context.Context, maxScore int, since *time.Time) ([]*model.Rating, error) {
+	query := "SELECT " + ratingColumns + " FROM labwork.delivery_rating " +
+		"WHERE status = 'published' AND score <= $1 AND ($2::timestamptz IS NULL OR created_at >= $2) ORDER BY created_at DESC"
+	return db.queryRatings(ctx, query, maxScore, since)
+}
+
+func (db *PsqlConnection) queryRatings(ctx context.Context, query string, args ...interface{}) ([]*model.Rating, error) {
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.Rating
+
+	for rows.Next() {
+		rating, err := scanRating(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, rating)
+	}
+	return result, rows.Err()
+}
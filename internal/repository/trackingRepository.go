@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// GetTrackingView returns model.ErrNotFound for unknown and revoked codes
+func (db *PsqlConnection) GetTrackingView(ctx context.Context, code string) (*model.TrackingView, error) {
+	view := &model.TrackingView{}
+	var deliveryId uuid.UUID
+	var createdAt time.Time
+	var pickedUpAt, deliveredAt, cancelledAt *time.Time
+	var cancelReason *string
+	query := "SELECT d.id, d.delivery_status, COALESCE(c.name, ''), d.window_start, d.window_end, d.eta, d.created_at, d.picked_up_at, d.delivered_at, x.cancelled_at, x.reason " +
+		"FROM labwork.tracking_code t JOIN labwork.delivery d ON d.id = t.delivery_id " +
+		"LEFT JOIN labwork.courier c ON c.id = d.courier_id " +
+		"LEFT JOIN labwork.delivery_cancellation x ON x.delivery_id = d.id " +
+		"WHERE t.code=$1 AND t.revoked_at IS NULL"
+	err := db.pool.QueryRow(ctx, query, code).Scan(&deliveryId, &view.Status, &view.CourierFirstName, &view.WindowStart, &view.WindowEnd, &view.ETA, &createdAt, &pickedUpAt, &deliveredAt, &cancelledAt, &cancelReason)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	view.Timeline = append(view.Timeline, model.TrackingEvent{Status: model.DeliveryStatusCreated, At: createdAt})
+	if pickedUpAt != nil {
+		view.Timeline = append(view.Timeline, model.TrackingEvent{Status: model.DeliveryStatusPickedUp, At: *pickedUpAt})
+	}
+	if deliveredAt != nil {
+		view.Timeline = append(view.Timeline, model.TrackingEvent{Status: model.DeliveryStatusDelivered, At: *deliveredAt})
+	}
+	if cancelledAt != nil {
+		view.Timeline = append(view.Timeline, model.TrackingEvent{Status: model.DeliveryStatusCancelled, Reason: *cancelReason, At: *cancelledAt})
+	}
+
+	rows, err := db.pool.Query(ctx, "SELECT reason, attempted_at FROM labwork.delivery_attempt WHERE delivery_id=$1", deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		event := model.TrackingEvent{Status: model.TrackingAttemptFailed}
+		err := rows.Scan(&event.Reason, &event.At)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		view.Timeline = append(view.Timeline, event)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Next(): %w", err)
+	}
+	sort.SliceStable(view.Timeline, func(i, j int) bool { return view.Timeline[i].At.Before(view.Timeline[j].At) })
+
+	if view.Status == model.DeliveryStatusReturned {
+		view.Return, err = db.getTrackingReturn(ctx, deliveryId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return view, nil
+}
+
+// getTrackingReturn returns the return leg of a delivery, nil when there is none
+func (db *PsqlConnection) getTrackingReturn(ctx context.Context, deliveryId uuid.UUID) (*model.TrackingReturn, error) {
+	leg := &model.TrackingReturn{}
+	err := db.pool.QueryRow(ctx, "SELECT delivery_status, window_end, eta, delivered_at FROM labwork.delivery WHERE return_of=$1", deliveryId).
+		Scan(&leg.Status, &leg.WindowEnd, &leg.ETA, &leg.DeliveredAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return leg, nil
+}
+
+// ReissueTrackingCode revokes active codes of the delivery and stores the new one
+func (db *PsqlConnection) ReissueTrackingCode(ctx context.Context, deliveryId uuid.UUID, code string) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "UPDATE labwork.tracking_code SET revoked_at=now() WHERE delivery_id=$1 AND revoked_at IS NULL", deliveryId)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	_, err = tx.Exec(ctx, "INSERT INTO labwork.tracking_code (code, delivery_id) VALUES ($1, $2)", code, deliveryId)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	return nil
+}
+
+// RevokeTrackingCodes revokes every active code of the delivery
+func (db *PsqlConnection) RevokeTrackingCodes(ctx context.Context, deliveryId uuid.UUID) error {
+	_, err := db.pool.Exec(ctx, "UPDATE labwork.tracking_code SET revoked_at=now() WHERE delivery_id=$1 AND revoked_at IS NULL", deliveryId)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	return nil
+}
+
+// GetDeliveryIDByTrackingCode returns model.ErrNotFound for unknown and revoked codes
+func (db *PsqlConnection) GetDeliveryIDByTrackingCode(ctx context.Context, code string) (uuid.UUID, error) {
+	var deliveryId uuid.UUID
+	err := db.pool.QueryRow(ctx, "SELECT delivery_id FROM labwork.tracking_code WHERE code=$1 AND revoked_at IS NULL", code).Scan(&deliveryId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return deliveryId, nil
+}
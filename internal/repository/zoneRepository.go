@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+const zoneColumns = "z.id, z.code, z.name, z.boundary, z.time_zone, z.hours, z.active, z.created_at, z.updated_at"
+
+// homeZoneCondition keeps deliveries of the home zones of the courier with user id $3, couriers without home zones see every zone
+const homeZoneCondition = "(NOT EXISTS (SELECT 1 FROM labwork.courier_zone cz JOIN labwork.courier c ON c.id = cz.courier_id WHERE c.userid = $3) " +
+	"OR zone_id IN (SELECT cz.zone_id FROM labwork.courier_zone cz JOIN labwork.courier c ON c.id = cz.courier_id WHERE c.userid = $3))"
+
+// inHomeZones keeps rows whose zone, given as an SQL expression, is a home zone of the courier with the id expression courier,
+// zones are not restricted for couriers without home zones and deliveries outside every zone
+func inHomeZones(zone, courier string) string {
+	return "(" + zone + " IS NULL OR NOT EXISTS (SELECT 1 FROM labwork.courier_zone cz WHERE cz.courier_id = " + courier + ") " +
+		"OR " + zone + " IN (SELECT cz.zone_id FROM labwork.courier_zone cz WHERE cz.courier_id = " + courier + "))"
+}
+
+// scanZone scans a row selected with zoneColumns, boundary and hours are stored as JSON
+func scanZone(row pgx.Row) (*model.Zone, error) {
+	zone := &model.Zone{}
+	var boundary, hours []byte
+	err := row.Scan(&zone.Id, &zone.Code, &zone.Name, &boundary, &zone.TimeZone, &hours, &zone.Active, &zone.CreatedAt, &zone.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(boundary, &zone.Boundary)
+	if err != nil {
+		return nil, fmt.Errorf("boundary: %w", err)
+	}
+	err = json.Unmarshal(hours, &zone.Hours)
+	if err != nil {
+		return nil, fmt.Errorf("hours: %w", err)
+	}
+	return zone, nil
+}
+
+// zoneJSON encodes the boundary and hours of a zone for storage
+func zoneJSON(zone *model.Zone) ([]byte, []byte, error) {
+	boundary, err := json.Marshal(zone.Boundary)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Marshal(): %w", err)
+	}
+	if zone.Hours == nil {
+		zone.Hours = []model.ZoneHours{}
+	}
+	hours, err := json.Marshal(zone.Hours)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Marshal(): %w", err)
+	}
+	return boundary, hours, nil
+}
+
+// InsertZone returns model.ErrConflict when the code is taken
+func (db *PsqlConnection) InsertZone(ctx context.Context, zone *model.Zone) error {
+	boundary, hours, err := zoneJSON(zone)
+	if err != nil {
+		return err
+	}
+	query := "INSERT INTO labwork.zone (id, code, name, boundary, time_zone, hours, active, created_at, updated_at) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT (code) DO NOTHING"
+	tag, err := db.pool.Exec(ctx, query, zone.Id, zone.Code, zone.Name, boundary, zone.TimeZone, hours, zone.Active, zone.CreatedAt, zone.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Exec(): %w: zone %s already exists", model.ErrConflict, zone.Code)
+	}
+	return nil
+}
+
+// UpdateZone replaces the definition of an existing zone and sets zone.CreatedAt,
+// it returns model.ErrConflict when the new code is taken
+func (db *PsqlConnection) UpdateZone(ctx context.Context, zone *model.Zone) error {
+	boundary, hours, err := zoneJSON(zone)
+	if err != nil {
+		return err
+	}
+	var taken bool
+	err = db.pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM labwork.zone WHERE code=$1 AND id<>$2)", zone.Code, zone.Id).Scan(&taken)
+	if err != nil {
+		return fmt.Errorf("QueryRow(): %w", err)
+	}
+	if taken {
+		return fmt.Errorf("QueryRow(): %w: zone %s already exists", model.ErrConflict, zone.Code)
+	}
+	query := "UPDATE labwork.zone SET code=$2, name=$3, boundary=$4, time_zone=$5, hours=$6, active=$7, updated_at=$8 WHERE id=$1 RETURNING created_at"
+	err = db.pool.QueryRow(ctx, query, zone.Id, zone.Code, zone.Name, boundary, zone.TimeZone, hours, zone.Active, zone.UpdatedAt).Scan(&zone.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("QueryRow(): %w", err)
+	}
+	return nil
+}
+
+// GetZones returns zones ordered by code
+func (db *PsqlConnection) GetZones(ctx context.Context, activeOnly bool) ([]*model.Zone, error) {
+	query := "SELECT " + zoneColumns + " FROM labwork.zone z WHERE NOT $1 OR z.active ORDER BY z.code"
+	return db.queryZones(ctx, query, activeOnly)
+}
+
+// LocateZonePostGIS returns the smallest active zone containing the point, it needs the PostGIS extension
+func (db *PsqlConnection) LocateZonePostGIS(ctx context.Context, point model.GeoPoint) (*model.Zone, error) {
+	query := "SELECT " + zoneColumns + " FROM labwork.zone z " +
+		"WHERE z.active AND ST_Contains(ST_SetSRID(ST_GeomFromGeoJSON(z.boundary::text), 4326), ST_SetSRID(ST_MakePoint($1, $2), 4326)) " +
+		"ORDER BY ST_Area(ST_GeomFromGeoJSON(z.boundary::text)) LIMIT 1"
+	zone, err := scanZone(db.pool.QueryRow(ctx, query, point.Lon, point.Lat))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("QueryRow(): %w", model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow(): %w", err)
+	}
+	return zone, nil
+}
+
+// SetCourierZones replaces the home zones of a courier in one transaction,
+// it returns model.ErrNotFound when one of the zones does not exist
+func (db *PsqlConnection) SetCourierZones(ctx context.Context, courierId uuid.UUID, zoneIds []uuid.UUID) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Begin(): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "DELETE FROM labwork.courier_zone WHERE courier_id=$1", courierId)
+	if err != nil {
+		return fmt.Errorf("Exec(): %w", err)
+	}
+	for _, zoneId := range zoneIds {
+		tag, err := tx.Exec(ctx, "INSERT INTO labwork.courier_zone (courier_id, zone_id) SELECT $1, id FROM labwork.zone WHERE id=$2 ON CONFLICT DO NOTHING",
+			courierId, zoneId)
+		if err != nil {
+			return fmt.Errorf("Exec(): %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			var exists bool
+			err = tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM labwork.zone WHERE id=$1)", zoneId).Scan(&exists)
+			if err != nil {
+				return fmt.Errorf("QueryRow(): %w", err)
+			}
+			if !exists {
+				return fmt.Errorf("Exec(): %w: zone %s", model.ErrNotFound, zoneId)
+			}
+		}
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("Commit(): %w", err)
+	}
+	return nil
+}
+
+// GetCourierZones returns the home zones of a courier ordered by code
+func (db *PsqlConnection) GetCourierZones(ctx context.Context, courierId uuid.UUID) ([]*model.Zone, error) {
+	query := "SELECT " + zoneColumns + " FROM labwork.zone z JOIN labwork.courier_zone cz ON cz.zone_id = z.id WHERE cz.courier_id=$1 ORDER BY z.code"
+	return db.queryZones(ctx, query, courierId)
+}
+
+func (db *PsqlConnection) queryZones(ctx context.Context, query string, args ...interface{}) ([]*model.Zone, error) {
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Query(): %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.Zone
+
+	for rows.Next() {
+		zone, err := scanZone(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Scan(): %w", err)
+		}
+		result = append(result, zone)
+	}
+	return result, rows.Err()
+}
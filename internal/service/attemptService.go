@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+const (
+	// defaultWindowLength is used when a delivery without a promised window is rescheduled
+	defaultWindowLength = 2 * time.Hour
+	// returnRecipientName is the recipient of return-to-sender legs, the drop-off is the original pickup
+	returnRecipientName = "Return to sender"
+)
+
+// attemptReasons are the accepted failure reasons, AttemptOther needs a comment
+var attemptReasons = map[string]bool{
+	model.AttemptRecipientAbsent: true,
+	model.AttemptAddressNotFound: true,
+	model.AttemptAccessDenied:    true,
+	model.AttemptRefused:         true,
+	model.AttemptUnsafeLocation:  true,
+	model.AttemptOther:           true,
+}
+
+type AttemptService struct {
+	rps         AttemptRepository
+	clock       Clock
+	maxAttempts int
+	lead        time.Duration
+}
+
+func NewAttemptService(rps AttemptRepository, clock Clock, maxAttempts int, lead time.Duration) *AttemptService {
+	return &AttemptService{rps: rps, clock: clock, maxAttempts: maxAttempts, lead: lead}
+}
+
+type AttemptRepository interface {
+	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
+	GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error)
+	RecordFailedAttempt(ctx context.Context, attempt *model.DeliveryAttempt, windowStart, windowEnd time.Time, returnLeg *model.Delivery) error
+	GetDeliveryAttempts(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryAttempt, error)
+	GetDeliveryLegs(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryLeg, error)
+}
+
+// validateFailedAttempt checks the reason and trims the comment
+func validateFailedAttempt(report *model.FailedAttempt) error {
+	if !attemptReasons[report.Reason] {
+		return fmt.Errorf("%w: unknown reason %q", model.ErrValidation, report.Reason)
+	}
+	report.Comment = strings.TrimSpace(report.Comment)
+	if report.Reason == model.AttemptOther && report.Comment == "" {
+		return fmt.Errorf("%w: comment is required for reason other", model.ErrValidation)
+	}
+	if len(report.Comment) > 500 {
+		return fmt.Errorf("%w: comment is longer than 500 characters", model.ErrValidation)
+	}
+	return validateReportedPoint(report.Lat, report.Lon)
+}
+
+// nextWindow keeps the time of day of the promised window and moves it forward by whole days
+// until it starts at least lead after now
+func nextWindow(start, end *time.Time, now time.Time, lead time.Duration) (time.Time, time.Time) {
+	earliest := now.Add(lead)
+	if start == nil || end == nil {
+		return earliest, earliest.Add(defaultWindowLength)
+	}
+	if !start.Before(earliest) {
+		return *start, *end
+	}
+	days := int(math.Ceil(earliest.Sub(*start).Hours() / 24))
+	return start.AddDate(0, 0, days), end.AddDate(0, 0, days)
+}
+
+// returnLeg builds the delivery that takes the parcel back from the drop-off to the pickup address,
+// the courier who failed the last attempt still carries it. Nobody receives a PIN for it, so return legs
+// are completed without one, and it has no tracking code because the sender follows it with the original one
+func returnLeg(delivery *model.DeliveryGet, now, windowEnd time.Time) *model.Delivery {
+	return &model.Delivery{
+		ClientId:        delivery.ClientId,
+		DeliveryStatus:  model.DeliveryStatusPickedUp,
+		DeliveryComment: fmt.Sprintf("Return of delivery %s", delivery.Id),
+		CreatedAt:       now,
+		WindowStart:     &now,
+		WindowEnd:       &windowEnd,
+		PickedUpAt:      &now,
+		Pickup:          delivery.Dropoff,
+		Dropoff:         delivery.Pickup,
+		Recipient:       model.Recipient{Name: returnRecipientName},
+		WeightKg:        delivery.WeightKg,
+		DeliveryTotals:  delivery.DeliveryTotals,
+		ReturnOf:        &delivery.Id,
+	}
+}
+
+// FailAttempt records a failed attempt of the courier's picked up delivery. The delivery is rescheduled
+// into its next window and stays with the courier who has the parcel, or returned to the sender once
+// the attempts are exhausted. Only the courier of the final leg attempts a multi-leg delivery
+func (srv *AttemptService) FailAttempt(ctx context.Context, userId uuid.UUID, report *model.FailedAttempt) (*model.AttemptOutcome, error) {
+	err := validateFailedAttempt(report)
+	if err != nil {
+		return nil, fmt.Errorf("validateFailedAttempt: %w", err)
+	}
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	delivery, err := srv.rps.GetDeliveryByID(ctx, report.DeliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryByID: %w", err)
+	}
+	if delivery.CourierId == nil || *delivery.CourierId != courier.Id {
+		return nil, fmt.Errorf("FailAttempt: %w", model.ErrNotFound)
+	}
+	if delivery.DeliveryStatus != model.DeliveryStatusPickedUp {
+		return nil, fmt.Errorf("FailAttempt: %w: delivery is %s", model.ErrConflict, delivery.DeliveryStatus)
+	}
+	if delivery.Legs > 0 {
+		legs, err := srv.rps.GetDeliveryLegs(ctx, delivery.Id)
+		if err != nil {
+			return nil, fmt.Errorf("GetDeliveryLegs: %w", err)
+		}
+		err = checkFinalLeg(legs, courier.Id)
+		if err != nil {
+			return nil, fmt.Errorf("checkFinalLeg: %w", err)
+		}
+	}
+
+	now := srv.clock.Now().UTC()
+	attempt := &model.DeliveryAttempt{Id: uuid.New(), DeliveryId: delivery.Id, CourierId: courier.Id, Number: delivery.Attempts + 1,
+		Reason: report.Reason, Comment: report.Comment, AttemptedAt: now, Lat: report.Lat, Lon: report.Lon}
+	start, end := nextWindow(delivery.WindowStart, delivery.WindowEnd, now, srv.lead)
+	outcome := &model.AttemptOutcome{Attempt: attempt, DeliveryStatus: model.DeliveryStatusPickedUp, WindowStart: &start, WindowEnd: &end}
+
+	var leg *model.Delivery
+	if attempt.Number >= srv.maxAttempts {
+		leg = returnLeg(delivery, now, end)
+		outcome.DeliveryStatus = model.DeliveryStatusReturned
+		outcome.WindowStart, outcome.WindowEnd = delivery.WindowStart, delivery.WindowEnd
+	}
+	err = srv.rps.RecordFailedAttempt(ctx, attempt, start, end, leg)
+	if err != nil {
+		return nil, fmt.Errorf("RecordFailedAttempt: %w", err)
+	}
+	if leg != nil {
+		outcome.ReturnDeliveryId = &leg.Id
+	}
+	return outcome, nil
+}
+
+func (srv *AttemptService) GetDeliveryAttempts(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryAttempt, error) {
+	attempts, err := srv.rps.GetDeliveryAttempts(ctx, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryAttempts: %w", err)
+	}
+	return attempts, nil
+}
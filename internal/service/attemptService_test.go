@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAttemptRepository struct {
+	courier   *model.Courier
+	delivery  *model.DeliveryGet
+	attempt   *model.DeliveryAttempt
+	start     time.Time
+	end       time.Time
+	returnLeg *model.Delivery
+	legs      []*model.DeliveryLeg
+}
+
+func (r *fakeAttemptRepository) GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error) {
+	return r.courier, nil
+}
+
+func (r *fakeAttemptRepository) GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error) {
+	return r.delivery, nil
+}
+
+func (r *fakeAttemptRepository) RecordFailedAttempt(ctx context.Context, attempt *model.DeliveryAttempt, windowStart, windowEnd time.Time, returnLeg *model.Delivery) error {
+	r.attempt, r.start, r.end, r.returnLeg = attempt, windowStart, windowEnd, returnLeg
+	if returnLeg != nil {
+		returnLeg.Id = uuid.New()
+	}
+	return nil
+}
+
+func (r *fakeAttemptRepository) GetDeliveryAttempts(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryAttempt, error) {
+	return nil, nil
+}
+
+func (r *fakeAttemptRepository) GetDeliveryLegs(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryLeg, error) {
+	return r.legs, nil
+}
+
+func newAttemptFixture(attempts int) (*AttemptService, *fakeAttemptRepository) {
+	courier := &model.Courier{Id: uuid.New()}
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	repo := &fakeAttemptRepository{courier: courier, delivery: &model.DeliveryGet{
+		Id: uuid.New(), CourierId: &courier.Id, DeliveryStatus: model.DeliveryStatusPickedUp, Attempts: attempts,
+		WindowStart: &start, WindowEnd: &end,
+		Pickup:  model.Location{AddressLine1: "test_pickup", City: "test_city", Lat: 53.9, Lon: 27.56},
+		Dropoff: model.Location{AddressLine1: "test_dropoff", City: "test_city", Lat: 53.91, Lon: 27.6},
+	}}
+	clock := &fixedClock{time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)}
+	return NewAttemptService(repo, clock, 3, 2*time.Hour), repo
+}
+
+// TestFailAttemptReschedules checks that an early failed attempt moves the delivery into the next day's window
+// and leaves it with the courier
+func TestFailAttemptReschedules(t *testing.T) {
+	srv, repo := newAttemptFixture(0)
+	outcome, err := srv.FailAttempt(context.Background(), uuid.New(), &model.FailedAttempt{DeliveryId: repo.delivery.Id, Reason: model.AttemptRecipientAbsent})
+	require.NoError(t, err)
+	require.Equal(t, 1, repo.attempt.Number)
+	require.Equal(t, model.DeliveryStatusPickedUp, outcome.DeliveryStatus)
+	require.Equal(t, time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC), repo.start)
+	require.Equal(t, time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC), repo.end)
+	require.Nil(t, repo.returnLeg)
+	require.Nil(t, outcome.ReturnDeliveryId)
+}
+
+// TestFailAttemptReturnsToSender checks that the last allowed attempt creates a reversed return leg
+func TestFailAttemptReturnsToSender(t *testing.T) {
+	srv, repo := newAttemptFixture(2)
+	outcome, err := srv.FailAttempt(context.Background(), uuid.New(), &model.FailedAttempt{DeliveryId: repo.delivery.Id, Reason: model.AttemptRefused})
+	require.NoError(t, err)
+	require.Equal(t, 3, repo.attempt.Number)
+	require.Equal(t, model.DeliveryStatusReturned, outcome.DeliveryStatus)
+	require.NotNil(t, repo.returnLeg)
+	require.Equal(t, &repo.returnLeg.Id, outcome.ReturnDeliveryId)
+	require.Equal(t, repo.delivery.Pickup, repo.returnLeg.Dropoff)
+	require.Equal(t, repo.delivery.Dropoff, repo.returnLeg.Pickup)
+	require.Equal(t, &repo.delivery.Id, repo.returnLeg.ReturnOf)
+	require.Equal(t, model.DeliveryStatusPickedUp, repo.returnLeg.DeliveryStatus)
+	require.Empty(t, repo.returnLeg.TrackingCode)
+	require.Empty(t, repo.returnLeg.Pin)
+}
+
+// TestFailAttemptValidation checks reasons, ownership and status before anything is recorded
+func TestFailAttemptValidation(t *testing.T) {
+	srv, repo := newAttemptFixture(0)
+	ctx := context.Background()
+
+	_, err := srv.FailAttempt(ctx, uuid.New(), &model.FailedAttempt{DeliveryId: repo.delivery.Id, Reason: "test_reason"})
+	require.ErrorIs(t, err, model.ErrValidation)
+	_, err = srv.FailAttempt(ctx, uuid.New(), &model.FailedAttempt{DeliveryId: repo.delivery.Id, Reason: model.AttemptOther, Comment: " "})
+	require.ErrorIs(t, err, model.ErrValidation)
+
+	repo.delivery.DeliveryStatus = model.DeliveryStatusCreated
+	_, err = srv.FailAttempt(ctx, uuid.New(), &model.FailedAttempt{DeliveryId: repo.delivery.Id, Reason: model.AttemptRecipientAbsent})
+	require.ErrorIs(t, err, model.ErrConflict)
+
+	other := uuid.New()
+	repo.delivery.CourierId = &other
+	_, err = srv.FailAttempt(ctx, uuid.New(), &model.FailedAttempt{DeliveryId: repo.delivery.Id, Reason: model.AttemptRecipientAbsent})
+	require.ErrorIs(t, err, model.ErrNotFound)
+	require.Nil(t, repo.attempt)
+}
+
+// TestFailAttemptOfMultiLegDelivery checks that a multi-leg delivery is only attempted on its final leg
+func TestFailAttemptOfMultiLegDelivery(t *testing.T) {
+	srv, repo := newAttemptFixture(0)
+	ctx := context.Background()
+	hubId := uuid.New()
+	final := &model.DeliveryLeg{Id: uuid.New(), Position: 1, FromHubId: &hubId, Status: model.DeliveryStatusCreated}
+	repo.delivery.Legs = 2
+	repo.legs = []*model.DeliveryLeg{
+		{Id: uuid.New(), ToHubId: &hubId, CourierId: &repo.courier.Id, Status: model.DeliveryStatusPickedUp}, final}
+	report := &model.FailedAttempt{DeliveryId: repo.delivery.Id, Reason: model.AttemptRecipientAbsent}
+
+	_, err := srv.FailAttempt(ctx, uuid.New(), report)
+	require.ErrorIs(t, err, model.ErrConflict)
+	require.Nil(t, repo.attempt)
+
+	repo.legs[0].Status = model.DeliveryStatusDelivered
+	final.Status, final.CourierId = model.DeliveryStatusPickedUp, &repo.courier.Id
+	_, err = srv.FailAttempt(ctx, uuid.New(), report)
+	require.NoError(t, err)
+}
+
+// TestNextWindow checks that future windows are kept and past ones moved by whole days
+func TestNextWindow(t *testing.T) {
+	now := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	start, end := now.Add(4*time.Hour), now.Add(6*time.Hour)
+	gotStart, gotEnd := nextWindow(&start, &end, now, time.Hour)
+	require.Equal(t, start, gotStart)
+	require.Equal(t, end, gotEnd)
+
+	start, end = now.AddDate(0, 0, -3), now.AddDate(0, 0, -3).Add(time.Hour)
+	gotStart, gotEnd = nextWindow(&start, &end, now, time.Hour)
+	require.Equal(t, now.AddDate(0, 0, 1), gotStart)
+	require.Equal(t, now.AddDate(0, 0, 1).Add(time.Hour), gotEnd)
+
+	gotStart, gotEnd = nextWindow(nil, nil, now, time.Hour)
+	require.Equal(t, now.Add(time.Hour), gotStart)
+	require.Equal(t, now.Add(3*time.Hour), gotEnd)
+}
@@ -20,6 +20,7 @@ import (
 const (
 	accessTokenTTL  = 24 * time.Hour
 	refreshTokenTTL = 72 * time.Hour
+	eventTicketTTL  = time.Minute
 )
 
 // tokenClaims struct contains information about the claims associated with the given token
@@ -168,6 +169,25 @@ func GenerateAccessAndRefreshTokens(key, role string, id uuid.UUID) (access, ref
 	return access, refresh, err
 }
 
+// GenerateEventTicket returns a ticket that only opens an event stream, it expires eventTicketTTL after now
+func GenerateEventTicket(key, role string, id uuid.UUID, now time.Time) (*model.EventTicket, error) {
+	expiresAt := now.Add(eventTicketTTL)
+	ticket := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
+		Role: role,
+		StandardClaims: jwt.StandardClaims{
+			Audience:  model.EventTicketAudience,
+			ExpiresAt: expiresAt.Unix(),
+			IssuedAt:  now.Unix(),
+			Id:        id.String(),
+		},
+	})
+	signed, err := ticket.SignedString([]byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("SignedString: %w", err)
+	}
+	return &model.EventTicket{Ticket: signed, ExpiresAt: expiresAt}, nil
+}
+
 // CompareTokenIDs func compares token ids
 func CompareTokenIDs(accessToken, refreshToken, key string) (bool, error) {
 	accessID, err := ExtractIDFromToken(accessToken, key)
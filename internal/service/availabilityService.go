@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// shiftLookahead is how far ahead couriers see their planned shifts
+const shiftLookahead = 14 * 24 * time.Hour
+
+type AvailabilityService struct {
+	rps   AvailabilityRepository
+	clock Clock
+}
+
+func NewAvailabilityService(rps AvailabilityRepository, clock Clock) *AvailabilityService {
+	return &AvailabilityService{rps: rps, clock: clock}
+}
+
+type AvailabilityRepository interface {
+	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
+	GetCourierCapacity(ctx context.Context, courierId uuid.UUID, now time.Time) (*model.CourierCapacity, error)
+	UpdateCourierCapacity(ctx context.Context, update *model.CourierCapacityUpdate) error
+	UpdateCourierStatusFrom(ctx context.Context, courierId uuid.UUID, from string, to string) (bool, error)
+	InsertCourierShift(ctx context.Context, shift *model.CourierShift) error
+	DeleteCourierShift(ctx context.Context, shiftId uuid.UUID) error
+	GetCourierShifts(ctx context.Context, courierId uuid.UUID, from time.Time) ([]*model.CourierShift, error)
+}
+
+// courierTransitions lists the statuses a courier may switch to by themselves,
+// suspended couriers are only changed by a manager
+var courierTransitions = map[string][]string{
+	model.CourierStatusOffShift: {model.CourierStatusActive},
+	model.CourierStatusActive:   {model.CourierStatusOffShift},
+}
+
+// validateCourierTransition returns model.ErrConflict when the courier may not move from one status to another
+func validateCourierTransition(from string, to string) error {
+	for _, status := range courierTransitions[from] {
+		if status == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: courier can not change status from %q to %q", model.ErrConflict, from, to)
+}
+
+// validateCourierStatusChange checks a status change requested by the courier,
+// going active is only allowed during a planned shift
+func validateCourierStatusChange(from string, to string, capacity *model.CourierCapacity) error {
+	err := validateCourierTransition(from, to)
+	if err != nil {
+		return err
+	}
+	if to == model.CourierStatusActive && !capacity.OnShift {
+		return fmt.Errorf("%w: courier is not on shift", model.ErrConflict)
+	}
+	return nil
+}
+
+// checkCourierCapacity returns model.ErrConflict when the courier can not take one more delivery
+func checkCourierCapacity(capacity *model.CourierCapacity, delivery *model.DeliveryGet) error {
+	if !capacity.OnShift {
+		return fmt.Errorf("%w: courier is not on shift", model.ErrConflict)
+	}
+	if capacity.ActiveDeliveries >= capacity.MaxActiveDeliveries {
+		return fmt.Errorf("%w: courier already holds %d deliveries", model.ErrConflict, capacity.ActiveDeliveries)
+	}
+	if capacity.MaxWeightKg != nil && capacity.ActiveWeightKg+delivery.WeightKg > *capacity.MaxWeightKg {
+		return fmt.Errorf("%w: delivery exceeds vehicle weight limit", model.ErrConflict)
+	}
+	return checkVehicleLimits(capacity, delivery)
+}
+
+// GoOnline makes the courier available for deliveries, only during a planned shift
+func (srv *AvailabilityService) GoOnline(ctx context.Context, userId uuid.UUID) error {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	capacity, err := srv.rps.GetCourierCapacity(ctx, courier.Id, srv.clock.Now())
+	if err != nil {
+		return fmt.Errorf("GetCourierCapacity: %w", err)
+	}
+	return srv.changeStatus(ctx, courier, model.CourierStatusActive, capacity)
+}
+
+// GoOffline stops new deliveries from reaching the courier, the ones they hold stay assigned
+func (srv *AvailabilityService) GoOffline(ctx context.Context, userId uuid.UUID) error {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	return srv.changeStatus(ctx, courier, model.CourierStatusOffShift, nil)
+}
+
+// changeStatus moves the courier to the given status, capacity is only needed to go active
+func (srv *AvailabilityService) changeStatus(ctx context.Context, courier *model.Courier, status string, capacity *model.CourierCapacity) error {
+	err := validateCourierStatusChange(courier.Status, status, capacity)
+	if err != nil {
+		return fmt.Errorf("validateCourierStatusChange: %w", err)
+	}
+	changed, err := srv.rps.UpdateCourierStatusFrom(ctx, courier.Id, courier.Status, status)
+	if err != nil {
+		return fmt.Errorf("UpdateCourierStatusFrom: %w", err)
+	}
+	if !changed {
+		return fmt.Errorf("UpdateCourierStatusFrom: %w: courier status has changed meanwhile", model.ErrConflict)
+	}
+	return nil
+}
+
+func (srv *AvailabilityService) GetMyShifts(ctx context.Context, userId uuid.UUID) ([]*model.CourierShift, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	now := srv.clock.Now()
+	shifts, err := srv.rps.GetCourierShifts(ctx, courier.Id, now)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierShifts: %w", err)
+	}
+	var result []*model.CourierShift
+	for _, shift := range shifts {
+		if shift.StartsAt.Before(now.Add(shiftLookahead)) {
+			result = append(result, shift)
+		}
+	}
+	return result, nil
+}
+
+func (srv *AvailabilityService) SetCourierCapacity(ctx context.Context, update *model.CourierCapacityUpdate) error {
+	if update.MaxActiveDeliveries < 1 {
+		return fmt.Errorf("%w: max_active_deliveries must be positive", model.ErrValidation)
+	}
+	if update.MaxWeightKg != nil && *update.MaxWeightKg <= 0 {
+		return fmt.Errorf("%w: max_weight_kg must be positive", model.ErrValidation)
+	}
+	if update.VehicleType != nil && !vehicleTypes[*update.VehicleType] {
+		return fmt.Errorf("%w: unknown vehicle type %q", model.ErrValidation, *update.VehicleType)
+	}
+	err := srv.rps.UpdateCourierCapacity(ctx, update)
+	if err != nil {
+		return fmt.Errorf("UpdateCourierCapacity: %w", err)
+	}
+	return nil
+}
+
+// PlanShift adds a working period for the courier, times are stored in UTC
+func (srv *AvailabilityService) PlanShift(ctx context.Context, create *model.CourierShiftCreate) (*model.CourierShift, error) {
+	if create.StartsAt.IsZero() || create.EndsAt.IsZero() {
+		return nil, fmt.Errorf("%w: starts_at and ends_at are required", model.ErrValidation)
+	}
+	if !create.EndsAt.After(create.StartsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", model.ErrValidation)
+	}
+	courier, err := srv.rps.GetCourierByUserID(ctx, create.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	shift := &model.CourierShift{
+		Id:        uuid.New(),
+		CourierId: courier.Id,
+		StartsAt:  create.StartsAt.UTC(),
+		EndsAt:    create.EndsAt.UTC(),
+	}
+	err = srv.rps.InsertCourierShift(ctx, shift)
+	if err != nil {
+		return nil, fmt.Errorf("InsertCourierShift: %w", err)
+	}
+	return shift, nil
+}
+
+func (srv *AvailabilityService) CancelShift(ctx context.Context, shiftId uuid.UUID) error {
+	err := srv.rps.DeleteCourierShift(ctx, shiftId)
+	if err != nil {
+		return fmt.Errorf("DeleteCourierShift: %w", err)
+	}
+	return nil
+}
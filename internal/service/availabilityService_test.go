@@ -0,0 +1,24 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateCourierTransition checks which status changes a courier may make by themselves
+func TestValidateCourierTransition(t *testing.T) {
+	require.NoError(t, validateCourierTransition(model.CourierStatusOffShift, model.CourierStatusActive))
+	require.NoError(t, validateCourierTransition(model.CourierStatusActive, model.CourierStatusOffShift))
+	require.ErrorIs(t, validateCourierTransition(model.CourierStatusActive, model.CourierStatusActive), model.ErrConflict)
+	require.ErrorIs(t, validateCourierTransition(model.CourierStatusSuspended, model.CourierStatusActive), model.ErrConflict)
+	require.ErrorIs(t, validateCourierTransition(model.CourierStatusSuspended, model.CourierStatusOffShift), model.ErrConflict)
+}
+
+// TestValidateCourierStatusChange checks that going active needs a shift
+func TestValidateCourierStatusChange(t *testing.T) {
+	require.ErrorIs(t, validateCourierStatusChange(model.CourierStatusOffShift, model.CourierStatusActive, &model.CourierCapacity{}), model.ErrConflict)
+	require.NoError(t, validateCourierStatusChange(model.CourierStatusOffShift, model.CourierStatusActive, &model.CourierCapacity{OnShift: true}))
+	require.NoError(t, validateCourierStatusChange(model.CourierStatusActive, model.CourierStatusOffShift, nil))
+}
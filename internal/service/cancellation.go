@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// cancelReasons are the accepted cancellation reasons, CancelOther needs a comment
+var cancelReasons = map[string]bool{
+	model.CancelNoLongerNeeded: true,
+	model.CancelWrongDetails:   true,
+	model.CancelDuplicate:      true,
+	model.CancelTooLate:        true,
+	model.CancelOperational:    true,
+	model.CancelOther:          true,
+}
+
+// CancellationFees maps the state of a delivery when it is cancelled to the fee charged,
+// states without an entry are free
+type CancellationFees map[string]float64
+
+// NewCancellationFees rejects unknown states and negative fees
+func NewCancellationFees(fees map[string]float64) (CancellationFees, error) {
+	for state, fee := range fees {
+		switch state {
+		case model.CancelStateCreated, model.CancelStateAssigned, model.CancelStatePickedUp:
+		default:
+			return nil, fmt.Errorf("unknown cancellation state: %s", state)
+		}
+		if fee < 0 {
+			return nil, fmt.Errorf("negative cancellation fee for %s", state)
+		}
+	}
+	return CancellationFees(fees), nil
+}
+
+// DeliveryCanceller closes a delivery that is still in the given state
+type DeliveryCanceller interface {
+	CancelDelivery(ctx context.Context, cancellation *model.Cancellation, expected *model.DeliveryGet) error
+}
+
+// cancellationState tells how far the delivery got, which decides the fee
+func cancellationState(delivery *model.DeliveryGet) string {
+	switch {
+	case delivery.PickedUpAt != nil || delivery.DeliveryStatus == model.DeliveryStatusPickedUp:
+		return model.CancelStatePickedUp
+	case delivery.CourierId != nil:
+		return model.CancelStateAssigned
+	default:
+		return model.CancelStateCreated
+	}
+}
+
+// validateCancelRequest checks the reason and trims the comment
+func validateCancelRequest(request *model.CancelRequest) error {
+	if !cancelReasons[request.Reason] {
+		return fmt.Errorf("%w: unknown reason %q", model.ErrValidation, request.Reason)
+	}
+	request.Comment = strings.TrimSpace(request.Comment)
+	if request.Reason == model.CancelOther && request.Comment == "" {
+		return fmt.Errorf("%w: comment is required for reason other", model.ErrValidation)
+	}
+	if len(request.Comment) > 500 {
+		return fmt.Errorf("%w: comment is longer than 500 characters", model.ErrValidation)
+	}
+	return nil
+}
+
+// cancelDelivery closes an open delivery at now and unassigns its courier, the fee is computed from the state
+// the delivery is in; if the delivery changes in the meantime model.ErrConflict is returned
+func cancelDelivery(ctx context.Context, rps DeliveryCanceller, fees CancellationFees, delivery *model.DeliveryGet,
+	request *model.CancelRequest, userId uuid.UUID, role string, waiveFee bool, now time.Time) (*model.Cancellation, error) {
+	if deliveryClosed(delivery.DeliveryStatus) {
+		return nil, fmt.Errorf("%w: delivery is %s", model.ErrConflict, delivery.DeliveryStatus)
+	}
+	cancellation := &model.Cancellation{
+		DeliveryId:  delivery.Id,
+		CancelledBy: userId,
+		Role:        role,
+		Reason:      request.Reason,
+		Comment:     request.Comment,
+		State:       cancellationState(delivery),
+		CourierId:   delivery.CourierId,
+		CancelledAt: now.UTC(),
+	}
+	if !waiveFee {
+		cancellation.Fee = fees[cancellation.State]
+	}
+	err := rps.CancelDelivery(ctx, cancellation, delivery)
+	if err != nil {
+		return nil, fmt.Errorf("CancelDelivery: %w", err)
+	}
+	return cancellation, nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCancelRepository struct {
+	ClientRepository
+	delivery     *model.DeliveryGet
+	cancellation *model.Cancellation
+}
+
+func (r *fakeCancelRepository) GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error) {
+	return r.delivery, nil
+}
+
+func (r *fakeCancelRepository) CancelDelivery(ctx context.Context, cancellation *model.Cancellation, expected *model.DeliveryGet) error {
+	r.cancellation = cancellation
+	return nil
+}
+
+// TestCancellationFees checks that the fee follows the delivery state and can be waived
+func TestCancellationFees(t *testing.T) {
+	fees, err := NewCancellationFees(map[string]float64{model.CancelStateAssigned: 2, model.CancelStatePickedUp: 5})
+	require.NoError(t, err)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	courierId, pickedUpAt := uuid.New(), now.Add(-time.Hour)
+	request := &model.CancelRequest{Reason: model.CancelOperational}
+	repo := &fakeCancelRepository{}
+
+	cases := []struct {
+		delivery *model.DeliveryGet
+		state    string
+		fee      float64
+	}{
+		{&model.DeliveryGet{DeliveryStatus: model.DeliveryStatusCreated}, model.CancelStateCreated, 0},
+		{&model.DeliveryGet{DeliveryStatus: model.DeliveryStatusCreated, CourierId: &courierId}, model.CancelStateAssigned, 2},
+		{&model.DeliveryGet{DeliveryStatus: model.DeliveryStatusPickedUp, CourierId: &courierId, PickedUpAt: &pickedUpAt}, model.CancelStatePickedUp, 5},
+	}
+	for _, tc := range cases {
+		cancellation, err := cancelDelivery(context.Background(), repo, fees, tc.delivery, request, uuid.New(), model.CancelledByManager, false, now)
+		require.NoError(t, err)
+		require.Equal(t, now, cancellation.CancelledAt)
+		require.Equal(t, tc.state, cancellation.State)
+		require.Equal(t, tc.fee, cancellation.Fee)
+		require.Equal(t, tc.delivery.CourierId, cancellation.CourierId)
+	}
+
+	cancellation, err := cancelDelivery(context.Background(), repo, fees, cases[2].delivery, request, uuid.New(), model.CancelledByManager, true, now)
+	require.NoError(t, err)
+	require.Zero(t, cancellation.Fee)
+
+	_, err = cancelDelivery(context.Background(), repo, fees, &model.DeliveryGet{DeliveryStatus: model.DeliveryStatusDelivered}, request, uuid.New(), model.CancelledByManager, false, now)
+	require.ErrorIs(t, err, model.ErrConflict)
+
+	_, err = NewCancellationFees(map[string]float64{"test_state": 1})
+	require.Error(t, err)
+}
+
+// TestClientCancelRules checks that clients can only cancel their own deliveries before pickup and must give a reason
+func TestClientCancelRules(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clientId, pickedUpAt := uuid.New(), now.Add(-time.Hour)
+	repo := &fakeCancelRepository{delivery: &model.DeliveryGet{Id: uuid.New(), ClientId: &clientId, DeliveryStatus: model.DeliveryStatusCreated}}
+	srv := NewClientService(repo, CancellationFees{}, nil, &fixedClock{now})
+	ctx := context.Background()
+
+	_, err := srv.CancelOrder(ctx, clientId, &model.CancelRequest{DeliveryId: model.DeliveryId{Id: repo.delivery.Id}, Reason: model.CancelOther})
+	require.ErrorIs(t, err, model.ErrValidation)
+
+	_, err = srv.CancelOrder(ctx, uuid.New(), &model.CancelRequest{DeliveryId: model.DeliveryId{Id: repo.delivery.Id}, Reason: model.CancelDuplicate})
+	require.ErrorIs(t, err, model.ErrNotFound)
+
+	repo.delivery.PickedUpAt = &pickedUpAt
+	_, err = srv.CancelOrder(ctx, clientId, &model.CancelRequest{DeliveryId: model.DeliveryId{Id: repo.delivery.Id}, Reason: model.CancelDuplicate})
+	require.ErrorIs(t, err, model.ErrConflict)
+	require.Nil(t, repo.cancellation)
+
+	repo.delivery.PickedUpAt = nil
+	cancellation, err := srv.CancelOrder(ctx, clientId, &model.CancelRequest{DeliveryId: model.DeliveryId{Id: repo.delivery.Id}, Reason: model.CancelDuplicate})
+	require.NoError(t, err)
+	require.Equal(t, model.CancelledByClient, cancellation.Role)
+	require.Equal(t, clientId, cancellation.CancelledBy)
+	require.Equal(t, now, cancellation.CancelledAt)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// maxHandoverComment limits the manager's note on a handover
+const maxHandoverComment = 500
+
+type CashService struct {
+	rps   CashRepository
+	clock Clock
+}
+
+func NewCashService(rps CashRepository, clock Clock) *CashService {
+	return &CashService{rps: rps, clock: clock}
+}
+
+type CashRepository interface {
+	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
+	GetCashBalance(ctx context.Context, courierId uuid.UUID) (float64, error)
+	GetCashEntries(ctx context.Context, courierId uuid.UUID, filter *model.DeliveryFilter) ([]*model.CashEntry, error)
+	InsertCashHandover(ctx context.Context, handover *model.CashHandover) error
+	GetCashHandovers(ctx context.Context, courierId uuid.UUID) ([]*model.CashHandover, error)
+	GetOutstandingCash(ctx context.Context) ([]*model.CourierCash, error)
+}
+
+// cashCollection checks the cash reported at the delivered transition and returns the ledger entry to book,
+// nil when the delivery is prepaid. The courier owes the full COD amount, a reported amount below it is
+// kept on the delivery as the shortfall the manager reconciles at the next handover
+func cashCollection(delivery *model.DeliveryGet, courierId uuid.UUID, collected *float64, now time.Time) (*model.CashEntry, error) {
+	if delivery.CodAmount == 0 {
+		if collected != nil && *collected != 0 {
+			return nil, fmt.Errorf("%w: delivery is not cash on delivery", model.ErrValidation)
+		}
+		return nil, nil
+	}
+	if collected == nil {
+		return nil, fmt.Errorf("%w: collected_amount is required for cash on delivery", model.ErrValidation)
+	}
+	if *collected < 0 || *collected > maxCodAmount {
+		return nil, fmt.Errorf("%w: collected_amount must be 0 to %d", model.ErrValidation, maxCodAmount)
+	}
+	deliveryId := delivery.Id
+	return &model.CashEntry{Id: uuid.New(), CourierId: courierId, Kind: model.CashEntryCollection, Amount: roundCents(delivery.CodAmount),
+		DeliveryId: &deliveryId, CreatedAt: now}, nil
+}
+
+// GetCashLedger returns the courier's cash balance and the ledger entries of the period
+func (srv *CashService) GetCashLedger(ctx context.Context, userId uuid.UUID, filter *model.DeliveryFilter) (*model.CashLedger, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	balance, err := srv.rps.GetCashBalance(ctx, courier.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GetCashBalance: %w", err)
+	}
+	entries, err := srv.rps.GetCashEntries(ctx, courier.Id, filter)
+	if err != nil {
+		return nil, fmt.Errorf("GetCashEntries: %w", err)
+	}
+	return &model.CashLedger{CourierId: courier.Id, Balance: balance, Entries: entries}, nil
+}
+
+// RecordHandover reconciles the cash a manager counted with the courier's balance and books the handover,
+// it returns model.ErrConflict when cash was collected while the handover was recorded
+func (srv *CashService) RecordHandover(ctx context.Context, managerId uuid.UUID, request *model.CashHandoverRequest) (*model.CashHandover, error) {
+	request.Comment = strings.TrimSpace(request.Comment)
+	if request.Counted < 0 {
+		return nil, fmt.Errorf("%w: counted must not be negative", model.ErrValidation)
+	}
+	if len([]rune(request.Comment)) > maxHandoverComment {
+		return nil, fmt.Errorf("%w: comment must be at most %d characters", model.ErrValidation, maxHandoverComment)
+	}
+	courier, err := srv.rps.GetCourierByUserID(ctx, request.CourierUserId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	expected, err := srv.rps.GetCashBalance(ctx, courier.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GetCashBalance: %w", err)
+	}
+	counted := roundCents(request.Counted)
+	handover := &model.CashHandover{
+		Id:           uuid.New(),
+		CourierId:    courier.Id,
+		ManagerId:    managerId,
+		Expected:     expected,
+		Counted:      counted,
+		Discrepancy:  roundCents(counted - expected),
+		Comment:      request.Comment,
+		HandedOverAt: srv.clock.Now().UTC(),
+	}
+	err = srv.rps.InsertCashHandover(ctx, handover)
+	if err != nil {
+		return nil, fmt.Errorf("InsertCashHandover: %w", err)
+	}
+	return handover, nil
+}
+
+func (srv *CashService) GetCashHandovers(ctx context.Context, userId uuid.UUID) ([]*model.CashHandover, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	handovers, err := srv.rps.GetCashHandovers(ctx, courier.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GetCashHandovers: %w", err)
+	}
+	return handovers, nil
+}
+
+func (srv *CashService) GetOutstandingCash(ctx context.Context) ([]*model.CourierCash, error) {
+	cash, err := srv.rps.GetOutstandingCash(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetOutstandingCash: %w", err)
+	}
+	return cash, nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCashCollection checks that cash is required for cash on delivery and refused otherwise
+func TestCashCollection(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	courierId := uuid.New()
+	prepaid := &model.DeliveryGet{Id: uuid.New()}
+	cod := &model.DeliveryGet{Id: uuid.New(), CodAmount: 25}
+	amount, zero, negative := 20.0, 0.0, -1.0
+
+	entry, err := cashCollection(prepaid, courierId, nil, now)
+	require.NoError(t, err)
+	require.Nil(t, entry)
+	entry, err = cashCollection(prepaid, courierId, &zero, now)
+	require.NoError(t, err)
+	require.Nil(t, entry)
+	_, err = cashCollection(prepaid, courierId, &amount, now)
+	require.ErrorIs(t, err, model.ErrValidation)
+
+	_, err = cashCollection(cod, courierId, nil, now)
+	require.ErrorIs(t, err, model.ErrValidation)
+	_, err = cashCollection(cod, courierId, &negative, now)
+	require.ErrorIs(t, err, model.ErrValidation)
+
+	// a smaller amount is accepted, the courier still owes the COD amount
+	entry, err = cashCollection(cod, courierId, &amount, now)
+	require.NoError(t, err)
+	require.Equal(t, model.CashEntryCollection, entry.Kind)
+	require.Equal(t, 25.0, entry.Amount)
+	require.Equal(t, cod.Id, *entry.DeliveryId)
+	require.Equal(t, courierId, entry.CourierId)
+}
+
+type fakeCashRepository struct {
+	CashRepository
+	courier  *model.Courier
+	balance  float64
+	handover *model.CashHandover
+}
+
+func (r *fakeCashRepository) GetCourierByUserID(ctx context.Context, userId uuid.UUID) (*model.Courier, error) {
+	return r.courier, nil
+}
+
+func (r *fakeCashRepository) GetCashBalance(ctx context.Context, courierId uuid.UUID) (float64, error) {
+	return r.balance, nil
+}
+
+func (r *fakeCashRepository) InsertCashHandover(ctx context.Context, handover *model.CashHandover) error {
+	r.handover = handover
+	return nil
+}
+
+// TestRecordHandover checks that the discrepancy is the counted cash minus the balance
+func TestRecordHandover(t *testing.T) {
+	now := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	repo := &fakeCashRepository{courier: &model.Courier{Id: uuid.New()}, balance: 120.5}
+	srv := NewCashService(repo, &fixedClock{now})
+	managerId := uuid.New()
+
+	handover, err := srv.RecordHandover(context.Background(), managerId, &model.CashHandoverRequest{Counted: 110, Comment: " short "})
+	require.NoError(t, err)
+	require.Equal(t, repo.handover, handover)
+	require.Equal(t, 120.5, handover.Expected)
+	require.Equal(t, -10.5, handover.Discrepancy)
+	require.Equal(t, "short", handover.Comment)
+	require.Equal(t, managerId, handover.ManagerId)
+	require.Equal(t, now, handover.HandedOverAt)
+
+	_, err = srv.RecordHandover(context.Background(), managerId, &model.CashHandoverRequest{Counted: -1})
+	require.ErrorIs(t, err, model.ErrValidation)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+type ClientService struct {
+	rps    ClientRepository
+	fees   CancellationFees
+	pricer DeliveryPricer
+	clock  Clock
+}
+
+func NewClientService(rps ClientRepository, fees CancellationFees, pricer DeliveryPricer, clock Clock) *ClientService {
+	return &ClientService{rps: rps, fees: fees, pricer: pricer, clock: clock}
+}
+
+type ClientRepository interface {
+	InsertDelivery(context.Context, *model.Delivery) error
+	GetDeliveriesByClientID(ctx context.Context, clientId uuid.UUID) ([]*model.DeliveryGet, error)
+	GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error)
+	CancelDelivery(ctx context.Context, cancellation *model.Cancellation, expected *model.DeliveryGet) error
+	ReissueTrackingCode(ctx context.Context, deliveryId uuid.UUID, code string) error
+}
+
+// PlaceOrder creates a delivery owned by the client, the result carries the new id, tracking code and frozen price
+func (srv *ClientService) PlaceOrder(ctx context.Context, clientId uuid.UUID, delivery *model.Delivery) (*model.Delivery, error) {
+	err := validateDelivery(delivery)
+	if err != nil {
+		return nil, fmt.Errorf("validateDelivery: %w", err)
+	}
+	err = srv.pricer.PriceDelivery(ctx, delivery)
+	if err != nil {
+		return nil, fmt.Errorf("PriceDelivery: %w", err)
+	}
+	delivery.ClientId = &clientId
+	delivery.CourierId = uuid.Nil
+	delivery.CreatedAt = srv.clock.Now().UTC()
+	delivery.DeliveryStatus = model.DeliveryStatusCreated
+	delivery.PickedUpAt, delivery.DeliveredAt = nil, nil
+	delivery.TrackingCode, err = newTrackingCode()
+	if err != nil {
+		return nil, fmt.Errorf("newTrackingCode: %w", err)
+	}
+	delivery.Pin, err = newDeliveryPin()
+	if err != nil {
+		return nil, fmt.Errorf("newDeliveryPin: %w", err)
+	}
+
+	err = srv.rps.InsertDelivery(ctx, delivery)
+	if err != nil {
+		return nil, fmt.Errorf("InsertDelivery: %w", err)
+	}
+	return delivery, nil
+}
+
+func (srv *ClientService) GetOrderHistory(ctx context.Context, clientId uuid.UUID) ([]*model.DeliveryGet, error) {
+	deliveries, err := srv.rps.GetDeliveriesByClientID(ctx, clientId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveriesByClientID: %w", err)
+	}
+	return deliveries, nil
+}
+
+// GetOrder returns model.ErrNotFound for deliveries of other clients so their existence is not revealed
+func (srv *ClientService) GetOrder(ctx context.Context, clientId uuid.UUID, deliveryId uuid.UUID) (*model.DeliveryGet, error) {
+	delivery, err := srv.rps.GetDeliveryByID(ctx, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryByID: %w", err)
+	}
+	if delivery.ClientId == nil || *delivery.ClientId != clientId {
+		return nil, fmt.Errorf("GetOrder: %w", model.ErrNotFound)
+	}
+	return delivery, nil
+}
+
+// CancelOrder cancels the client's delivery if it has not been picked up yet,
+// the cancellation fee depends on whether a courier was already assigned
+func (srv *ClientService) CancelOrder(ctx context.Context, clientId uuid.UUID, request *model.CancelRequest) (*model.Cancellation, error) {
+	err := validateCancelRequest(request)
+	if err != nil {
+		return nil, fmt.Errorf("validateCancelRequest: %w", err)
+	}
+	delivery, err := srv.GetOrder(ctx, clientId, request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if cancellationState(delivery) == model.CancelStatePickedUp {
+		return nil, fmt.Errorf("CancelOrder: %w: delivery is already picked up", model.ErrConflict)
+	}
+	cancellation, err := cancelDelivery(ctx, srv.rps, srv.fees, delivery, request, clientId, model.CancelledByClient, false, srv.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("cancelDelivery: %w", err)
+	}
+	return cancellation, nil
+}
+
+// ReissueTrackingCode revokes the tracking code of the client's delivery and issues a new one
+func (srv *ClientService) ReissueTrackingCode(ctx context.Context, clientId uuid.UUID, deliveryId uuid.UUID) (string, error) {
+	_, err := srv.GetOrder(ctx, clientId, deliveryId)
+	if err != nil {
+		return "", err
+	}
+	code, err := newTrackingCode()
+	if err != nil {
+		return "", fmt.Errorf("newTrackingCode: %w", err)
+	}
+	err = srv.rps.ReissueTrackingCode(ctx, deliveryId, code)
+	if err != nil {
+		return "", fmt.Errorf("ReissueTrackingCode: %w", err)
+	}
+	return code, nil
+}
@@ -0,0 +1,15 @@
+package service
+
+import "time"
+
+// Clock abstracts the current time so that time-dependent logic can be tested deterministically
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock returns the real UTC time
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now().UTC()
+}
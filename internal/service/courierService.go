@@ -3,69 +3,209 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/liza/labwork_45/internal/model"
 )
 
 type CourierService struct {
-	rps CourierRepository
+	rps    CourierRepository
+	pricer DeliveryPricer
+	clock  Clock
 }
 
-func NewCourierService(rps CourierRepository) *CourierService {
-	return &CourierService{rps: rps}
+func NewCourierService(rps CourierRepository, pricer DeliveryPricer, clock Clock) *CourierService {
+	return &CourierService{rps: rps, pricer: pricer, clock: clock}
 }
 
 type CourierRepository interface {
-	UpdateCourierInfo(context.Context, uuid.UUID, *model.Courier) error
+	PatchCourier(ctx context.Context, userId uuid.UUID, patch *model.CourierPatch, currentStatus string) (*model.Courier, error)
 	InsertDelivery(context.Context, *model.Delivery) error
-	GetAllDeliveries(context.Context) ([]*model.DeliveryGet, error)
-	UpdateDeliveryCourier(ctx context.Context, deliveryId uuid.UUID, courieerId uuid.UUID) error
+	GetAllDeliveries(context.Context, *model.DeliveryFilter) ([]*model.DeliveryGet, error)
+	GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error)
+	AssignDeliveryWithinCapacity(ctx context.Context, deliveryId uuid.UUID, courierId uuid.UUID, now time.Time,
+		check func(*model.CourierCapacity, *model.DeliveryGet) error) error
+	GetCourierCapacity(ctx context.Context, courierId uuid.UUID, now time.Time) (*model.CourierCapacity, error)
 	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
-	UpdateStatus(context.Context, *model.DeliveryStatus) error
+	PickUpDelivery(ctx context.Context, deliveryId uuid.UUID, courierId uuid.UUID, at time.Time) error
+	GetDeliveryItems(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryItem, error)
+	GetCourierZones(ctx context.Context, courierId uuid.UUID) ([]*model.Zone, error)
 }
 
-func (srv *CourierService) UpdateCourier(ctx context.Context, userId uuid.UUID, courier *model.Courier) error {
-	err := srv.rps.UpdateCourierInfo(ctx, userId, courier)
+// UpdateCourier lets the courier change their name and surname, a status change has to be
+// a valid transition and the performance indicator can not be changed at all
+func (srv *CourierService) UpdateCourier(ctx context.Context, userId uuid.UUID, patch *model.CourierPatch) (*model.Courier, error) {
+	err := validateCourierPatch(patch)
 	if err != nil {
-		return fmt.Errorf("UpdateStatus: %w", err)
+		return nil, fmt.Errorf("validateCourierPatch: %w", err)
+	}
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	if patch.Status != nil && *patch.Status != courier.Status {
+		capacity, err := srv.rps.GetCourierCapacity(ctx, courier.Id, srv.clock.Now().UTC())
+		if err != nil {
+			return nil, fmt.Errorf("GetCourierCapacity: %w", err)
+		}
+		err = validateCourierStatusChange(courier.Status, *patch.Status, capacity)
+		if err != nil {
+			return nil, fmt.Errorf("validateCourierStatusChange: %w", err)
+		}
+	}
+	updated, err := srv.rps.PatchCourier(ctx, userId, patch, courier.Status)
+	if err != nil {
+		return nil, fmt.Errorf("PatchCourier: %w", err)
+	}
+	return updated, nil
+}
+
+// maxNameLength limits courier name and surname
+const maxNameLength = 100
+
+// validateCourierPatch trims provided names and rejects empty or too long ones
+func validateCourierPatch(patch *model.CourierPatch) error {
+	for field, value := range map[string]*string{"name": patch.Name, "surname": patch.Surname} {
+		if value == nil {
+			continue
+		}
+		*value = strings.TrimSpace(*value)
+		if *value == "" || len([]rune(*value)) > maxNameLength {
+			return fmt.Errorf("%w: %s must be 1 to %d characters", model.ErrValidation, field, maxNameLength)
+		}
 	}
 	return nil
 }
 
 func (srv *CourierService) CreateDelivery(ctx context.Context, delivery *model.Delivery) error {
-	err := srv.rps.InsertDelivery(ctx, delivery)
+	err := validateDelivery(delivery)
+	if err != nil {
+		return fmt.Errorf("validateDelivery: %w", err)
+	}
+	err = srv.pricer.PriceDelivery(ctx, delivery)
+	if err != nil {
+		return fmt.Errorf("PriceDelivery: %w", err)
+	}
+	delivery.CreatedAt = srv.clock.Now().UTC()
+	if delivery.DeliveryStatus == "" {
+		delivery.DeliveryStatus = model.DeliveryStatusCreated
+	}
+	delivery.TrackingCode, err = newTrackingCode()
+	if err != nil {
+		return fmt.Errorf("newTrackingCode: %w", err)
+	}
+	delivery.Pin, err = newDeliveryPin()
+	if err != nil {
+		return fmt.Errorf("newDeliveryPin: %w", err)
+	}
+	err = srv.rps.InsertDelivery(ctx, delivery)
 	if err != nil {
 		return fmt.Errorf("InsertDelivery: %w", err)
 	}
 	return nil
 }
 
-func (srv *CourierService) GetAllDeliveries(ctx context.Context) ([]*model.DeliveryGet, error) {
-	deliveries, err := srv.rps.GetAllDeliveries(ctx)
+// GetAllDeliveries lists deliveries within the filter without their recipients,
+// a courier filter hides deliveries outside their home zones
+func (srv *CourierService) GetAllDeliveries(ctx context.Context, filter *model.DeliveryFilter) ([]*model.DeliveryGet, error) {
+	deliveries, err := srv.rps.GetAllDeliveries(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("GetAllDeliveries: %w", err)
 	}
 	return deliveries, nil
 }
 
+// GetDelivery returns a delivery assigned to the courier together with its items,
+// deliveries of other couriers are reported as not found
+func (srv *CourierService) GetDelivery(ctx context.Context, userId uuid.UUID, deliveryId uuid.UUID) (*model.DeliveryGet, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	delivery, err := srv.rps.GetDeliveryByID(ctx, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryByID: %w", err)
+	}
+	if delivery.CourierId == nil || *delivery.CourierId != courier.Id {
+		return nil, fmt.Errorf("GetDelivery: %w", model.ErrNotFound)
+	}
+	delivery.Items, err = srv.rps.GetDeliveryItems(ctx, delivery.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryItems: %w", err)
+	}
+	return delivery, nil
+}
+
+// AssignCourierToDelivery lets an active courier claim a free delivery of their home zones while they are on shift and have room for it
 func (srv *CourierService) AssignCourierToDelivery(ctx context.Context, deliveryId uuid.UUID, userId uuid.UUID) error {
 	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
 	if err != nil {
 		return fmt.Errorf("GetCourierByUserID: %w", err)
 	}
-
-	err = srv.rps.UpdateDeliveryCourier(ctx, deliveryId, courier.Id)
+	if courier.Status != model.CourierStatusActive {
+		return fmt.Errorf("AssignCourierToDelivery: %w: courier is %s", model.ErrConflict, courier.Status)
+	}
+	delivery, err := srv.rps.GetDeliveryByID(ctx, deliveryId)
+	if err != nil {
+		return fmt.Errorf("GetDeliveryByID: %w", err)
+	}
+	if delivery.Legs > 0 {
+		return fmt.Errorf("AssignCourierToDelivery: %w: multi-leg deliveries are claimed leg by leg", model.ErrConflict)
+	}
+	zones, err := srv.rps.GetCourierZones(ctx, courier.Id)
+	if err != nil {
+		return fmt.Errorf("GetCourierZones: %w", err)
+	}
+	err = checkHomeZone(zones, delivery)
+	if err != nil {
+		return fmt.Errorf("checkHomeZone: %w", err)
+	}
+	err = srv.rps.AssignDeliveryWithinCapacity(ctx, deliveryId, courier.Id, srv.clock.Now().UTC(), checkCourierCapacity)
 	if err != nil {
-		return fmt.Errorf("UpdateDeliveryCourier: %w", err)
+		return fmt.Errorf("AssignDeliveryWithinCapacity: %w", err)
 	}
 	return nil
 }
 
-func (srv *CourierService) UpdateDeliveryStatus(ctx context.Context, delivery *model.DeliveryStatus) error {
-	err := srv.rps.UpdateStatus(ctx, delivery)
+// UpdateDeliveryStatus lets the courier mark their delivery picked up, deliveries are completed with proof
+// and failed attempts are reported separately so their cash, earnings and return flows run
+func (srv *CourierService) UpdateDeliveryStatus(ctx context.Context, userId uuid.UUID, update *model.DeliveryStatus) error {
+	switch update.DeliveryStatus {
+	case model.DeliveryStatusPickedUp:
+	case model.DeliveryStatusDelivered:
+		return fmt.Errorf("%w: delivered status requires proof, use /courier/deliver", model.ErrValidation)
+	case model.DeliveryStatusReturned:
+		return fmt.Errorf("%w: report failed attempts with /courier/failed_attempt", model.ErrValidation)
+	case model.DeliveryStatusCancelled:
+		return fmt.Errorf("%w: couriers can not cancel deliveries", model.ErrForbidden)
+	default:
+		return fmt.Errorf("%w: couriers can only set the %s status", model.ErrValidation, model.DeliveryStatusPickedUp)
+	}
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	delivery, err := srv.rps.GetDeliveryByID(ctx, update.Id)
+	if err != nil {
+		return fmt.Errorf("GetDeliveryByID: %w", err)
+	}
+	if delivery.CourierId == nil || *delivery.CourierId != courier.Id {
+		return fmt.Errorf("UpdateDeliveryStatus: %w", model.ErrNotFound)
+	}
+	if deliveryClosed(delivery.DeliveryStatus) {
+		return fmt.Errorf("UpdateDeliveryStatus: %w: delivery is %s", model.ErrConflict, delivery.DeliveryStatus)
+	}
+	if delivery.DeliveryStatus != model.DeliveryStatusCreated {
+		return fmt.Errorf("UpdateDeliveryStatus: %w: delivery is already %s", model.ErrConflict, delivery.DeliveryStatus)
+	}
+	if delivery.Legs > 0 {
+		return fmt.Errorf("UpdateDeliveryStatus: %w: legs of a multi-leg delivery are picked up with /courier/pickup_leg", model.ErrConflict)
+	}
+	err = srv.rps.PickUpDelivery(ctx, delivery.Id, courier.Id, srv.clock.Now().UTC())
 	if err != nil {
-		return fmt.Errorf("UpdateStatus: %w", err)
+		return fmt.Errorf("PickUpDelivery: %w", err)
 	}
 	return nil
 }
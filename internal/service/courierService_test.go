@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// pickupRepository serves one courier and one delivery
+type pickupRepository struct {
+	CourierRepository
+	courier  *model.Courier
+	delivery *model.DeliveryGet
+	pickedUp bool
+}
+
+func (r *pickupRepository) GetCourierByUserID(_ context.Context, _ uuid.UUID) (*model.Courier, error) {
+	return r.courier, nil
+}
+
+func (r *pickupRepository) GetDeliveryByID(_ context.Context, _ uuid.UUID) (*model.DeliveryGet, error) {
+	return r.delivery, nil
+}
+
+func (r *pickupRepository) PickUpDelivery(_ context.Context, _ uuid.UUID, _ uuid.UUID, _ time.Time) error {
+	r.pickedUp = true
+	return nil
+}
+
+// TestUpdateDeliveryStatus checks that couriers only pick up their own open deliveries
+func TestUpdateDeliveryStatus(t *testing.T) {
+	courier := &model.Courier{Id: uuid.New()}
+	other := uuid.New()
+	delivery := &model.DeliveryGet{Id: uuid.New(), CourierId: &other, DeliveryStatus: model.DeliveryStatusCreated}
+	rps := &pickupRepository{courier: courier, delivery: delivery}
+	srv := NewCourierService(rps, nil, &fixedClock{time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)})
+	ctx := context.Background()
+	update := &model.DeliveryStatus{Id: delivery.Id, DeliveryStatus: model.DeliveryStatusPickedUp}
+
+	require.ErrorIs(t, srv.UpdateDeliveryStatus(ctx, uuid.New(), update), model.ErrNotFound)
+
+	delivery.CourierId = &courier.Id
+	delivery.DeliveryStatus = model.DeliveryStatusDelivered
+	require.ErrorIs(t, srv.UpdateDeliveryStatus(ctx, uuid.New(), update), model.ErrConflict)
+	for _, status := range []string{model.DeliveryStatusCreated, model.DeliveryStatusDelivered, model.DeliveryStatusReturned, "lost"} {
+		err := srv.UpdateDeliveryStatus(ctx, uuid.New(), &model.DeliveryStatus{Id: delivery.Id, DeliveryStatus: status})
+		require.ErrorIs(t, err, model.ErrValidation, status)
+	}
+	require.False(t, rps.pickedUp)
+
+	delivery.DeliveryStatus = model.DeliveryStatusCreated
+	delivery.Legs = 2
+	require.ErrorIs(t, srv.UpdateDeliveryStatus(ctx, uuid.New(), update), model.ErrConflict)
+	require.False(t, rps.pickedUp)
+
+	delivery.Legs = 0
+	require.NoError(t, srv.UpdateDeliveryStatus(ctx, uuid.New(), update))
+	require.True(t, rps.pickedUp)
+}
+
+// capacityRepository hands a fixed capacity to the check run while assigning
+type capacityRepository struct {
+	pickupRepository
+	capacity *model.CourierCapacity
+	assigned bool
+}
+
+func (r *capacityRepository) GetCourierZones(_ context.Context, _ uuid.UUID) ([]*model.Zone, error) {
+	return nil, nil
+}
+
+func (r *capacityRepository) AssignDeliveryWithinCapacity(_ context.Context, _ uuid.UUID, _ uuid.UUID, _ time.Time,
+	check func(*model.CourierCapacity, *model.DeliveryGet) error) error {
+	err := check(r.capacity, r.delivery)
+	if err != nil {
+		return err
+	}
+	r.assigned = true
+	return nil
+}
+
+// TestAssignCourierToDeliveryChecksCapacity checks that the capacity is checked by the assigning transaction
+func TestAssignCourierToDeliveryChecksCapacity(t *testing.T) {
+	courier := &model.Courier{Id: uuid.New(), Status: model.CourierStatusActive}
+	delivery := &model.DeliveryGet{Id: uuid.New(), DeliveryStatus: model.DeliveryStatusCreated}
+	rps := &capacityRepository{pickupRepository: pickupRepository{courier: courier, delivery: delivery},
+		capacity: &model.CourierCapacity{OnShift: true, ActiveDeliveries: 3, MaxActiveDeliveries: 3}}
+	srv := NewCourierService(rps, nil, &fixedClock{time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)})
+
+	require.ErrorIs(t, srv.AssignCourierToDelivery(context.Background(), delivery.Id, uuid.New()), model.ErrConflict)
+	require.False(t, rps.assigned)
+
+	rps.capacity.ActiveDeliveries = 2
+	require.NoError(t, srv.AssignCourierToDelivery(context.Background(), delivery.Id, uuid.New()))
+	require.True(t, rps.assigned)
+}
+
+// TestValidateCourierPatch checks that provided names are trimmed and empty ones rejected
+func TestValidateCourierPatch(t *testing.T) {
+	name, empty := "  test_name ", " "
+	patch := &model.CourierPatch{Name: &name}
+	require.NoError(t, validateCourierPatch(patch))
+	require.Equal(t, "test_name", *patch.Name)
+	require.Nil(t, patch.Surname)
+
+	require.ErrorIs(t, validateCourierPatch(&model.CourierPatch{Surname: &empty}), model.ErrValidation)
+}
+
+// shiftRepository reports the courier on shift only at the given moment
+type shiftRepository struct {
+	pickupRepository
+	shiftAt time.Time
+	patched bool
+}
+
+func (r *shiftRepository) GetCourierCapacity(_ context.Context, _ uuid.UUID, now time.Time) (*model.CourierCapacity, error) {
+	return &model.CourierCapacity{OnShift: now.Equal(r.shiftAt)}, nil
+}
+
+func (r *shiftRepository) PatchCourier(_ context.Context, _ uuid.UUID, _ *model.CourierPatch, _ string) (*model.Courier, error) {
+	r.patched = true
+	return r.courier, nil
+}
+
+// TestUpdateCourierGoingActive checks that going active is checked against the shift at the service clock's time
+func TestUpdateCourierGoingActive(t *testing.T) {
+	shiftAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	rps := &shiftRepository{pickupRepository: pickupRepository{courier: &model.Courier{Status: model.CourierStatusOffShift}}, shiftAt: shiftAt}
+	active := model.CourierStatusActive
+
+	_, err := NewCourierService(rps, nil, &fixedClock{shiftAt.Add(-time.Hour)}).UpdateCourier(context.Background(), uuid.New(), &model.CourierPatch{Status: &active})
+	require.ErrorIs(t, err, model.ErrConflict)
+	require.False(t, rps.patched)
+
+	_, err = NewCourierService(rps, nil, &fixedClock{shiftAt}).UpdateCourier(context.Background(), uuid.New(), &model.CourierPatch{Status: &active})
+	require.NoError(t, err)
+	require.True(t, rps.patched)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// maxCodAmount limits the cash a courier collects for one delivery
+const maxCodAmount = 10_000
+
+// phonePattern accepts international and local numbers with common separators
+var phonePattern = regexp.MustCompile(`^\+?[0-9][0-9 ()\-]{5,19}$`)
+
+// deliveryClosed reports whether the delivery reached a final status
+func deliveryClosed(status string) bool {
+	switch status {
+	case model.DeliveryStatusDelivered, model.DeliveryStatusCancelled, model.DeliveryStatusReturned:
+		return true
+	}
+	return false
+}
+
+// validateDelivery checks a delivery before it is inserted and normalizes its fields
+func validateDelivery(delivery *model.Delivery) error {
+	err := validateDeliveryWindow(delivery)
+	if err != nil {
+		return err
+	}
+	err = validateLocation("pickup", &delivery.Pickup)
+	if err != nil {
+		return err
+	}
+	err = validateLocation("dropoff", &delivery.Dropoff)
+	if err != nil {
+		return err
+	}
+	if delivery.WeightKg < 0 {
+		return fmt.Errorf("%w: weight_kg must not be negative", model.ErrValidation)
+	}
+	if delivery.CodAmount < 0 || delivery.CodAmount > maxCodAmount {
+		return fmt.Errorf("%w: cod_amount must be 0 to %d", model.ErrValidation, maxCodAmount)
+	}
+	delivery.CodAmount = roundCents(delivery.CodAmount)
+	err = validateItems(delivery)
+	if err != nil {
+		return err
+	}
+	return validateRecipient(&delivery.Recipient)
+}
+
+// validateDeliveryWindow checks the promised window and converts it to UTC
+func validateDeliveryWindow(delivery *model.Delivery) error {
+	if delivery.WindowStart == nil || delivery.WindowEnd == nil {
+		return fmt.Errorf("%w: window_start and window_end are required", model.ErrValidation)
+	}
+	if delivery.WindowEnd.Before(*delivery.WindowStart) {
+		return fmt.Errorf("%w: window_end is before window_start", model.ErrValidation)
+	}
+	start, end := delivery.WindowStart.UTC(), delivery.WindowEnd.UTC()
+	delivery.WindowStart, delivery.WindowEnd = &start, &end
+	return nil
+}
+
+// validateLocation checks that an address is complete and its coordinates are in range
+func validateLocation(name string, location *model.Location) error {
+	location.AddressLine1 = strings.TrimSpace(location.AddressLine1)
+	location.AddressLine2 = strings.TrimSpace(location.AddressLine2)
+	location.City = strings.TrimSpace(location.City)
+	location.Postcode = strings.TrimSpace(location.Postcode)
+
+	if location.AddressLine1 == "" {
+		return fmt.Errorf("%w: %s.address_line1 is required", model.ErrValidation, name)
+	}
+	if location.City == "" {
+		return fmt.Errorf("%w: %s.city is required", model.ErrValidation, name)
+	}
+	if location.Lat < -90 || location.Lat > 90 {
+		return fmt.Errorf("%w: %s.lat must be between -90 and 90", model.ErrValidation, name)
+	}
+	if location.Lon < -180 || location.Lon > 180 {
+		return fmt.Errorf("%w: %s.lon must be between -180 and 180", model.ErrValidation, name)
+	}
+	if location.Lat == 0 && location.Lon == 0 {
+		return fmt.Errorf("%w: %s coordinates are required", model.ErrValidation, name)
+	}
+	return nil
+}
+
+// validateReportedPoint checks optional coordinates reported by the courier app
+func validateReportedPoint(lat, lon *float64) error {
+	if (lat == nil) != (lon == nil) {
+		return fmt.Errorf("%w: lat and lon must be given together", model.ErrValidation)
+	}
+	if lat != nil && (*lat < -90 || *lat > 90 || *lon < -180 || *lon > 180) {
+		return fmt.Errorf("%w: lat or lon is out of range", model.ErrValidation)
+	}
+	return nil
+}
+
+// validateRecipient checks recipient contact fields
+func validateRecipient(recipient *model.Recipient) error {
+	recipient.Name = strings.TrimSpace(recipient.Name)
+	recipient.Phone = strings.TrimSpace(recipient.Phone)
+	recipient.AccessNotes = strings.TrimSpace(recipient.AccessNotes)
+
+	if recipient.Name == "" {
+		return fmt.Errorf("%w: recipient.name is required", model.ErrValidation)
+	}
+	if !phonePattern.MatchString(recipient.Phone) {
+		return fmt.Errorf("%w: recipient.phone is invalid", model.ErrValidation)
+	}
+	if len(recipient.AccessNotes) > 500 {
+		return fmt.Errorf("%w: recipient.access_notes is longer than 500 characters", model.ErrValidation)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocation() model.Location {
+	return model.Location{AddressLine1: "Nezavisimosti 4", City: "Minsk", Postcode: "220030", Lat: 53.9, Lon: 27.56}
+}
+
+func newTestDelivery() *model.Delivery {
+	start := time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	return &model.Delivery{
+		WindowStart: &start,
+		WindowEnd:   &end,
+		Pickup:      newTestLocation(),
+		Dropoff:     newTestLocation(),
+		Recipient:   model.Recipient{Name: "Anna", Phone: "+375 (29) 123-45-67"},
+	}
+}
+
+// TestValidateDeliveryWindow checks that both ends are required, the end is not before the start
+// and the window is converted to UTC
+func TestValidateDeliveryWindow(t *testing.T) {
+	minsk := time.FixedZone("Minsk", 3*60*60)
+	start := time.Date(2024, time.December, 13, 12, 0, 0, 0, minsk)
+
+	cases := []struct {
+		name  string
+		start *time.Time
+		end   *time.Time
+		valid bool
+	}{
+		{"two hours", &start, ptrTime(start.Add(2 * time.Hour)), true},
+		{"empty window", &start, &start, true},
+		{"end in another zone", &start, ptrTime(time.Date(2024, time.December, 13, 9, 0, 0, 1, time.UTC)), true},
+		{"end before start", &start, ptrTime(start.Add(-time.Nanosecond)), false},
+		{"end in another zone before start", &start, ptrTime(time.Date(2024, time.December, 13, 8, 59, 0, 0, time.UTC)), false},
+		{"no start", nil, &start, false},
+		{"no end", &start, nil, false},
+		{"no window", nil, nil, false},
+	}
+	for _, tc := range cases {
+		delivery := &model.Delivery{WindowStart: tc.start, WindowEnd: tc.end}
+		err := validateDeliveryWindow(delivery)
+		if !tc.valid {
+			require.ErrorIs(t, err, model.ErrValidation, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, time.UTC, delivery.WindowStart.Location(), tc.name)
+		require.Equal(t, time.UTC, delivery.WindowEnd.Location(), tc.name)
+		require.True(t, delivery.WindowStart.Equal(*tc.start), tc.name)
+		require.True(t, delivery.WindowEnd.Equal(*tc.end), tc.name)
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
+// TestValidateLocation checks required address fields and coordinate ranges including their bounds
+func TestValidateLocation(t *testing.T) {
+	cases := []struct {
+		name   string
+		change func(location *model.Location)
+		valid  bool
+	}{
+		{"complete", func(location *model.Location) {}, true},
+		{"lat -90", func(location *model.Location) { location.Lat = -90 }, true},
+		{"lat 90", func(location *model.Location) { location.Lat = 90 }, true},
+		{"lon -180", func(location *model.Location) { location.Lon = -180 }, true},
+		{"lon 180", func(location *model.Location) { location.Lon = 180 }, true},
+		{"zero lat only", func(location *model.Location) { location.Lat = 0 }, true},
+		{"lat below range", func(location *model.Location) { location.Lat = -90.0001 }, false},
+		{"lat above range", func(location *model.Location) { location.Lat = 90.0001 }, false},
+		{"lon below range", func(location *model.Location) { location.Lon = -180.0001 }, false},
+		{"lon above range", func(location *model.Location) { location.Lon = 180.0001 }, false},
+		{"no coordinates", func(location *model.Location) { location.Lat, location.Lon = 0, 0 }, false},
+		{"no address", func(location *model.Location) { location.AddressLine1 = "" }, false},
+		{"blank address", func(location *model.Location) { location.AddressLine1 = "   " }, false},
+		{"no city", func(location *model.Location) { location.City = "\t" }, false},
+	}
+	for _, tc := range cases {
+		location := newTestLocation()
+		tc.change(&location)
+		err := validateLocation("pickup", &location)
+		if tc.valid {
+			require.NoError(t, err, tc.name)
+		} else {
+			require.ErrorIs(t, err, model.ErrValidation, tc.name)
+		}
+	}
+
+	location := newTestLocation()
+	location.AddressLine1, location.Postcode = "  Nezavisimosti 4 ", " 220030\n"
+	require.NoError(t, validateLocation("pickup", &location))
+	require.Equal(t, "Nezavisimosti 4", location.AddressLine1)
+	require.Equal(t, "220030", location.Postcode)
+}
+
+// TestValidateRecipient checks the required name, the phone pattern and the access notes length
+func TestValidateRecipient(t *testing.T) {
+	cases := []struct {
+		name      string
+		recipient model.Recipient
+		valid     bool
+	}{
+		{"international", model.Recipient{Name: "Anna", Phone: "+375291234567"}, true},
+		{"separators", model.Recipient{Name: "Anna", Phone: "+375 (29) 123-45-67"}, true},
+		{"local", model.Recipient{Name: "Anna", Phone: "80291234567"}, true},
+		{"shortest", model.Recipient{Name: "Anna", Phone: "123456"}, true},
+		{"longest", model.Recipient{Name: "Anna", Phone: "+1" + strings.Repeat("2", 19)}, true},
+		{"padded phone", model.Recipient{Name: "Anna", Phone: " 123456 "}, true},
+		{"notes at limit", model.Recipient{Name: "Anna", Phone: "123456", AccessNotes: strings.Repeat("a", 500)}, true},
+		{"too short", model.Recipient{Name: "Anna", Phone: "12345"}, false},
+		{"too long", model.Recipient{Name: "Anna", Phone: "1" + strings.Repeat("2", 20)}, false},
+		{"letters", model.Recipient{Name: "Anna", Phone: "+37529ABC4567"}, false},
+		{"starts with separator", model.Recipient{Name: "Anna", Phone: "(29) 1234567"}, false},
+		{"plus inside", model.Recipient{Name: "Anna", Phone: "375+291234567"}, false},
+		{"no phone", model.Recipient{Name: "Anna"}, false},
+		{"no name", model.Recipient{Name: "  ", Phone: "123456"}, false},
+		{"notes too long", model.Recipient{Name: "Anna", Phone: "123456", AccessNotes: strings.Repeat("a", 501)}, false},
+	}
+	for _, tc := range cases {
+		err := validateRecipient(&tc.recipient)
+		if tc.valid {
+			require.NoError(t, err, tc.name)
+		} else {
+			require.ErrorIs(t, err, model.ErrValidation, tc.name)
+		}
+	}
+}
+
+// TestValidateDelivery checks that every part of a delivery is validated and cash on delivery stays within bounds
+func TestValidateDelivery(t *testing.T) {
+	cases := []struct {
+		name   string
+		change func(delivery *model.Delivery)
+		valid  bool
+	}{
+		{"complete", func(delivery *model.Delivery) {}, true},
+		{"zero weight", func(delivery *model.Delivery) { delivery.WeightKg = 0 }, true},
+		{"cod at limit", func(delivery *model.Delivery) { delivery.CodAmount = maxCodAmount }, true},
+		{"no window", func(delivery *model.Delivery) { delivery.WindowEnd = nil }, false},
+		{"bad pickup", func(delivery *model.Delivery) { delivery.Pickup.City = "" }, false},
+		{"bad dropoff", func(delivery *model.Delivery) { delivery.Dropoff.Lat = 91 }, false},
+		{"negative weight", func(delivery *model.Delivery) { delivery.WeightKg = -0.1 }, false},
+		{"negative cod", func(delivery *model.Delivery) { delivery.CodAmount = -1 }, false},
+		{"cod above limit", func(delivery *model.Delivery) { delivery.CodAmount = maxCodAmount + 0.01 }, false},
+		{"bad recipient", func(delivery *model.Delivery) { delivery.Recipient.Phone = "phone" }, false},
+	}
+	for _, tc := range cases {
+		delivery := newTestDelivery()
+		tc.change(delivery)
+		err := validateDelivery(delivery)
+		if tc.valid {
+			require.NoError(t, err, tc.name)
+		} else {
+			require.ErrorIs(t, err, model.ErrValidation, tc.name)
+		}
+	}
+
+	delivery := newTestDelivery()
+	delivery.CodAmount = 12.345
+	require.NoError(t, validateDelivery(delivery))
+	require.Equal(t, 12.35, delivery.CodAmount)
+}
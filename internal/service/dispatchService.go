@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// Scorer rates how well a courier suits a delivery, higher is better.
+// The second result is false when the courier must not get the delivery at all
+type Scorer interface {
+	Score(candidate *model.DispatchCandidate, delivery *model.DeliveryGet, now time.Time) (float64, bool)
+}
+
+// WeightedScorer combines distance to pickup, current load and performance indicator
+type WeightedScorer struct {
+	DistanceWeight    float64
+	LoadWeight        float64
+	PerformanceWeight float64
+	// couriers farther than MaxDistanceKm from the pickup are not considered
+	MaxDistanceKm float64
+	// UnknownDistanceKm is assumed for couriers without a known position
+	UnknownDistanceKm float64
+}
+
+// DefaultScorer is used by the dispatcher unless another Scorer is configured
+var DefaultScorer = &WeightedScorer{
+	DistanceWeight:    0.5,
+	LoadWeight:        0.3,
+	PerformanceWeight: 0.2,
+	MaxDistanceKm:     15,
+	UnknownDistanceKm: 7.5,
+}
+
+// Score rules out couriers who are not active or not on shift
+func (s *WeightedScorer) Score(candidate *model.DispatchCandidate, delivery *model.DeliveryGet, now time.Time) (float64, bool) {
+	if candidate.Status != model.CourierStatusActive || !candidate.OnShift {
+		return 0, false
+	}
+	distance := s.UnknownDistanceKm
+	if candidate.Position != nil {
+		distance = haversineKm(*candidate.Position, delivery.Pickup.Point())
+	}
+	if distance > s.MaxDistanceKm {
+		return 0, false
+	}
+	performance := math.Max(0, math.Min(1, float64(candidate.Perfomance_indicator)/100))
+	score := s.DistanceWeight*(1-distance/s.MaxDistanceKm) +
+		s.LoadWeight/float64(1+candidate.ActiveDeliveries) +
+		s.PerformanceWeight*performance
+	return score, true
+}
+
+type Dispatcher struct {
+	rps    DispatchRepository
+	scorer Scorer
+	clock  Clock
+	// offerTimeout of zero assigns the best courier directly instead of offering
+	offerTimeout time.Duration
+	// once every candidate had an offer for a delivery, those whose last offer is older than reofferAfter
+	// get it again; zero never offers a delivery to the same courier twice
+	reofferAfter time.Duration
+}
+
+func NewDispatcher(rps DispatchRepository, scorer Scorer, clock Clock, offerTimeout time.Duration, reofferAfter time.Duration) *Dispatcher {
+	return &Dispatcher{rps: rps, scorer: scorer, clock: clock, offerTimeout: offerTimeout, reofferAfter: reofferAfter}
+}
+
+type DispatchRepository interface {
+	GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error)
+	GetDispatchCandidates(ctx context.Context, now time.Time, zoneId *uuid.UUID) ([]*model.DispatchCandidate, error)
+	GetUndispatchedDeliveryIDs(ctx context.Context) ([]uuid.UUID, error)
+	GetLastOfferTimes(ctx context.Context, deliveryId uuid.UUID) (map[uuid.UUID]time.Time, error)
+	AssignDeliveryWithinCapacity(ctx context.Context, deliveryId uuid.UUID, courierId uuid.UUID, now time.Time,
+		check func(*model.CourierCapacity, *model.DeliveryGet) error) error
+	InsertOffer(ctx context.Context, offer *model.DeliveryOffer) error
+	ExpirePendingOffers(ctx context.Context, now time.Time) ([]uuid.UUID, error)
+}
+
+// rankedCandidate is a candidate with its score
+type rankedCandidate struct {
+	candidate *model.DispatchCandidate
+	score     float64
+}
+
+// rank returns eligible candidates with room for the delivery best first, ties are broken by courier id to keep the order stable
+func (d *Dispatcher) rank(candidates []*model.DispatchCandidate, delivery *model.DeliveryGet, exclude map[uuid.UUID]bool) []rankedCandidate {
+	now := d.clock.Now()
+	var ranked []rankedCandidate
+	for _, candidate := range candidates {
+		if exclude[candidate.Id] {
+			continue
+		}
+		if checkCourierCapacity(&candidate.CourierCapacity, delivery) != nil {
+			continue
+		}
+		score, ok := d.scorer.Score(candidate, delivery, now)
+		if !ok {
+			continue
+		}
+		ranked = append(ranked, rankedCandidate{candidate: candidate, score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].candidate.Id.String() < ranked[j].candidate.Id.String()
+	})
+	return ranked
+}
+
+// Dispatch assigns the delivery to the best courier or offers it to them. Couriers who already had an offer
+// for this delivery are skipped until every candidate had one, then they are offered it again after reofferAfter
+func (d *Dispatcher) Dispatch(ctx context.Context, deliveryId uuid.UUID) (*model.DispatchResult, error) {
+	result := &model.DispatchResult{DeliveryId: deliveryId, Outcome: model.DispatchNoCandidate}
+	delivery, err := d.rps.GetDeliveryByID(ctx, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryByID: %w", err)
+	}
+	if delivery.CourierId != nil || delivery.DeliveryStatus != model.DeliveryStatusCreated {
+		return nil, fmt.Errorf("Dispatch: %w: delivery is already taken", model.ErrConflict)
+	}
+	candidates, err := d.rps.GetDispatchCandidates(ctx, d.clock.Now(), delivery.ZoneId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDispatchCandidates: %w", err)
+	}
+	offered, err := d.rps.GetLastOfferTimes(ctx, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetLastOfferTimes: %w", err)
+	}
+	now := d.clock.Now()
+	exclude := make(map[uuid.UUID]bool, len(offered))
+	for id := range offered {
+		exclude[id] = true
+	}
+	ranked := d.rank(candidates, delivery, exclude)
+	if len(ranked) == 0 && len(offered) > 0 && d.reofferAfter > 0 {
+		// every candidate had an offer, those who had it long enough ago get another one
+		for id, offeredAt := range offered {
+			exclude[id] = offeredAt.After(now.Add(-d.reofferAfter))
+		}
+		ranked = d.rank(candidates, delivery, exclude)
+	}
+	if len(ranked) == 0 {
+		return result, nil
+	}
+	best := ranked[0]
+	result.CourierId, result.Score = &best.candidate.Id, best.score
+
+	if d.offerTimeout == 0 {
+		err = d.rps.AssignDeliveryWithinCapacity(ctx, deliveryId, best.candidate.Id, now, checkCourierCapacity)
+		if err != nil {
+			return nil, fmt.Errorf("AssignDeliveryWithinCapacity: %w", err)
+		}
+		result.Outcome = model.DispatchAssigned
+		return result, nil
+	}
+
+	offer := &model.DeliveryOffer{
+		Id:         uuid.New(),
+		DeliveryId: deliveryId,
+		CourierId:  best.candidate.Id,
+		Status:     model.OfferStatusPending,
+		Score:      best.score,
+		OfferedAt:  now,
+		ExpiresAt:  now.Add(d.offerTimeout),
+	}
+	err = d.rps.InsertOffer(ctx, offer)
+	if err != nil {
+		return nil, fmt.Errorf("InsertOffer: %w", err)
+	}
+	result.Outcome, result.OfferId = model.DispatchOffered, &offer.Id
+	return result, nil
+}
+
+// RunOnce expires stale offers and dispatches every delivery that has no courier and no pending offer,
+// deliveries with expired offers fall through to the next candidate
+func (d *Dispatcher) RunOnce(ctx context.Context) error {
+	_, err := d.rps.ExpirePendingOffers(ctx, d.clock.Now())
+	if err != nil {
+		return fmt.Errorf("ExpirePendingOffers: %w", err)
+	}
+	ids, err := d.rps.GetUndispatchedDeliveryIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("GetUndispatchedDeliveryIDs: %w", err)
+	}
+	for _, id := range ids {
+		result, err := d.Dispatch(ctx, id)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{"deliveryId": id}).Errorf("Dispatch: %v", err)
+			continue
+		}
+		logrus.WithFields(logrus.Fields{"deliveryId": id, "outcome": result.Outcome}).Debug("Dispatch")
+	}
+	return nil
+}
+
+// Run calls RunOnce every interval until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := d.RunOnce(ctx)
+			if err != nil {
+				logrus.Errorf("RunOnce: %v", err)
+			}
+		}
+	}
+}
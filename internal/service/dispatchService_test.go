@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedClock always returns the same moment
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
+}
+
+// fakeDispatchRepository keeps dispatcher state in memory
+type fakeDispatchRepository struct {
+	delivery   *model.DeliveryGet
+	candidates []*model.DispatchCandidate
+	offers     []*model.DeliveryOffer
+}
+
+func (r *fakeDispatchRepository) GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error) {
+	return r.delivery, nil
+}
+
+func (r *fakeDispatchRepository) GetDispatchCandidates(ctx context.Context, now time.Time, zoneId *uuid.UUID) ([]*model.DispatchCandidate, error) {
+	return r.candidates, nil
+}
+
+func (r *fakeDispatchRepository) GetUndispatchedDeliveryIDs(ctx context.Context) ([]uuid.UUID, error) {
+	for _, offer := range r.offers {
+		if offer.Status == model.OfferStatusPending {
+			return nil, nil
+		}
+	}
+	if r.delivery.CourierId != nil {
+		return nil, nil
+	}
+	return []uuid.UUID{r.delivery.Id}, nil
+}
+
+func (r *fakeDispatchRepository) GetLastOfferTimes(ctx context.Context, deliveryId uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	times := make(map[uuid.UUID]time.Time)
+	for _, offer := range r.offers {
+		if offer.OfferedAt.After(times[offer.CourierId]) {
+			times[offer.CourierId] = offer.OfferedAt
+		}
+	}
+	return times, nil
+}
+
+func (r *fakeDispatchRepository) AssignDeliveryWithinCapacity(ctx context.Context, deliveryId uuid.UUID, courierId uuid.UUID, now time.Time,
+	check func(*model.CourierCapacity, *model.DeliveryGet) error) error {
+	if r.delivery.CourierId != nil {
+		return model.ErrConflict
+	}
+	for _, candidate := range r.candidates {
+		if candidate.Id == courierId {
+			if err := check(&candidate.CourierCapacity, r.delivery); err != nil {
+				return err
+			}
+		}
+	}
+	r.delivery.CourierId = &courierId
+	return nil
+}
+
+func (r *fakeDispatchRepository) InsertOffer(ctx context.Context, offer *model.DeliveryOffer) error {
+	for _, pending := range r.offers {
+		if pending.DeliveryId == offer.DeliveryId && pending.Status == model.OfferStatusPending {
+			return model.ErrConflict
+		}
+	}
+	r.offers = append(r.offers, offer)
+	return nil
+}
+
+func (r *fakeDispatchRepository) ExpirePendingOffers(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, offer := range r.offers {
+		if offer.Status == model.OfferStatusPending && !offer.ExpiresAt.After(now) {
+			offer.Status = model.OfferStatusExpired
+			ids = append(ids, offer.DeliveryId)
+		}
+	}
+	return ids, nil
+}
+
+func newTestCandidate(status string, load int, performance int, position *model.GeoPoint) *model.DispatchCandidate {
+	return &model.DispatchCandidate{
+		Courier:         model.Courier{Id: uuid.New(), Status: status, Perfomance_indicator: performance},
+		CourierCapacity: model.CourierCapacity{ActiveDeliveries: load, MaxActiveDeliveries: 5, OnShift: true},
+		Position:        position,
+	}
+}
+
+func newTestDispatchRepository(candidates ...*model.DispatchCandidate) *fakeDispatchRepository {
+	return &fakeDispatchRepository{
+		delivery: &model.DeliveryGet{
+			Id:             uuid.New(),
+			DeliveryStatus: model.DeliveryStatusCreated,
+			Pickup:         model.Location{Lat: 53.9, Lon: 27.56},
+		},
+		candidates: candidates,
+	}
+}
+
+// TestWeightedScorer checks eligibility rules and that closer, less loaded couriers win
+func TestWeightedScorer(t *testing.T) {
+	delivery := &model.DeliveryGet{Pickup: model.Location{Lat: 53.9, Lon: 27.56}}
+	now := time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)
+
+	near := newTestCandidate(model.CourierStatusActive, 0, 50, &model.GeoPoint{Lat: 53.905, Lon: 27.56})
+	far := newTestCandidate(model.CourierStatusActive, 0, 50, &model.GeoPoint{Lat: 53.98, Lon: 27.56})
+	busy := newTestCandidate(model.CourierStatusActive, 3, 50, &model.GeoPoint{Lat: 53.905, Lon: 27.56})
+	tooFar := newTestCandidate(model.CourierStatusActive, 0, 100, &model.GeoPoint{Lat: 55.75, Lon: 37.61})
+	offShift := newTestCandidate(model.CourierStatusOffShift, 0, 100, &model.GeoPoint{Lat: 53.9, Lon: 27.56})
+
+	nearScore, ok := DefaultScorer.Score(near, delivery, now)
+	require.True(t, ok)
+	farScore, ok := DefaultScorer.Score(far, delivery, now)
+	require.True(t, ok)
+	busyScore, ok := DefaultScorer.Score(busy, delivery, now)
+	require.True(t, ok)
+	require.Greater(t, nearScore, farScore)
+	require.Greater(t, nearScore, busyScore)
+
+	_, ok = DefaultScorer.Score(tooFar, delivery, now)
+	require.False(t, ok)
+	_, ok = DefaultScorer.Score(offShift, delivery, now)
+	require.False(t, ok)
+	near.OnShift = false
+	_, ok = DefaultScorer.Score(near, delivery, now)
+	require.False(t, ok)
+}
+
+// TestDispatchAssignsBestCourier checks direct assignment when offers are disabled
+func TestDispatchAssignsBestCourier(t *testing.T) {
+	best := newTestCandidate(model.CourierStatusActive, 0, 90, &model.GeoPoint{Lat: 53.901, Lon: 27.56})
+	other := newTestCandidate(model.CourierStatusActive, 2, 40, &model.GeoPoint{Lat: 53.95, Lon: 27.6})
+	rps := newTestDispatchRepository(other, best)
+	dispatcher := NewDispatcher(rps, DefaultScorer, &fixedClock{now: time.Now()}, 0, 0)
+
+	result, err := dispatcher.Dispatch(context.Background(), rps.delivery.Id)
+	require.NoError(t, err)
+	require.Equal(t, model.DispatchAssigned, result.Outcome)
+	require.Equal(t, best.Id, *rps.delivery.CourierId)
+}
+
+// TestDispatchOfferFallsBackAfterTimeout checks that an expired offer moves on to the next candidate
+func TestDispatchOfferFallsBackAfterTimeout(t *testing.T) {
+	first := newTestCandidate(model.CourierStatusActive, 0, 90, &model.GeoPoint{Lat: 53.901, Lon: 27.56})
+	second := newTestCandidate(model.CourierStatusActive, 1, 60, &model.GeoPoint{Lat: 53.92, Lon: 27.56})
+	rps := newTestDispatchRepository(first, second)
+	clock := &fixedClock{now: time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)}
+	dispatcher := NewDispatcher(rps, DefaultScorer, clock, time.Minute, 0)
+
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+	require.Len(t, rps.offers, 1)
+	require.Equal(t, first.Id, rps.offers[0].CourierId)
+	require.Equal(t, clock.now.Add(time.Minute), rps.offers[0].ExpiresAt)
+
+	// the offer is still pending, nothing changes
+	clock.now = clock.now.Add(30 * time.Second)
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+	require.Len(t, rps.offers, 1)
+
+	clock.now = clock.now.Add(30 * time.Second)
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+	require.Len(t, rps.offers, 2)
+	require.Equal(t, model.OfferStatusExpired, rps.offers[0].Status)
+	require.Equal(t, second.Id, rps.offers[1].CourierId)
+
+	// every candidate has been tried
+	clock.now = clock.now.Add(time.Minute)
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+	require.Len(t, rps.offers, 2)
+}
+
+// TestDispatchReoffersAfterCooldown checks that a delivery every candidate let expire is offered again
+// once the cooldown has passed, starting from the best courier
+func TestDispatchReoffersAfterCooldown(t *testing.T) {
+	first := newTestCandidate(model.CourierStatusActive, 0, 90, &model.GeoPoint{Lat: 53.901, Lon: 27.56})
+	second := newTestCandidate(model.CourierStatusActive, 1, 60, &model.GeoPoint{Lat: 53.92, Lon: 27.56})
+	rps := newTestDispatchRepository(first, second)
+	clock := &fixedClock{now: time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)}
+	dispatcher := NewDispatcher(rps, DefaultScorer, clock, time.Minute, 5*time.Minute)
+
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+	clock.now = clock.now.Add(time.Minute)
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+	require.Len(t, rps.offers, 2)
+	require.Equal(t, second.Id, rps.offers[1].CourierId)
+
+	// both offers expired but the first one is only two minutes old
+	clock.now = clock.now.Add(time.Minute)
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+	require.Len(t, rps.offers, 2)
+
+	clock.now = clock.now.Add(3 * time.Minute)
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+	require.Len(t, rps.offers, 3)
+	require.Equal(t, first.Id, rps.offers[2].CourierId)
+
+	// the first courier lets it expire again, by now the second one is past the cooldown too
+	clock.now = clock.now.Add(time.Minute)
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+	require.Len(t, rps.offers, 4)
+	require.Equal(t, second.Id, rps.offers[3].CourierId)
+}
+
+// TestDispatchWithPendingOffer checks that a delivery is not offered twice at the same time
+func TestDispatchWithPendingOffer(t *testing.T) {
+	rps := newTestDispatchRepository(newTestCandidate(model.CourierStatusActive, 0, 90, &model.GeoPoint{Lat: 53.901, Lon: 27.56}),
+		newTestCandidate(model.CourierStatusActive, 1, 60, &model.GeoPoint{Lat: 53.92, Lon: 27.56}))
+	dispatcher := NewDispatcher(rps, DefaultScorer, &fixedClock{now: time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)}, time.Minute, 0)
+
+	_, err := dispatcher.Dispatch(context.Background(), rps.delivery.Id)
+	require.NoError(t, err)
+	_, err = dispatcher.Dispatch(context.Background(), rps.delivery.Id)
+	require.ErrorIs(t, err, model.ErrConflict)
+	require.Len(t, rps.offers, 1)
+}
+
+// TestDispatchSkipsCouriersWithoutCapacity checks that full, overweight and off-shift couriers are not picked
+func TestDispatchSkipsCouriersWithoutCapacity(t *testing.T) {
+	maxWeight := 10.0
+	full := newTestCandidate(model.CourierStatusActive, 5, 100, &model.GeoPoint{Lat: 53.9, Lon: 27.56})
+	heavy := newTestCandidate(model.CourierStatusActive, 0, 100, &model.GeoPoint{Lat: 53.9, Lon: 27.56})
+	heavy.ActiveWeightKg, heavy.MaxWeightKg = 8, &maxWeight
+	resting := newTestCandidate(model.CourierStatusActive, 0, 100, &model.GeoPoint{Lat: 53.9, Lon: 27.56})
+	resting.OnShift = false
+	free := newTestCandidate(model.CourierStatusActive, 1, 10, &model.GeoPoint{Lat: 53.95, Lon: 27.6})
+	rps := newTestDispatchRepository(full, heavy, resting, free)
+	rps.delivery.WeightKg = 3
+	dispatcher := NewDispatcher(rps, DefaultScorer, &fixedClock{now: time.Now()}, 0, 0)
+
+	result, err := dispatcher.Dispatch(context.Background(), rps.delivery.Id)
+	require.NoError(t, err)
+	require.Equal(t, model.DispatchAssigned, result.Outcome)
+	require.Equal(t, free.Id, *rps.delivery.CourierId)
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// maxEarningComment limits the reason given for an adjustment or penalty
+const maxEarningComment = 500
+
+// PayoutRules is what a courier earns per delivery: a flat amount, a distance based amount
+// from pickup to drop-off and a bonus when the delivery is completed within its window
+type PayoutRules struct {
+	Flat        float64
+	PerKm       float64
+	OnTimeBonus float64
+}
+
+// NewPayoutRules reads the rules from a map with the keys flat, per_km and on_time_bonus
+func NewPayoutRules(rules map[string]float64) (PayoutRules, error) {
+	var payout PayoutRules
+	for key, value := range rules {
+		if value < 0 {
+			return PayoutRules{}, fmt.Errorf("negative payout for %s", key)
+		}
+		switch key {
+		case "flat":
+			payout.Flat = value
+		case "per_km":
+			payout.PerKm = value
+		case "on_time_bonus":
+			payout.OnTimeBonus = value
+		default:
+			return PayoutRules{}, fmt.Errorf("unknown payout rule: %s", key)
+		}
+	}
+	return payout, nil
+}
+
+type EarningService struct {
+	rps      EarningRepository
+	rules    PayoutRules
+	distance Distancer
+	clock    Clock
+}
+
+func NewEarningService(rps EarningRepository, rules PayoutRules, distance Distancer, clock Clock) *EarningService {
+	return &EarningService{rps: rps, rules: rules, distance: distance, clock: clock}
+}
+
+type EarningRepository interface {
+	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
+	GetUnaccruedDeliveries(ctx context.Context) ([]*model.DeliveryGet, error)
+	GetUnaccruedLegs(ctx context.Context) ([]*model.DeliveredLeg, error)
+	InsertEarnings(ctx context.Context, earnings []*model.Earning) error
+	GetFirstUnstatedEarning(ctx context.Context, period string) (*time.Time, error)
+	GenerateStatements(ctx context.Context, period string, start time.Time, end time.Time, generatedAt time.Time) (int64, error)
+	GetStatements(ctx context.Context, courierId *uuid.UUID, filter *model.StatementFilter) ([]*model.Statement, error)
+	GetStatement(ctx context.Context, statementId uuid.UUID) (*model.Statement, error)
+	GetEarnings(ctx context.Context, courierId uuid.UUID, from time.Time, to time.Time) ([]*model.Earning, error)
+}
+
+// deliveryEarnings applies the payout rules to a delivered delivery, the on-time bonus is a separate entry
+func deliveryEarnings(rules PayoutRules, distance Distancer, delivery *model.DeliveryGet, now time.Time) []*model.Earning {
+	if delivery.CourierId == nil {
+		return nil
+	}
+	deliveryId := delivery.Id
+	km := distance.DistanceKm(delivery.Pickup.Point(), delivery.Dropoff.Point())
+	earnings := []*model.Earning{{Id: uuid.New(), CourierId: *delivery.CourierId, DeliveryId: &deliveryId, Kind: model.EarningDelivery,
+		Amount: roundCents(rules.Flat + km*rules.PerKm), CreatedAt: now}}
+	if rules.OnTimeBonus > 0 && delivery.DeliveredAt != nil && delivery.WindowEnd != nil && !delivery.DeliveredAt.After(*delivery.WindowEnd) {
+		earnings = append(earnings, &model.Earning{Id: uuid.New(), CourierId: *delivery.CourierId, DeliveryId: &deliveryId,
+			Kind: model.EarningOnTimeBonus, Amount: roundCents(rules.OnTimeBonus), CreatedAt: now})
+	}
+	return earnings
+}
+
+// legEarnings pays the courier of a delivered leg like a delivery over the leg's distance,
+// the on-time bonus goes to the courier of the final leg
+func legEarnings(rules PayoutRules, distance Distancer, leg *model.DeliveredLeg, now time.Time) []*model.Earning {
+	deliveryId, legId := leg.DeliveryId, leg.Id
+	km := distance.DistanceKm(leg.From, leg.To)
+	earnings := []*model.Earning{{Id: uuid.New(), CourierId: leg.CourierId, DeliveryId: &deliveryId, LegId: &legId, Kind: model.EarningDelivery,
+		Amount: roundCents(rules.Flat + km*rules.PerKm), CreatedAt: now}}
+	if rules.OnTimeBonus > 0 && leg.Final && leg.WindowEnd != nil && !leg.DeliveredAt.After(*leg.WindowEnd) {
+		earnings = append(earnings, &model.Earning{Id: uuid.New(), CourierId: leg.CourierId, DeliveryId: &deliveryId, LegId: &legId,
+			Kind: model.EarningOnTimeBonus, Amount: roundCents(rules.OnTimeBonus), CreatedAt: now})
+	}
+	return earnings
+}
+
+// periodBounds returns the UTC week (starting on Monday) or month that contains t
+func periodBounds(period string, t time.Time) (time.Time, time.Time, error) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case model.StatementWeek:
+		start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7), nil
+	case model.StatementMonth:
+		start := day.AddDate(0, 0, 1-day.Day())
+		return start, start.AddDate(0, 1, 0), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("%w: period must be week or month", model.ErrValidation)
+}
+
+// Accrue books the earnings of delivered deliveries and delivered legs of multi-leg deliveries that have none yet.
+// Earnings are booked at accrual time, so a closed statement period never changes
+func (srv *EarningService) Accrue(ctx context.Context) error {
+	deliveries, err := srv.rps.GetUnaccruedDeliveries(ctx)
+	if err != nil {
+		return fmt.Errorf("GetUnaccruedDeliveries: %w", err)
+	}
+	now := srv.clock.Now().UTC()
+	var earnings []*model.Earning
+	for _, delivery := range deliveries {
+		earnings = append(earnings, deliveryEarnings(srv.rules, srv.distance, delivery, now)...)
+	}
+	legs, err := srv.rps.GetUnaccruedLegs(ctx)
+	if err != nil {
+		return fmt.Errorf("GetUnaccruedLegs: %w", err)
+	}
+	for _, leg := range legs {
+		earnings = append(earnings, legEarnings(srv.rules, srv.distance, leg, now)...)
+	}
+	if len(earnings) == 0 {
+		return nil
+	}
+	err = srv.rps.InsertEarnings(ctx, earnings)
+	if err != nil {
+		return fmt.Errorf("InsertEarnings: %w", err)
+	}
+	return nil
+}
+
+// GenerateStatements creates the statements of every closed week and month since the oldest earning
+// not covered by a statement yet, so periods missed while the service was down are caught up. Existing statements are kept
+func (srv *EarningService) GenerateStatements(ctx context.Context) error {
+	now := srv.clock.Now().UTC()
+	for _, period := range []string{model.StatementWeek, model.StatementMonth} {
+		current, _, err := periodBounds(period, now)
+		if err != nil {
+			return err
+		}
+		first, err := srv.rps.GetFirstUnstatedEarning(ctx, period)
+		if err != nil {
+			return fmt.Errorf("GetFirstUnstatedEarning: %w", err)
+		}
+		if first == nil {
+			continue
+		}
+		start, end, err := periodBounds(period, *first)
+		if err != nil {
+			return err
+		}
+		for start.Before(current) {
+			generated, err := srv.rps.GenerateStatements(ctx, period, start, end, now)
+			if err != nil {
+				return fmt.Errorf("GenerateStatements: %w", err)
+			}
+			logrus.WithFields(logrus.Fields{"period": period, "start": start, "generated": generated}).Debug("GenerateStatements")
+			start, end, err = periodBounds(period, end)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Run accrues earnings and generates due statements right away and then every interval until ctx is cancelled
+func (srv *EarningService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := srv.Accrue(ctx)
+		if err != nil {
+			logrus.Errorf("Accrue: %v", err)
+		}
+		err = srv.GenerateStatements(ctx)
+		if err != nil {
+			logrus.Errorf("GenerateStatements: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AdjustEarnings books a manual adjustment or penalty for the courier
+func (srv *EarningService) AdjustEarnings(ctx context.Context, adminId uuid.UUID, adjustment *model.EarningAdjustmentRequest) (*model.Earning, error) {
+	adjustment.Comment = strings.TrimSpace(adjustment.Comment)
+	if adjustment.Comment == "" || len([]rune(adjustment.Comment)) > maxEarningComment {
+		return nil, fmt.Errorf("%w: comment must be 1 to %d characters", model.ErrValidation, maxEarningComment)
+	}
+	amount := roundCents(adjustment.Amount)
+	switch adjustment.Kind {
+	case model.EarningAdjustment:
+		if amount == 0 {
+			return nil, fmt.Errorf("%w: amount must not be zero", model.ErrValidation)
+		}
+	case model.EarningPenalty:
+		if amount <= 0 {
+			return nil, fmt.Errorf("%w: penalty amount must be positive", model.ErrValidation)
+		}
+		amount = -amount
+	default:
+		return nil, fmt.Errorf("%w: kind must be adjustment or penalty", model.ErrValidation)
+	}
+	courier, err := srv.rps.GetCourierByUserID(ctx, adjustment.CourierUserId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	earning := &model.Earning{Id: uuid.New(), CourierId: courier.Id, Kind: adjustment.Kind, Amount: amount, Comment: adjustment.Comment,
+		CreatedBy: &adminId, CreatedAt: srv.clock.Now().UTC()}
+	err = srv.rps.InsertEarnings(ctx, []*model.Earning{earning})
+	if err != nil {
+		return nil, fmt.Errorf("InsertEarnings: %w", err)
+	}
+	return earning, nil
+}
+
+// validateStatementFilter defaults the period to weeks
+func validateStatementFilter(filter *model.StatementFilter) error {
+	if filter.Period == "" {
+		filter.Period = model.StatementWeek
+	}
+	if filter.Period != model.StatementWeek && filter.Period != model.StatementMonth {
+		return fmt.Errorf("%w: period must be week or month", model.ErrValidation)
+	}
+	return nil
+}
+
+// GetMyStatements returns the statements of the courier with the given user id, newest first
+func (srv *EarningService) GetMyStatements(ctx context.Context, userId uuid.UUID, filter *model.StatementFilter) ([]*model.Statement, error) {
+	err := validateStatementFilter(filter)
+	if err != nil {
+		return nil, fmt.Errorf("validateStatementFilter: %w", err)
+	}
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	statements, err := srv.rps.GetStatements(ctx, &courier.Id, filter)
+	if err != nil {
+		return nil, fmt.Errorf("GetStatements: %w", err)
+	}
+	return statements, nil
+}
+
+// GetMyStatement returns a statement of the courier with its entries,
+// statements of other couriers are reported as not found
+func (srv *EarningService) GetMyStatement(ctx context.Context, userId uuid.UUID, statementId uuid.UUID) (*model.Statement, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	statement, err := srv.rps.GetStatement(ctx, statementId)
+	if err != nil {
+		return nil, fmt.Errorf("GetStatement: %w", err)
+	}
+	if statement.CourierId != courier.Id {
+		return nil, fmt.Errorf("GetMyStatement: %w", model.ErrNotFound)
+	}
+	statement.Entries, err = srv.rps.GetEarnings(ctx, courier.Id, statement.PeriodStart, statement.PeriodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("GetEarnings: %w", err)
+	}
+	return statement, nil
+}
+
+// GetStatements returns the statements of all couriers for admins
+func (srv *EarningService) GetStatements(ctx context.Context, filter *model.StatementFilter) ([]*model.Statement, error) {
+	err := validateStatementFilter(filter)
+	if err != nil {
+		return nil, fmt.Errorf("validateStatementFilter: %w", err)
+	}
+	statements, err := srv.rps.GetStatements(ctx, nil, filter)
+	if err != nil {
+		return nil, fmt.Errorf("GetStatements: %w", err)
+	}
+	return statements, nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedDistance returns the same distance for any two points
+type fixedDistance float64
+
+func (d fixedDistance) DistanceKm(a, b model.GeoPoint) float64 {
+	return float64(d)
+}
+
+// TestNewPayoutRules checks that unknown rules and negative amounts are rejected
+func TestNewPayoutRules(t *testing.T) {
+	rules, err := NewPayoutRules(map[string]float64{"flat": 2, "per_km": 0.4, "on_time_bonus": 0.5})
+	require.NoError(t, err)
+	require.Equal(t, PayoutRules{Flat: 2, PerKm: 0.4, OnTimeBonus: 0.5}, rules)
+	_, err = NewPayoutRules(map[string]float64{"per_kg": 1})
+	require.Error(t, err)
+	_, err = NewPayoutRules(map[string]float64{"flat": -1})
+	require.Error(t, err)
+}
+
+// TestDeliveryEarnings checks the distance based payout and the on-time bonus
+func TestDeliveryEarnings(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rules := PayoutRules{Flat: 2, PerKm: 0.4, OnTimeBonus: 0.5}
+	courierId := uuid.New()
+	windowEnd, onTime, late := now.Add(-time.Hour), now.Add(-2*time.Hour), now.Add(-30*time.Minute)
+	delivery := &model.DeliveryGet{Id: uuid.New(), CourierId: &courierId, WindowEnd: &windowEnd, DeliveredAt: &onTime}
+
+	earnings := deliveryEarnings(rules, fixedDistance(12.5), delivery, now)
+	require.Len(t, earnings, 2)
+	require.Equal(t, model.EarningDelivery, earnings[0].Kind)
+	require.Equal(t, 7.0, earnings[0].Amount)
+	require.Equal(t, courierId, earnings[0].CourierId)
+	require.Equal(t, delivery.Id, *earnings[0].DeliveryId)
+	require.Equal(t, model.EarningOnTimeBonus, earnings[1].Kind)
+	require.Equal(t, 0.5, earnings[1].Amount)
+
+	delivery.DeliveredAt = &late
+	earnings = deliveryEarnings(rules, fixedDistance(12.5), delivery, now)
+	require.Len(t, earnings, 1)
+
+	delivery.CourierId = nil
+	require.Empty(t, deliveryEarnings(rules, fixedDistance(12.5), delivery, now))
+}
+
+// TestLegEarnings checks that each leg pays its own courier and only the final leg earns the on-time bonus
+func TestLegEarnings(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rules := PayoutRules{Flat: 2, PerKm: 0.4, OnTimeBonus: 0.5}
+	windowEnd := now.Add(-time.Hour)
+	leg := &model.DeliveredLeg{Id: uuid.New(), DeliveryId: uuid.New(), CourierId: uuid.New(), DeliveredAt: now.Add(-2 * time.Hour), WindowEnd: &windowEnd}
+
+	earnings := legEarnings(rules, fixedDistance(5), leg, now)
+	require.Len(t, earnings, 1)
+	require.Equal(t, 4.0, earnings[0].Amount)
+	require.Equal(t, leg.CourierId, earnings[0].CourierId)
+	require.Equal(t, leg.Id, *earnings[0].LegId)
+	require.Equal(t, leg.DeliveryId, *earnings[0].DeliveryId)
+
+	leg.Final = true
+	earnings = legEarnings(rules, fixedDistance(5), leg, now)
+	require.Len(t, earnings, 2)
+	require.Equal(t, model.EarningOnTimeBonus, earnings[1].Kind)
+}
+
+// TestPeriodBounds checks that weeks start on Monday and months on the first day
+func TestPeriodBounds(t *testing.T) {
+	sunday := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+
+	start, end, err := periodBounds(model.StatementWeek, sunday)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC), start)
+	require.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), end)
+
+	start, end, err = periodBounds(model.StatementMonth, sunday)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), start)
+	require.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), end)
+
+	_, _, err = periodBounds("year", sunday)
+	require.ErrorIs(t, err, model.ErrValidation)
+}
+
+type fakeEarningRepository struct {
+	EarningRepository
+	courier  *model.Courier
+	earnings []*model.Earning
+	periods  map[string][]time.Time
+	first    *time.Time
+}
+
+func (r *fakeEarningRepository) GetCourierByUserID(ctx context.Context, userId uuid.UUID) (*model.Courier, error) {
+	return r.courier, nil
+}
+
+func (r *fakeEarningRepository) InsertEarnings(ctx context.Context, earnings []*model.Earning) error {
+	r.earnings = append(r.earnings, earnings...)
+	return nil
+}
+
+func (r *fakeEarningRepository) GetFirstUnstatedEarning(ctx context.Context, period string) (*time.Time, error) {
+	return r.first, nil
+}
+
+func (r *fakeEarningRepository) GenerateStatements(ctx context.Context, period string, start time.Time, end time.Time, generatedAt time.Time) (int64, error) {
+	r.periods[period] = append(r.periods[period], start)
+	return 0, nil
+}
+
+// TestAdjustEarnings checks that penalties are booked as negative amounts and need a reason
+func TestAdjustEarnings(t *testing.T) {
+	repo := &fakeEarningRepository{courier: &model.Courier{Id: uuid.New()}}
+	srv := NewEarningService(repo, PayoutRules{}, fixedDistance(0), &fixedClock{time.Now()})
+	adminId := uuid.New()
+
+	earning, err := srv.AdjustEarnings(context.Background(), adminId, &model.EarningAdjustmentRequest{Kind: model.EarningPenalty, Amount: 3, Comment: "lost parcel"})
+	require.NoError(t, err)
+	require.Equal(t, -3.0, earning.Amount)
+	require.Equal(t, adminId, *earning.CreatedBy)
+	require.Len(t, repo.earnings, 1)
+
+	_, err = srv.AdjustEarnings(context.Background(), adminId, &model.EarningAdjustmentRequest{Kind: model.EarningPenalty, Amount: -3, Comment: "x"})
+	require.ErrorIs(t, err, model.ErrValidation)
+	_, err = srv.AdjustEarnings(context.Background(), adminId, &model.EarningAdjustmentRequest{Kind: model.EarningAdjustment, Amount: 1})
+	require.ErrorIs(t, err, model.ErrValidation)
+	_, err = srv.AdjustEarnings(context.Background(), adminId, &model.EarningAdjustmentRequest{Kind: model.EarningDelivery, Amount: 1, Comment: "x"})
+	require.ErrorIs(t, err, model.ErrValidation)
+}
+
+// TestGenerateStatements checks that statements are generated for every closed week and month
+// since the oldest earning without a statement
+func TestGenerateStatements(t *testing.T) {
+	first := time.Date(2026, 1, 28, 15, 0, 0, 0, time.UTC)
+	repo := &fakeEarningRepository{periods: map[string][]time.Time{}, first: &first}
+	srv := NewEarningService(repo, PayoutRules{}, fixedDistance(0), &fixedClock{time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)})
+
+	require.NoError(t, srv.GenerateStatements(context.Background()))
+	require.Equal(t, []time.Time{time.Date(2026, 1, 26, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC)}, repo.periods[model.StatementWeek])
+	require.Equal(t, []time.Time{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		repo.periods[model.StatementMonth])
+
+	repo.periods, repo.first = map[string][]time.Time{}, nil
+	require.NoError(t, srv.GenerateStatements(context.Background()))
+	require.Empty(t, repo.periods)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+const (
+	// zone speeds are averaged over zoneSpeedWindow from zones with at least zoneMinSamples
+	// deliveries and reloaded every zoneSpeedRefresh
+	zoneSpeedWindow  = 30 * 24 * time.Hour
+	zoneMinSamples   = 5
+	zoneSpeedRefresh = time.Hour
+)
+
+// vehicleTypes are the vehicle types a courier may have
+var vehicleTypes = map[string]bool{
+	model.VehicleFoot:    true,
+	model.VehicleBicycle: true,
+	model.VehicleScooter: true,
+	model.VehicleCar:     true,
+}
+
+type EtaService struct {
+	rps     EtaRepository
+	planner *RoutePlanner
+	clock   Clock
+
+	mu           sync.Mutex
+	zoneSpeeds   map[string]float64
+	zoneLoadedAt time.Time
+}
+
+func NewEtaService(rps EtaRepository, planner *RoutePlanner, clock Clock) *EtaService {
+	return &EtaService{rps: rps, planner: planner, clock: clock}
+}
+
+type EtaRepository interface {
+	GetCourierRoute(ctx context.Context, courierId uuid.UUID) (*model.CourierRoute, error)
+	GetZoneSpeeds(ctx context.Context, since time.Time, minSamples int) ([]*model.ZoneSpeed, error)
+	UpdateDeliveryETAs(ctx context.Context, etas map[uuid.UUID]time.Time) error
+}
+
+// zoneKey identifies a zone speed
+func zoneKey(zone string, vehicleType string) string {
+	return zone + "|" + vehicleType
+}
+
+// speedFor prefers the historical speed of the zone over the vehicle profile
+func (srv *EtaService) speedFor(zoneSpeeds map[string]float64, zone string, vehicleType string) float64 {
+	if speed, ok := zoneSpeeds[zoneKey(zone, vehicleType)]; ok && speed > 0 {
+		return speed
+	}
+	return srv.planner.speedFor(vehicleType)
+}
+
+// estimate walks the planned stops from the courier position and returns the drop-off time of every delivery,
+// legs are timed with zone history where there is enough of it
+func (srv *EtaService) estimate(route *model.CourierRoute, zoneSpeeds map[string]float64, now time.Time) map[uuid.UUID]time.Time {
+	plan := srv.planner.Plan(route, now)
+	etas := make(map[uuid.UUID]time.Time, len(route.Deliveries))
+	at, position := now, route.Position
+	for _, stop := range plan.Stops {
+		point := stop.Location.Point()
+		speed := srv.speedFor(zoneSpeeds, stop.Location.City, route.VehicleType)
+		// without a position the courier is assumed to be at the first stop
+		if position != nil && speed > 0 {
+			hours := srv.planner.distance.DistanceKm(*position, point) / speed
+			at = at.Add(time.Duration(hours * float64(time.Hour)))
+		}
+		if stop.WindowStart != nil && at.Before(*stop.WindowStart) {
+			at = *stop.WindowStart
+		}
+		at = at.Add(stopDuration)
+		position = &point
+		if stop.Kind == model.StopDropoff {
+			etas[stop.DeliveryId] = at
+		}
+	}
+	return etas
+}
+
+// loadZoneSpeeds returns cached zone speeds, reloading them when they are stale
+func (srv *EtaService) loadZoneSpeeds(ctx context.Context, now time.Time) (map[string]float64, error) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.zoneSpeeds != nil && now.Sub(srv.zoneLoadedAt) < zoneSpeedRefresh {
+		return srv.zoneSpeeds, nil
+	}
+	speeds, err := srv.rps.GetZoneSpeeds(ctx, now.Add(-zoneSpeedWindow), zoneMinSamples)
+	if err != nil {
+		return nil, fmt.Errorf("GetZoneSpeeds: %w", err)
+	}
+	srv.zoneSpeeds = make(map[string]float64, len(speeds))
+	for _, speed := range speeds {
+		srv.zoneSpeeds[zoneKey(speed.Zone, speed.VehicleType)] = speed.SpeedKmh
+	}
+	srv.zoneLoadedAt = now
+	return srv.zoneSpeeds, nil
+}
+
+// RecomputeCourier estimates and stores ETAs of every active delivery of the courier
+func (srv *EtaService) RecomputeCourier(ctx context.Context, courierId uuid.UUID) error {
+	now := srv.clock.Now()
+	route, err := srv.rps.GetCourierRoute(ctx, courierId)
+	if err != nil {
+		return fmt.Errorf("GetCourierRoute: %w", err)
+	}
+	if len(route.Deliveries) == 0 {
+		return nil
+	}
+	zoneSpeeds, err := srv.loadZoneSpeeds(ctx, now)
+	if err != nil {
+		return fmt.Errorf("loadZoneSpeeds: %w", err)
+	}
+	err = srv.rps.UpdateDeliveryETAs(ctx, srv.estimate(route, zoneSpeeds, now))
+	if err != nil {
+		return fmt.Errorf("UpdateDeliveryETAs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEstimate checks stop order, vehicle speed and that zone history overrides the profile
+func TestEstimate(t *testing.T) {
+	now := time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)
+	early, late := now.Add(time.Hour), now.Add(3*time.Hour)
+	carried := &model.DeliveryGet{
+		Id:         uuid.New(),
+		PickedUpAt: &now,
+		WindowEnd:  &early,
+		Dropoff:    model.Location{City: "Minsk", Lat: 53.9, Lon: 27.6},
+	}
+	queued := &model.DeliveryGet{
+		Id:        uuid.New(),
+		WindowEnd: &late,
+		Pickup:    model.Location{City: "Minsk", Lat: 53.9, Lon: 27.7},
+		Dropoff:   model.Location{City: "Minsk", Lat: 53.95, Lon: 27.7},
+	}
+	route := &model.CourierRoute{
+		VehicleType: model.VehicleBicycle,
+		Position:    &model.GeoPoint{Lat: 53.9, Lon: 27.5},
+		Deliveries:  []*model.DeliveryGet{queued, carried},
+	}
+	planner := NewRoutePlanner(DefaultDistance, map[string]float64{model.VehicleBicycle: 15, model.VehicleCar: 30})
+	srv := NewEtaService(nil, planner, &fixedClock{now: now})
+
+	etas := srv.estimate(route, nil, now)
+	require.Len(t, etas, 2)
+	// the carried delivery is dropped off first although it was listed second
+	require.True(t, etas[carried.Id].Before(etas[queued.Id]))
+	first := etas[carried.Id].Sub(now)
+	legKm := DefaultDistance.DistanceKm(*route.Position, carried.Dropoff.Point())
+	require.InDelta(t, (time.Duration(legKm/15*float64(time.Hour)) + stopDuration).Seconds(), first.Seconds(), 1)
+
+	route.VehicleType = model.VehicleCar
+	require.True(t, srv.estimate(route, nil, now)[carried.Id].Before(etas[carried.Id]))
+
+	slowZone := map[string]float64{zoneKey("Minsk", model.VehicleBicycle): 5}
+	route.VehicleType = model.VehicleBicycle
+	require.True(t, srv.estimate(route, slowZone, now)[carried.Id].After(etas[carried.Id]))
+}
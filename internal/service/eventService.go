@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// subscriberBuffer is how many events may wait for a slow subscriber before new ones are dropped
+	subscriberBuffer = 64
+	// listenRetryDelay is the pause before listening again after the connection failed
+	listenRetryDelay = 2 * time.Second
+)
+
+// EventHub fans delivery events received from Postgres out to the subscribers of this instance
+type EventHub struct {
+	rps         EventRepository
+	clock       Clock
+	signingKey  string
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+func NewEventHub(rps EventRepository, clock Clock, signingKey string) *EventHub {
+	return &EventHub{rps: rps, clock: clock, signingKey: signingKey, subscribers: make(map[*subscriber]struct{})}
+}
+
+type EventRepository interface {
+	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
+	ListenDeliveryEvents(ctx context.Context, handle func(payload string)) error
+}
+
+type subscriber struct {
+	all       bool
+	clientId  *uuid.UUID
+	courierId *uuid.UUID
+	events    chan *model.DeliveryEvent
+}
+
+// allows reports whether the subscriber may see the event, couriers also see deliveries taken away from them
+func (s *subscriber) allows(event *model.DeliveryEvent) bool {
+	switch {
+	case s.all:
+		return true
+	case s.clientId != nil:
+		return event.ClientId != nil && *event.ClientId == *s.clientId
+	case s.courierId != nil:
+		return (event.CourierId != nil && *event.CourierId == *s.courierId) ||
+			(event.PreviousCourierId != nil && *event.PreviousCourierId == *s.courierId)
+	}
+	return false
+}
+
+// Subscribe returns a channel of events allowed by the filter and a function that ends the subscription
+func (h *EventHub) Subscribe(ctx context.Context, filter *model.EventFilter) (<-chan *model.DeliveryEvent, func(), error) {
+	s := &subscriber{all: filter.All, clientId: filter.ClientId, events: make(chan *model.DeliveryEvent, subscriberBuffer)}
+	if filter.CourierUserId != nil {
+		courier, err := h.rps.GetCourierByUserID(ctx, *filter.CourierUserId)
+		if err != nil {
+			return nil, nil, fmt.Errorf("GetCourierByUserID: %w", err)
+		}
+		s.courierId = &courier.Id
+	}
+	h.mu.Lock()
+	h.subscribers[s] = struct{}{}
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		delete(h.subscribers, s)
+		h.mu.Unlock()
+	}
+	return s.events, unsubscribe, nil
+}
+
+// IssueTicket returns a short-lived ticket that opens an event stream of the user in place of their access token
+func (h *EventHub) IssueTicket(_ context.Context, userId uuid.UUID, role string) (*model.EventTicket, error) {
+	ticket, err := GenerateEventTicket(h.signingKey, role, userId, h.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("GenerateEventTicket: %w", err)
+	}
+	return ticket, nil
+}
+
+// publish decodes a notification and hands it to every subscriber allowed to see it without blocking
+func (h *EventHub) publish(payload string) {
+	event := &model.DeliveryEvent{}
+	err := json.Unmarshal([]byte(payload), event)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"payload": payload}).Errorf("Unmarshal: %v", err)
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.subscribers {
+		if !s.allows(event) {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			logrus.WithFields(logrus.Fields{"deliveryId": event.DeliveryId}).Warn("publish: subscriber is too slow, event dropped")
+		}
+	}
+}
+
+// Run listens for delivery events until ctx is cancelled, reconnecting when the connection fails
+func (h *EventHub) Run(ctx context.Context) {
+	for {
+		err := h.rps.ListenDeliveryEvents(ctx, h.publish)
+		if ctx.Err() != nil {
+			return
+		}
+		logrus.Errorf("ListenDeliveryEvents: %v", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEventHubFiltersSubscribers checks that clients only receive events of their own deliveries
+func TestEventHubFiltersSubscribers(t *testing.T) {
+	hub := NewEventHub(nil, SystemClock{}, "test_key")
+	clientId, otherClientId := uuid.New(), uuid.New()
+
+	own, unsubscribe, err := hub.Subscribe(context.Background(), &model.EventFilter{ClientId: &clientId})
+	require.NoError(t, err)
+	defer unsubscribe()
+	all, unsubscribeAll, err := hub.Subscribe(context.Background(), &model.EventFilter{All: true})
+	require.NoError(t, err)
+	defer unsubscribeAll()
+
+	hub.publish(`{"type":"status","delivery_id":"` + uuid.NewString() + `","client_id":"` + otherClientId.String() + `","status":"picked_up"}`)
+	hub.publish(`{"type":"status","delivery_id":"` + uuid.NewString() + `","client_id":"` + clientId.String() + `","status":"delivered"}`)
+
+	require.Len(t, all, 2)
+	require.Len(t, own, 1)
+	event := <-own
+	require.Equal(t, model.DeliveryStatusDelivered, event.Status)
+}
+
+type fakeEventRepository struct {
+	courier *model.Courier
+}
+
+func (r *fakeEventRepository) GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error) {
+	return r.courier, nil
+}
+
+func (r *fakeEventRepository) ListenDeliveryEvents(ctx context.Context, handle func(payload string)) error {
+	return nil
+}
+
+// TestEventHubNotifiesPreviousCourier checks that a courier learns about a delivery taken away from them
+func TestEventHubNotifiesPreviousCourier(t *testing.T) {
+	courier := &model.Courier{Id: uuid.New(), UserId: uuid.New()}
+	hub := NewEventHub(&fakeEventRepository{courier: courier}, SystemClock{}, "test_key")
+	events, unsubscribe, err := hub.Subscribe(context.Background(), &model.EventFilter{CourierUserId: &courier.UserId})
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	hub.publish(`{"type":"status","delivery_id":"` + uuid.NewString() + `","courier_id":null,"previous_courier_id":"` + courier.Id.String() + `","status":"cancelled"}`)
+	hub.publish(`{"type":"status","delivery_id":"` + uuid.NewString() + `","courier_id":"` + uuid.NewString() + `","status":"picked_up"}`)
+
+	require.Len(t, events, 1)
+	require.Equal(t, model.DeliveryStatusCancelled, (<-events).Status)
+}
+
+// TestIssueTicket checks that a ticket carries the user and role for a minute and only opens event streams
+func TestIssueTicket(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	userId := uuid.New()
+	ticket, err := NewEventHub(nil, &fixedClock{now}, "test_key").IssueTicket(context.Background(), userId, "Client")
+	require.NoError(t, err)
+	require.Equal(t, now.Add(time.Minute), ticket.ExpiresAt)
+
+	claims := &tokenClaims{}
+	_, err = jwt.ParseWithClaims(ticket.Ticket, claims, func(*jwt.Token) (interface{}, error) { return []byte("test_key"), nil })
+	require.NoError(t, err)
+	require.Equal(t, "Client", claims.Role)
+	require.Equal(t, userId.String(), claims.Id)
+	require.Equal(t, model.EventTicketAudience, claims.Audience)
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"math"
+
+	"github.com/liza/labwork_45/internal/model"
+)
+
+const earthRadiusKm = 6371.0
+
+// haversineKm returns the great-circle distance between two points in kilometers
+func haversineKm(a, b model.GeoPoint) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
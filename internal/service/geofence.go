@@ -0,0 +1,214 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// polygon is an outer ring followed by its holes, every ring is closed
+type polygon [][]model.GeoPoint
+
+// parseRings converts GeoJSON positions of one polygon and checks that the rings are closed and valid
+func parseRings(rings [][][]float64) (polygon, error) {
+	if len(rings) == 0 {
+		return nil, fmt.Errorf("%w: polygon has no rings", model.ErrValidation)
+	}
+	result := make(polygon, 0, len(rings))
+	for _, ring := range rings {
+		if len(ring) < 4 {
+			return nil, fmt.Errorf("%w: a ring needs at least 4 positions", model.ErrValidation)
+		}
+		points := make([]model.GeoPoint, 0, len(ring))
+		for _, position := range ring {
+			if len(position) < 2 {
+				return nil, fmt.Errorf("%w: a position needs longitude and latitude", model.ErrValidation)
+			}
+			point := model.GeoPoint{Lon: position[0], Lat: position[1]}
+			if point.Lat < -90 || point.Lat > 90 || point.Lon < -180 || point.Lon > 180 {
+				return nil, fmt.Errorf("%w: position [%v, %v] is out of range", model.ErrValidation, point.Lon, point.Lat)
+			}
+			points = append(points, point)
+		}
+		if points[0] != points[len(points)-1] {
+			return nil, fmt.Errorf("%w: rings must end where they start", model.ErrValidation)
+		}
+		result = append(result, points)
+	}
+	return result, nil
+}
+
+// parseGeometry turns a GeoJSON Polygon or MultiPolygon into polygons
+func parseGeometry(geometry model.Geometry) ([]polygon, error) {
+	switch geometry.Type {
+	case model.GeometryPolygon:
+		var rings [][][]float64
+		err := json.Unmarshal(geometry.Coordinates, &rings)
+		if err != nil {
+			return nil, fmt.Errorf("%w: polygon coordinates: %v", model.ErrValidation, err)
+		}
+		p, err := parseRings(rings)
+		if err != nil {
+			return nil, err
+		}
+		return []polygon{p}, nil
+	case model.GeometryMultiPolygon:
+		var polygons [][][][]float64
+		err := json.Unmarshal(geometry.Coordinates, &polygons)
+		if err != nil {
+			return nil, fmt.Errorf("%w: multipolygon coordinates: %v", model.ErrValidation, err)
+		}
+		if len(polygons) == 0 {
+			return nil, fmt.Errorf("%w: multipolygon has no polygons", model.ErrValidation)
+		}
+		result := make([]polygon, 0, len(polygons))
+		for _, rings := range polygons {
+			p, err := parseRings(rings)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, p)
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("%w: geometry type must be Polygon or MultiPolygon", model.ErrValidation)
+}
+
+// ringContains is the even-odd ray casting test, points on an edge may fall either way
+func ringContains(ring []model.GeoPoint, point model.GeoPoint) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > point.Lat) != (b.Lat > point.Lat) &&
+			point.Lon < (b.Lon-a.Lon)*(point.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// contains reports whether the point is inside the outer ring and outside every hole
+func (p polygon) contains(point model.GeoPoint) bool {
+	if !ringContains(p[0], point) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if ringContains(hole, point) {
+			return false
+		}
+	}
+	return true
+}
+
+// area is the planar area of the polygon in square degrees, good enough to compare zones
+func (p polygon) area() float64 {
+	var total float64
+	for i, ring := range p {
+		var sum float64
+		for j := 0; j < len(ring)-1; j++ {
+			sum += ring[j].Lon*ring[j+1].Lat - ring[j+1].Lon*ring[j].Lat
+		}
+		if i == 0 {
+			total += math.Abs(sum) / 2
+		} else {
+			total -= math.Abs(sum) / 2
+		}
+	}
+	return total
+}
+
+// locateZone returns the zone containing the point, the smallest one when zones overlap,
+// or nil when the point is outside every zone. Zones with invalid boundaries are skipped
+func locateZone(zones []*model.Zone, point model.GeoPoint) *model.Zone {
+	var found *model.Zone
+	var foundArea float64
+	for _, zone := range zones {
+		polygons, err := parseGeometry(zone.Boundary)
+		if err != nil {
+			continue
+		}
+		for _, p := range polygons {
+			if !p.contains(point) {
+				continue
+			}
+			area := p.area()
+			if found == nil || area < foundArea {
+				found, foundArea = zone, area
+			}
+		}
+	}
+	return found
+}
+
+// parseClock parses "HH:MM" into minutes since midnight, "24:00" is allowed as a closing time
+func parseClock(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(value, ":")
+	if !ok || len(hours) != 2 || len(minutes) != 2 {
+		return 0, fmt.Errorf("%w: time %q must be HH:MM", model.ErrValidation, value)
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil {
+		return 0, fmt.Errorf("%w: time %q must be HH:MM", model.ErrValidation, value)
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 || m > 59 || h < 0 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("%w: time %q must be HH:MM", model.ErrValidation, value)
+	}
+	return h*60 + m, nil
+}
+
+// validateZoneHours checks weekdays and that every period closes after it opens
+func validateZoneHours(hours []model.ZoneHours) error {
+	for _, h := range hours {
+		if h.Weekday < time.Sunday || h.Weekday > time.Saturday {
+			return fmt.Errorf("%w: weekday must be 0 to 6", model.ErrValidation)
+		}
+		open, err := parseClock(h.Open)
+		if err != nil {
+			return err
+		}
+		closing, err := parseClock(h.Close)
+		if err != nil {
+			return err
+		}
+		if open >= closing || open == 24*60 {
+			return fmt.Errorf("%w: %s closes before it opens", model.ErrValidation, h.Weekday)
+		}
+	}
+	return nil
+}
+
+// zoneOpen reports whether the zone works at the given moment, a zone without hours is always open
+func zoneOpen(zone *model.Zone, at time.Time) (bool, error) {
+	if len(zone.Hours) == 0 {
+		return true, nil
+	}
+	location, err := time.LoadLocation(zone.TimeZone)
+	if err != nil {
+		return false, fmt.Errorf("LoadLocation: %w", err)
+	}
+	local := at.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	for _, h := range zone.Hours {
+		if h.Weekday != local.Weekday() {
+			continue
+		}
+		open, err := parseClock(h.Open)
+		if err != nil {
+			return false, err
+		}
+		closing, err := parseClock(h.Close)
+		if err != nil {
+			return false, err
+		}
+		if minute >= open && minute < closing {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// square returns a closed ring of a square with the given lower left corner and side in degrees
+func square(lon, lat, side float64) [][]float64 {
+	return [][]float64{{lon, lat}, {lon + side, lat}, {lon + side, lat + side}, {lon, lat + side}, {lon, lat}}
+}
+
+func testZone(t *testing.T, code string, geometryType string, coordinates interface{}) *model.Zone {
+	raw, err := json.Marshal(coordinates)
+	require.NoError(t, err)
+	return &model.Zone{Id: uuid.New(), Code: code, Boundary: model.Geometry{Type: geometryType, Coordinates: raw}}
+}
+
+// TestLocateZone checks holes, multipolygons and that the smallest overlapping zone wins
+func TestLocateZone(t *testing.T) {
+	city := testZone(t, "city", model.GeometryPolygon, [][][]float64{square(27, 53, 1), square(27.8, 53.8, 0.1)})
+	center := testZone(t, "center", model.GeometryPolygon, [][][]float64{square(27.4, 53.4, 0.2)})
+	suburbs := testZone(t, "suburbs", model.GeometryMultiPolygon, [][][][]float64{{square(30, 50, 1)}, {square(32, 50, 1)}})
+	broken := testZone(t, "broken", model.GeometryPolygon, [][][]float64{{{27, 53}, {28, 53}, {28, 54}}})
+	zones := []*model.Zone{broken, city, center, suburbs}
+
+	require.Equal(t, city, locateZone(zones, model.GeoPoint{Lat: 53.1, Lon: 27.1}))
+	require.Equal(t, center, locateZone(zones, model.GeoPoint{Lat: 53.5, Lon: 27.5}))
+	require.Nil(t, locateZone(zones, model.GeoPoint{Lat: 53.85, Lon: 27.85}), "the hole is outside the city")
+	require.Equal(t, suburbs, locateZone(zones, model.GeoPoint{Lat: 50.5, Lon: 32.5}))
+	require.Nil(t, locateZone(zones, model.GeoPoint{Lat: 50.5, Lon: 31.5}))
+}
+
+// TestValidateZone checks the boundary, time zone and hours of a zone
+func TestValidateZone(t *testing.T) {
+	zone := testZone(t, " center ", model.GeometryPolygon, [][][]float64{square(27.4, 53.4, 0.2)})
+	zone.Hours = []model.ZoneHours{{Weekday: time.Saturday, Open: "10:00", Close: "24:00"}}
+	require.NoError(t, validateZone(zone))
+	require.Equal(t, "center", zone.Code)
+	require.Equal(t, "center", zone.Name)
+	require.Equal(t, "UTC", zone.TimeZone)
+
+	open := testZone(t, "ring", model.GeometryPolygon, [][][]float64{{{27, 53}, {28, 53}, {28, 54}, {27, 54}}})
+	require.ErrorIs(t, validateZone(open), model.ErrValidation)
+	point := testZone(t, "point", "Point", []float64{27, 53})
+	require.ErrorIs(t, validateZone(point), model.ErrValidation)
+	zone.Code = model.DefaultTariffZone
+	require.ErrorIs(t, validateZone(zone), model.ErrValidation)
+
+	zone.Code = "center"
+	for _, hours := range []model.ZoneHours{{Weekday: 7, Open: "08:00", Close: "20:00"}, {Open: "20:00", Close: "08:00"}, {Open: "8:00", Close: "20:00"}, {Open: "08:00", Close: "24:30"}} {
+		zone.Hours = []model.ZoneHours{hours}
+		require.ErrorIs(t, validateZone(zone), model.ErrValidation)
+	}
+}
+
+// TestZoneOpen checks opening hours in the zone's time zone
+func TestZoneOpen(t *testing.T) {
+	zone := &model.Zone{TimeZone: "Europe/Minsk", Hours: []model.ZoneHours{{Weekday: time.Monday, Open: "08:00", Close: "20:00"}}}
+	for at, want := range map[time.Time]bool{
+		time.Date(2026, 3, 2, 5, 0, 0, 0, time.UTC):  true,  // 08:00 in Minsk
+		time.Date(2026, 3, 2, 4, 59, 0, 0, time.UTC): false, // 07:59
+		time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC): false, // 20:00
+		time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC): false, // Tuesday
+	} {
+		open, err := zoneOpen(zone, at)
+		require.NoError(t, err)
+		require.Equal(t, want, open, at)
+	}
+	open, err := zoneOpen(&model.Zone{}, time.Date(2026, 3, 3, 3, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, open)
+}
+
+// TestCheckHomeZone checks that home zones restrict claims only when they are set
+func TestCheckHomeZone(t *testing.T) {
+	home, other := uuid.New(), uuid.New()
+	zones := []*model.Zone{{Id: home}}
+	require.NoError(t, checkHomeZone(nil, &model.DeliveryGet{ZoneId: &other}))
+	require.NoError(t, checkHomeZone(zones, &model.DeliveryGet{}))
+	require.NoError(t, checkHomeZone(zones, &model.DeliveryGet{ZoneId: &home}))
+	require.ErrorIs(t, checkHomeZone(zones, &model.DeliveryGet{ZoneId: &other}), model.ErrForbidden)
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+type HubService struct {
+	rps   HubRepository
+	clock Clock
+}
+
+func NewHubService(rps HubRepository, clock Clock) *HubService {
+	return &HubService{rps: rps, clock: clock}
+}
+
+type HubRepository interface {
+	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
+	GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error)
+	InsertHub(ctx context.Context, hub *model.Hub) error
+	GetHub(ctx context.Context, hubId uuid.UUID) (*model.Hub, error)
+	GetHubs(ctx context.Context) ([]*model.Hub, error)
+	SetHubStaff(ctx context.Context, staff *model.HubStaff) error
+	GetStaffHub(ctx context.Context, userId uuid.UUID) (uuid.UUID, error)
+	InsertLegs(ctx context.Context, deliveryId uuid.UUID, legs []*model.DeliveryLeg) error
+	GetLeg(ctx context.Context, legId uuid.UUID) (*model.DeliveryLeg, error)
+	GetDeliveryLegs(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryLeg, error)
+	GetCourierLegs(ctx context.Context, courierId uuid.UUID) ([]*model.DeliveryLeg, error)
+	GetHubInventory(ctx context.Context, hubId uuid.UUID) ([]*model.DeliveryLeg, error)
+	UpdateLeg(ctx context.Context, leg *model.DeliveryLeg, progress *model.LegProgress) error
+}
+
+// CreateHub adds an active hub
+func (srv *HubService) CreateHub(ctx context.Context, hub *model.Hub) (*model.Hub, error) {
+	hub.Code = strings.TrimSpace(hub.Code)
+	hub.Name = strings.TrimSpace(hub.Name)
+	if hub.Code == "" {
+		return nil, fmt.Errorf("%w: code is required", model.ErrValidation)
+	}
+	if hub.Name == "" {
+		hub.Name = hub.Code
+	}
+	err := validateLocation("location", &hub.Location)
+	if err != nil {
+		return nil, fmt.Errorf("validateLocation: %w", err)
+	}
+	hub.Id = uuid.New()
+	hub.Active = true
+	hub.CreatedAt = srv.clock.Now().UTC()
+	err = srv.rps.InsertHub(ctx, hub)
+	if err != nil {
+		return nil, fmt.Errorf("InsertHub: %w", err)
+	}
+	return hub, nil
+}
+
+func (srv *HubService) GetHubs(ctx context.Context) ([]*model.Hub, error) {
+	hubs, err := srv.rps.GetHubs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetHubs: %w", err)
+	}
+	return hubs, nil
+}
+
+// SetHubStaff assigns a user to the hub where they check parcels in and out, replacing their previous hub
+func (srv *HubService) SetHubStaff(ctx context.Context, staff *model.HubStaff) error {
+	_, err := srv.rps.GetHub(ctx, staff.HubId)
+	if err != nil {
+		return fmt.Errorf("GetHub: %w", err)
+	}
+	err = srv.rps.SetHubStaff(ctx, staff)
+	if err != nil {
+		return fmt.Errorf("SetHubStaff: %w", err)
+	}
+	return nil
+}
+
+// RouteDelivery splits a new unassigned delivery into legs through active hubs
+func (srv *HubService) RouteDelivery(ctx context.Context, routing *model.DeliveryRouting) (*model.DeliveryLegs, error) {
+	delivery, err := srv.rps.GetDeliveryByID(ctx, routing.DeliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryByID: %w", err)
+	}
+	if delivery.DeliveryStatus != model.DeliveryStatusCreated || delivery.CourierId != nil || delivery.Legs > 0 || delivery.ReturnOf != nil {
+		return nil, fmt.Errorf("%w: only new unassigned deliveries can be routed through hubs", model.ErrConflict)
+	}
+	legs, err := planLegs(delivery.Id, routing.HubIds, srv.clock.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("planLegs: %w", err)
+	}
+	for _, hubId := range routing.HubIds {
+		hub, err := srv.rps.GetHub(ctx, hubId)
+		if err != nil {
+			return nil, fmt.Errorf("GetHub: %w", err)
+		}
+		if !hub.Active {
+			return nil, fmt.Errorf("%w: hub %s is inactive", model.ErrConflict, hub.Code)
+		}
+	}
+	err = srv.rps.InsertLegs(ctx, delivery.Id, legs)
+	if err != nil {
+		return nil, fmt.Errorf("InsertLegs: %w", err)
+	}
+	return &model.DeliveryLegs{DeliveryId: delivery.Id, LegProgress: *deriveProgress(legs), Legs: legs}, nil
+}
+
+// GetDeliveryLegs returns the legs of a delivery with the state derived from them
+func (srv *HubService) GetDeliveryLegs(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryLegs, error) {
+	legs, err := srv.rps.GetDeliveryLegs(ctx, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryLegs: %w", err)
+	}
+	if len(legs) == 0 {
+		return nil, fmt.Errorf("GetDeliveryLegs: %w: delivery has no legs", model.ErrNotFound)
+	}
+	return &model.DeliveryLegs{DeliveryId: deliveryId, LegProgress: *deriveProgress(legs), Legs: legs}, nil
+}
+
+// loadLeg returns the leg with the given id together with all legs of its delivery
+func (srv *HubService) loadLeg(ctx context.Context, legId uuid.UUID) (*model.DeliveryLeg, []*model.DeliveryLeg, error) {
+	found, err := srv.rps.GetLeg(ctx, legId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetLeg: %w", err)
+	}
+	legs, err := srv.rps.GetDeliveryLegs(ctx, found.DeliveryId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetDeliveryLegs: %w", err)
+	}
+	for _, leg := range legs {
+		if leg.Id == legId {
+			return leg, legs, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("loadLeg: %w", model.ErrNotFound)
+}
+
+// saveLeg stores a changed leg and copies the state derived from all legs onto the delivery
+func (srv *HubService) saveLeg(ctx context.Context, leg *model.DeliveryLeg, legs []*model.DeliveryLeg) error {
+	err := srv.rps.UpdateLeg(ctx, leg, deriveProgress(legs))
+	if err != nil {
+		return fmt.Errorf("UpdateLeg: %w", err)
+	}
+	return nil
+}
+
+// assignLeg gives a leg that is not picked up yet to the courier
+func (srv *HubService) assignLeg(ctx context.Context, legId uuid.UUID, courier *model.Courier, onlyFree bool) (*model.DeliveryLeg, error) {
+	leg, legs, err := srv.loadLeg(ctx, legId)
+	if err != nil {
+		return nil, err
+	}
+	if leg.Status != model.DeliveryStatusCreated {
+		return nil, fmt.Errorf("%w: leg is %s", model.ErrConflict, leg.Status)
+	}
+	if onlyFree && leg.CourierId != nil {
+		return nil, fmt.Errorf("%w: leg is already taken", model.ErrConflict)
+	}
+	leg.CourierId = &courier.Id
+	err = srv.saveLeg(ctx, leg, legs)
+	if err != nil {
+		return nil, err
+	}
+	return leg, nil
+}
+
+// AssignLeg lets a manager assign or reassign a leg that is not picked up yet
+func (srv *HubService) AssignLeg(ctx context.Context, assignment *model.LegAssignment) (*model.DeliveryLeg, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, assignment.CourierUserId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	if courier.Status == model.CourierStatusSuspended {
+		return nil, fmt.Errorf("AssignLeg: %w: courier is suspended", model.ErrConflict)
+	}
+	return srv.assignLeg(ctx, assignment.LegId, courier, false)
+}
+
+// ClaimLeg lets an active courier take a free leg
+func (srv *HubService) ClaimLeg(ctx context.Context, userId uuid.UUID, legId uuid.UUID) (*model.DeliveryLeg, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	if courier.Status != model.CourierStatusActive {
+		return nil, fmt.Errorf("ClaimLeg: %w: courier is %s", model.ErrConflict, courier.Status)
+	}
+	return srv.assignLeg(ctx, legId, courier, true)
+}
+
+// GetCourierLegs returns free legs and the courier's own unfinished legs
+func (srv *HubService) GetCourierLegs(ctx context.Context, userId uuid.UUID) ([]*model.DeliveryLeg, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	legs, err := srv.rps.GetCourierLegs(ctx, courier.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierLegs: %w", err)
+	}
+	return legs, nil
+}
+
+// PickUpLeg lets the courier of the first leg pick the parcel up at the pickup address,
+// legs starting at a hub are picked up when the hub checks the parcel out
+func (srv *HubService) PickUpLeg(ctx context.Context, userId uuid.UUID, legId uuid.UUID) (*model.DeliveryLeg, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	leg, legs, err := srv.loadLeg(ctx, legId)
+	if err != nil {
+		return nil, err
+	}
+	if leg.CourierId == nil || *leg.CourierId != courier.Id {
+		return nil, fmt.Errorf("PickUpLeg: %w", model.ErrNotFound)
+	}
+	if leg.FromHubId != nil {
+		return nil, fmt.Errorf("%w: the hub checks the parcel out to the courier", model.ErrConflict)
+	}
+	if leg.Status != model.DeliveryStatusCreated {
+		return nil, fmt.Errorf("%w: leg is %s", model.ErrConflict, leg.Status)
+	}
+	now := srv.clock.Now().UTC()
+	leg.Status, leg.PickedUpAt = model.DeliveryStatusPickedUp, &now
+	err = srv.saveLeg(ctx, leg, legs)
+	if err != nil {
+		return nil, err
+	}
+	return leg, nil
+}
+
+// staffLegs returns the hub of the staff member and the legs of the scanned delivery
+func (srv *HubService) staffLegs(ctx context.Context, userId uuid.UUID, deliveryId uuid.UUID) (uuid.UUID, []*model.DeliveryLeg, error) {
+	hubId, err := srv.rps.GetStaffHub(ctx, userId)
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("GetStaffHub: %w", err)
+	}
+	legs, err := srv.rps.GetDeliveryLegs(ctx, deliveryId)
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("GetDeliveryLegs: %w", err)
+	}
+	if len(legs) == 0 {
+		return uuid.Nil, nil, fmt.Errorf("staffLegs: %w: delivery has no legs", model.ErrNotFound)
+	}
+	return hubId, legs, nil
+}
+
+// CheckIn records the arrival of a parcel at the staff member's hub, finishing the leg that ends there
+func (srv *HubService) CheckIn(ctx context.Context, userId uuid.UUID, deliveryId uuid.UUID) (*model.DeliveryLeg, error) {
+	hubId, legs, err := srv.staffLegs(ctx, userId, deliveryId)
+	if err != nil {
+		return nil, err
+	}
+	leg := legToHub(legs, hubId)
+	if leg == nil {
+		return nil, fmt.Errorf("%w: parcel is not on its way to this hub", model.ErrConflict)
+	}
+	now := srv.clock.Now().UTC()
+	leg.Status, leg.DeliveredAt = model.DeliveryStatusDelivered, &now
+	err = srv.saveLeg(ctx, leg, legs)
+	if err != nil {
+		return nil, err
+	}
+	return leg, nil
+}
+
+// CheckOut hands a parcel waiting at the staff member's hub to the courier of its next leg
+func (srv *HubService) CheckOut(ctx context.Context, userId uuid.UUID, deliveryId uuid.UUID) (*model.DeliveryLeg, error) {
+	hubId, legs, err := srv.staffLegs(ctx, userId, deliveryId)
+	if err != nil {
+		return nil, err
+	}
+	leg := legAtHub(legs, hubId)
+	if leg == nil {
+		return nil, fmt.Errorf("%w: parcel is not at this hub", model.ErrConflict)
+	}
+	if leg.CourierId == nil {
+		return nil, fmt.Errorf("%w: next leg has no courier yet", model.ErrConflict)
+	}
+	now := srv.clock.Now().UTC()
+	leg.Status, leg.PickedUpAt = model.DeliveryStatusPickedUp, &now
+	err = srv.saveLeg(ctx, leg, legs)
+	if err != nil {
+		return nil, err
+	}
+	return leg, nil
+}
+
+// GetHubInventory returns the legs whose parcels wait at the staff member's hub
+func (srv *HubService) GetHubInventory(ctx context.Context, userId uuid.UUID) ([]*model.DeliveryLeg, error) {
+	hubId, err := srv.rps.GetStaffHub(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetStaffHub: %w", err)
+	}
+	legs, err := srv.rps.GetHubInventory(ctx, hubId)
+	if err != nil {
+		return nil, fmt.Errorf("GetHubInventory: %w", err)
+	}
+	return legs, nil
+}
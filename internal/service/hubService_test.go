@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// legRepository keeps the legs of one delivery in memory
+type legRepository struct {
+	HubRepository
+	courier  *model.Courier
+	staffHub uuid.UUID
+	legs     []*model.DeliveryLeg
+	progress *model.LegProgress
+}
+
+func (r *legRepository) GetCourierByUserID(_ context.Context, _ uuid.UUID) (*model.Courier, error) {
+	return r.courier, nil
+}
+
+func (r *legRepository) GetStaffHub(_ context.Context, _ uuid.UUID) (uuid.UUID, error) {
+	return r.staffHub, nil
+}
+
+func (r *legRepository) GetDeliveryLegs(_ context.Context, _ uuid.UUID) ([]*model.DeliveryLeg, error) {
+	legs := make([]*model.DeliveryLeg, 0, len(r.legs))
+	for _, leg := range r.legs {
+		copied := *leg
+		legs = append(legs, &copied)
+	}
+	return legs, nil
+}
+
+func (r *legRepository) GetLeg(ctx context.Context, legId uuid.UUID) (*model.DeliveryLeg, error) {
+	for _, leg := range r.legs {
+		if leg.Id == legId {
+			copied := *leg
+			return &copied, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (r *legRepository) UpdateLeg(_ context.Context, leg *model.DeliveryLeg, progress *model.LegProgress) error {
+	copied := *leg
+	r.legs[leg.Position] = &copied
+	r.progress = progress
+	return nil
+}
+
+// TestPlanLegs checks the legs through the hubs and the routing limits
+func TestPlanLegs(t *testing.T) {
+	now := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+	first, second := uuid.New(), uuid.New()
+	legs, err := planLegs(uuid.New(), []uuid.UUID{first, second}, now)
+	require.NoError(t, err)
+	require.Len(t, legs, 3)
+	require.Nil(t, legs[0].FromHubId)
+	require.Equal(t, first, *legs[0].ToHubId)
+	require.Equal(t, first, *legs[1].FromHubId)
+	require.Equal(t, second, *legs[1].ToHubId)
+	require.Equal(t, second, *legs[2].FromHubId)
+	require.Nil(t, legs[2].ToHubId)
+	require.Equal(t, 2, legs[2].Position)
+
+	_, err = planLegs(uuid.New(), nil, now)
+	require.ErrorIs(t, err, model.ErrValidation)
+	_, err = planLegs(uuid.New(), []uuid.UUID{first, second, first}, now)
+	require.ErrorIs(t, err, model.ErrValidation)
+	_, err = planLegs(uuid.New(), make([]uuid.UUID, maxHubsPerDelivery+1), now)
+	require.ErrorIs(t, err, model.ErrValidation)
+}
+
+// TestDeriveProgress checks the delivery state while the parcel travels through a hub
+func TestDeriveProgress(t *testing.T) {
+	now := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+	hubId := uuid.New()
+	legs, err := planLegs(uuid.New(), []uuid.UUID{hubId}, now)
+	require.NoError(t, err)
+	first, second := uuid.New(), uuid.New()
+	legs[0].CourierId, legs[1].CourierId = &first, &second
+
+	progress := deriveProgress(legs)
+	require.Equal(t, model.DeliveryStatusCreated, progress.Status)
+	require.Equal(t, first, *progress.CourierId)
+
+	legs[0].Status, legs[0].PickedUpAt = model.DeliveryStatusPickedUp, &now
+	progress = deriveProgress(legs)
+	require.Equal(t, model.DeliveryStatusPickedUp, progress.Status)
+	require.Equal(t, &now, progress.PickedUpAt)
+	require.Nil(t, progress.AtHubId)
+	require.Equal(t, legs[0], legToHub(legs, hubId))
+
+	legs[0].Status = model.DeliveryStatusDelivered
+	progress = deriveProgress(legs)
+	require.Equal(t, model.DeliveryStatusPickedUp, progress.Status)
+	require.Equal(t, hubId, *progress.AtHubId)
+	require.Equal(t, second, *progress.CourierId)
+	require.Equal(t, legs[1], legAtHub(legs, hubId))
+	require.Nil(t, legAtHub(legs, uuid.New()))
+
+	later := now.Add(time.Hour)
+	legs[1].Status, legs[1].DeliveredAt = model.DeliveryStatusDelivered, &later
+	progress = deriveProgress(legs)
+	require.Equal(t, model.DeliveryStatusDelivered, progress.Status)
+	require.Equal(t, &later, progress.DeliveredAt)
+
+	legs[1].Status = model.DeliveryStatusCancelled
+	require.Equal(t, model.DeliveryStatusCancelled, deriveProgress(legs).Status)
+}
+
+// TestHubScanning checks pickup, check in and check out of a delivery routed through one hub
+func TestHubScanning(t *testing.T) {
+	now := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+	hubId := uuid.New()
+	legs, err := planLegs(uuid.New(), []uuid.UUID{hubId}, now)
+	require.NoError(t, err)
+	courier := &model.Courier{Id: uuid.New(), Status: model.CourierStatusActive}
+	rps := &legRepository{courier: courier, staffHub: hubId, legs: legs}
+	srv := NewHubService(rps, &fixedClock{now})
+	ctx := context.Background()
+	deliveryId := legs[0].DeliveryId
+
+	_, err = srv.CheckIn(ctx, uuid.New(), deliveryId)
+	require.ErrorIs(t, err, model.ErrConflict, "the parcel has not been picked up")
+
+	_, err = srv.ClaimLeg(ctx, uuid.New(), legs[0].Id)
+	require.NoError(t, err)
+	_, err = srv.ClaimLeg(ctx, uuid.New(), legs[0].Id)
+	require.ErrorIs(t, err, model.ErrConflict)
+	_, err = srv.PickUpLeg(ctx, uuid.New(), legs[1].Id)
+	require.ErrorIs(t, err, model.ErrNotFound)
+	_, err = srv.PickUpLeg(ctx, uuid.New(), legs[0].Id)
+	require.NoError(t, err)
+	require.Equal(t, model.DeliveryStatusPickedUp, rps.progress.Status)
+
+	leg, err := srv.CheckIn(ctx, uuid.New(), deliveryId)
+	require.NoError(t, err)
+	require.Equal(t, model.DeliveryStatusDelivered, leg.Status)
+	require.Equal(t, hubId, *rps.progress.AtHubId)
+
+	_, err = srv.CheckOut(ctx, uuid.New(), deliveryId)
+	require.ErrorIs(t, err, model.ErrConflict, "the next leg has no courier")
+	_, err = srv.ClaimLeg(ctx, uuid.New(), rps.legs[1].Id)
+	require.NoError(t, err)
+	leg, err = srv.CheckOut(ctx, uuid.New(), deliveryId)
+	require.NoError(t, err)
+	require.Equal(t, model.DeliveryStatusPickedUp, leg.Status)
+	require.Nil(t, rps.progress.AtHubId)
+	require.Equal(t, courier.Id, *rps.progress.CourierId)
+}
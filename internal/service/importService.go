@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// maxImportRows limits the deliveries of one import
+const maxImportRows = 5000
+
+type ImportService struct {
+	rps       ImportRepository
+	pricer    DeliveryPricer
+	clock     Clock
+	batchSize int
+}
+
+func NewImportService(rps ImportRepository, pricer DeliveryPricer, clock Clock, batchSize int) *ImportService {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	return &ImportService{rps: rps, pricer: pricer, clock: clock, batchSize: batchSize}
+}
+
+type ImportRepository interface {
+	ImportDeliveries(ctx context.Context, deliveries []*model.Delivery, batchSize int) error
+	IsClient(ctx context.Context, userId uuid.UUID) (bool, error)
+	GetPromo(ctx context.Context, code string) (*model.Promo, error)
+}
+
+// ImportDeliveries validates and prices every row of the spreadsheet like a delivery created on its own
+// and inserts the valid ones together, rows with problems are listed in the report and skipped.
+// Rows without a client_id belong to the client of the upload, rows past the uses left of their promo code are reported
+func (srv *ImportService) ImportDeliveries(ctx context.Context, upload *model.DeliveryImport) (*model.ImportReport, error) {
+	rows, err := readSpreadsheet(upload.Format, upload.Data)
+	if err != nil {
+		return nil, fmt.Errorf("readSpreadsheet: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", model.ErrValidation)
+	}
+	columns, err := importHeader(rows[0])
+	if err != nil {
+		return nil, fmt.Errorf("importHeader: %w", err)
+	}
+	clients := make(map[uuid.UUID]bool)
+	if upload.ClientId != nil {
+		known, err := srv.isClient(ctx, *upload.ClientId, clients)
+		if err != nil {
+			return nil, fmt.Errorf("isClient: %w", err)
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: client %s not found", model.ErrValidation, upload.ClientId)
+		}
+	}
+	report := &model.ImportReport{DryRun: upload.DryRun, Deliveries: []*model.ImportedDelivery{}, Errors: []*model.ImportRowError{}}
+	var deliveries []*model.Delivery
+	promoUsesLeft := make(map[string]*int)
+	now := srv.clock.Now().UTC()
+	for i, row := range rows[1:] {
+		if blankRow(row) {
+			continue
+		}
+		report.Rows++
+		if report.Rows > maxImportRows {
+			return nil, fmt.Errorf("%w: at most %d rows can be imported at once", model.ErrValidation, maxImportRows)
+		}
+		rowNumber := i + 2
+		delivery, rowErrors := parseImportRow(rowNumber, columns, row)
+		if len(rowErrors) > 0 {
+			report.Errors = append(report.Errors, rowErrors...)
+			continue
+		}
+		if delivery.ClientId == nil {
+			delivery.ClientId = upload.ClientId
+		} else {
+			known, err := srv.isClient(ctx, *delivery.ClientId, clients)
+			if err != nil {
+				return nil, fmt.Errorf("isClient: %w", err)
+			}
+			if !known {
+				report.Errors = append(report.Errors, &model.ImportRowError{Row: rowNumber, Column: "client_id", Message: "client not found"})
+				continue
+			}
+		}
+		err = srv.prepare(ctx, delivery, now)
+		if errors.Is(err, model.ErrValidation) || errors.Is(err, model.ErrConflict) {
+			report.Errors = append(report.Errors, &model.ImportRowError{Row: rowNumber, Message: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("prepare: %w", err)
+		}
+		if delivery.Price != nil && delivery.Price.PromoCode != "" {
+			used, err := srv.usePromo(ctx, delivery.Price.PromoCode, promoUsesLeft)
+			if err != nil {
+				return nil, fmt.Errorf("usePromo: %w", err)
+			}
+			if !used {
+				report.Errors = append(report.Errors, &model.ImportRowError{Row: rowNumber, Column: "promo_code",
+					Message: fmt.Sprintf("promo code %s is used up by the rows above", delivery.Price.PromoCode)})
+				continue
+			}
+		}
+		deliveries = append(deliveries, delivery)
+		report.Deliveries = append(report.Deliveries, &model.ImportedDelivery{Row: rowNumber, Price: delivery.Price.Total})
+	}
+	report.Valid = len(deliveries)
+	if upload.DryRun || len(deliveries) == 0 {
+		return report, nil
+	}
+	err = srv.rps.ImportDeliveries(ctx, deliveries, srv.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("ImportDeliveries: %w", err)
+	}
+	for i, delivery := range deliveries {
+		report.Deliveries[i].Id = delivery.Id.String()
+		report.Deliveries[i].TrackingCode = delivery.TrackingCode
+		report.Deliveries[i].Pin = delivery.Pin
+	}
+	report.Inserted = len(deliveries)
+	return report, nil
+}
+
+// isClient reports whether the user is a client, remembering the answers in clients
+func (srv *ImportService) isClient(ctx context.Context, userId uuid.UUID, clients map[uuid.UUID]bool) (bool, error) {
+	known, checked := clients[userId]
+	if checked {
+		return known, nil
+	}
+	known, err := srv.rps.IsClient(ctx, userId)
+	if err != nil {
+		return false, fmt.Errorf("IsClient: %w", err)
+	}
+	clients[userId] = known
+	return known, nil
+}
+
+// usePromo takes one use of a promo code for a row, it reports false when the rows before have taken all uses left.
+// usesLeft holds the uses left of every code seen so far, nil for codes without a limit
+func (srv *ImportService) usePromo(ctx context.Context, code string, usesLeft map[string]*int) (bool, error) {
+	left, seen := usesLeft[code]
+	if !seen {
+		promo, err := srv.rps.GetPromo(ctx, code)
+		if err != nil {
+			return false, fmt.Errorf("GetPromo: %w", err)
+		}
+		if promo.MaxUses != nil {
+			left = new(int)
+			*left = *promo.MaxUses - promo.Used
+		}
+		usesLeft[code] = left
+	}
+	if left == nil {
+		return true, nil
+	}
+	if *left <= 0 {
+		return false, nil
+	}
+	*left--
+	return true, nil
+}
+
+// prepare validates and prices a delivery parsed from a row and gives it a tracking code and PIN
+func (srv *ImportService) prepare(ctx context.Context, delivery *model.Delivery, now time.Time) error {
+	err := validateDelivery(delivery)
+	if err != nil {
+		return err
+	}
+	err = srv.pricer.PriceDelivery(ctx, delivery)
+	if err != nil {
+		return err
+	}
+	delivery.CreatedAt = now
+	delivery.DeliveryStatus = model.DeliveryStatusCreated
+	delivery.TrackingCode, err = newTrackingCode()
+	if err != nil {
+		return fmt.Errorf("newTrackingCode: %w", err)
+	}
+	delivery.Pin, err = newDeliveryPin()
+	if err != nil {
+		return fmt.Errorf("newDeliveryPin: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// cityPricer prices deliveries at a flat 5 and refuses drop-offs in cities without a tariff, promo codes are applied as they are
+type cityPricer struct {
+	unserved string
+}
+
+func (p cityPricer) PriceDelivery(_ context.Context, delivery *model.Delivery) error {
+	if delivery.Dropoff.City == p.unserved {
+		return model.ErrConflict
+	}
+	delivery.Price = &model.Quote{Total: 5, PromoCode: delivery.PromoCode}
+	return nil
+}
+
+type importRepository struct {
+	deliveries []*model.Delivery
+	batchSize  int
+	clients    map[uuid.UUID]bool
+	promos     map[string]*model.Promo
+}
+
+func (r *importRepository) IsClient(_ context.Context, userId uuid.UUID) (bool, error) {
+	return r.clients[userId], nil
+}
+
+func (r *importRepository) GetPromo(_ context.Context, code string) (*model.Promo, error) {
+	promo, ok := r.promos[code]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return promo, nil
+}
+
+func (r *importRepository) ImportDeliveries(_ context.Context, deliveries []*model.Delivery, batchSize int) error {
+	for _, delivery := range deliveries {
+		delivery.Id = uuid.New()
+	}
+	r.deliveries, r.batchSize = deliveries, batchSize
+	return nil
+}
+
+const importCSV = "Pickup_Address_Line1,pickup_city,pickup_lat,pickup_lon,dropoff_address_line1,dropoff_city,dropoff_lat,dropoff_lon," +
+	"recipient_name,recipient_phone,window_start,window_end,express,cod_amount\n" +
+	"Main St 1,Minsk,53.9,27.56,Lenina 2,Minsk,53.91,27.55,Anna,+375291234567,2024-05-06 10:00,2024-05-06 12:00,yes,10\n" +
+	"Main St 1,Minsk,53.9,27.56,Lenina 2,Minsk,abc,27.55,Anna,+375291234567,2024-05-06 10:00,2024-05-06 12:00,maybe,\n" +
+	",,,,,,,,,,,,,\n" +
+	"Main St 1,Minsk,53.9,27.56,Lenina 2,Grodno,53.68,23.83,Anna,+375291234567,2024-05-06 10:00,2024-05-06 12:00,,\n" +
+	"Main St 1,Minsk,53.9,27.56,Lenina 2,Minsk,53.91,27.55,Anna,+375291234567,2024-05-06 12:00,2024-05-06 10:00,,\n"
+
+// TestImportDeliveries checks the report of a CSV import with valid, unparsable, unpriceable and invalid rows
+func TestImportDeliveries(t *testing.T) {
+	rps := &importRepository{}
+	now := time.Date(2024, 5, 6, 8, 0, 0, 0, time.UTC)
+	srv := NewImportService(rps, cityPricer{unserved: "Grodno"}, &fixedClock{now}, 100)
+
+	report, err := srv.ImportDeliveries(context.Background(), &model.DeliveryImport{Format: model.ImportFormatCSV, Data: []byte(importCSV), DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, 4, report.Rows)
+	require.Equal(t, 1, report.Valid)
+	require.Zero(t, report.Inserted)
+	require.Nil(t, rps.deliveries, "a dry run saves nothing")
+	require.Len(t, report.Errors, 4)
+	require.Equal(t, &model.ImportRowError{Row: 3, Column: "dropoff_lat", Message: `"abc" is not a number`}, report.Errors[0])
+	require.Equal(t, "express", report.Errors[1].Column)
+	require.Equal(t, 5, report.Errors[2].Row, "the blank row keeps its number")
+	require.Equal(t, 6, report.Errors[3].Row)
+	require.Contains(t, report.Errors[3].Message, "window_end is before window_start")
+
+	report, err = srv.ImportDeliveries(context.Background(), &model.DeliveryImport{Format: model.ImportFormatCSV, Data: []byte(importCSV)})
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Inserted)
+	require.Equal(t, 100, rps.batchSize)
+	require.Len(t, rps.deliveries, 1)
+	delivery := rps.deliveries[0]
+	require.True(t, delivery.Express)
+	require.Equal(t, 10.0, delivery.CodAmount)
+	require.Equal(t, model.DeliveryStatusCreated, delivery.DeliveryStatus)
+	require.Equal(t, now, delivery.CreatedAt)
+	require.Equal(t, time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC), *delivery.WindowStart)
+	require.NotEmpty(t, delivery.Pin)
+	require.Equal(t, &model.ImportedDelivery{Row: 2, Id: delivery.Id.String(), TrackingCode: delivery.TrackingCode, Pin: delivery.Pin, Price: 5}, report.Deliveries[0])
+}
+
+// TestImportClientsAndPromoUses checks that rows get the client of the upload unless they name a known client of their own
+// and that rows past the uses left of their promo code are reported
+func TestImportClientsAndPromoUses(t *testing.T) {
+	merchant, other, stranger := uuid.New(), uuid.New(), uuid.New()
+	maxUses := 3
+	rps := &importRepository{
+		clients: map[uuid.UUID]bool{merchant: true, other: true},
+		promos:  map[string]*model.Promo{"SPRING": {Code: "SPRING", MaxUses: &maxUses, Used: 1}, "ALWAYS": {Code: "ALWAYS"}},
+	}
+	srv := NewImportService(rps, cityPricer{}, &fixedClock{time.Date(2024, 5, 6, 8, 0, 0, 0, time.UTC)}, 100)
+	row := "Main St 1,Minsk,53.9,27.56,Lenina 2,Minsk,53.91,27.55,Anna,+375291234567,2024-05-06 10:00,2024-05-06 12:00,"
+	data := "pickup_address_line1,pickup_city,pickup_lat,pickup_lon,dropoff_address_line1,dropoff_city,dropoff_lat,dropoff_lon," +
+		"recipient_name,recipient_phone,window_start,window_end,promo_code,client_id\n" +
+		row + "SPRING,\n" +
+		row + "SPRING," + other.String() + "\n" +
+		row + "SPRING,\n" +
+		row + "ALWAYS," + stranger.String() + "\n" +
+		row + "ALWAYS,\n"
+
+	_, err := srv.ImportDeliveries(context.Background(), &model.DeliveryImport{Format: model.ImportFormatCSV, Data: []byte(data), ClientId: &stranger})
+	require.ErrorIs(t, err, model.ErrValidation, "the client of the upload has to be known")
+
+	report, err := srv.ImportDeliveries(context.Background(), &model.DeliveryImport{Format: model.ImportFormatCSV, Data: []byte(data), ClientId: &merchant})
+	require.NoError(t, err)
+	require.Equal(t, 3, report.Inserted)
+	require.Equal(t, []*model.ImportRowError{
+		{Row: 4, Column: "promo_code", Message: "promo code SPRING is used up by the rows above"},
+		{Row: 5, Column: "client_id", Message: "client not found"},
+	}, report.Errors)
+	require.Equal(t, merchant, *rps.deliveries[0].ClientId)
+	require.Equal(t, other, *rps.deliveries[1].ClientId)
+	require.Equal(t, merchant, *rps.deliveries[2].ClientId)
+}
+
+// TestImportHeader checks that unknown, repeated and missing columns reject the whole file
+func TestImportHeader(t *testing.T) {
+	header := strings.Split(strings.SplitN(importCSV, "\n", 2)[0], ",")
+	_, err := importHeader(header)
+	require.NoError(t, err)
+	_, err = importHeader(append(header, "colour"))
+	require.ErrorIs(t, err, model.ErrValidation)
+	_, err = importHeader(append(header, "express"))
+	require.ErrorIs(t, err, model.ErrValidation)
+	_, err = importHeader(header[1:])
+	require.ErrorIs(t, err, model.ErrValidation)
+	_, err = readSpreadsheet("ods", []byte(importCSV))
+	require.ErrorIs(t, err, model.ErrValidation)
+}
+
+// TestReadSpreadsheetXLSX checks that dates and numbers are read from an XLSX workbook regardless of their formats
+func TestReadSpreadsheetXLSX(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	start := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"window_start", "weight_kg"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{start, 1.5}))
+	buf, err := f.WriteToBuffer()
+	require.NoError(t, err)
+
+	rows, err := readSpreadsheet(model.ImportFormatXLSX, buf.Bytes())
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	parsed, err := parseImportTime(rows[1][0])
+	require.NoError(t, err)
+	require.Equal(t, start, parsed)
+	require.Equal(t, "1.5", rows[1][1])
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/liza/labwork_45/internal/model"
+)
+
+const (
+	maxDeliveryItems    = 100
+	maxItemQuantity     = 1000
+	maxItemDescription  = 200
+	maxItemDimensionCm  = 300
+	maxItemWeightKg     = 500
+	maxItemDeclaredCost = 1_000_000
+)
+
+// vehicleLimit is what one vehicle can carry at once
+type vehicleLimit struct {
+	MaxWeightKg float64
+	MaxVolumeL  float64
+	MaxSideCm   float64
+}
+
+// vehicleLimits are the carrying limits per vehicle type, a manager set weight limit applies on top
+var vehicleLimits = map[string]vehicleLimit{
+	model.VehicleFoot:    {MaxWeightKg: 10, MaxVolumeL: 30, MaxSideCm: 60},
+	model.VehicleBicycle: {MaxWeightKg: 20, MaxVolumeL: 60, MaxSideCm: 80},
+	model.VehicleScooter: {MaxWeightKg: 30, MaxVolumeL: 90, MaxSideCm: 100},
+	model.VehicleCar:     {MaxWeightKg: 300, MaxVolumeL: 1500, MaxSideCm: 200},
+}
+
+// temperatureRank orders temperature requirements from the least to the most strict
+var temperatureRank = map[string]int{
+	model.TemperatureAmbient: 0,
+	model.TemperatureChilled: 1,
+	model.TemperatureFrozen:  2,
+}
+
+// validateItem checks one item and normalizes its description and temperature
+func validateItem(i int, item *model.DeliveryItem) error {
+	item.Description = strings.TrimSpace(item.Description)
+	if item.Description == "" || len([]rune(item.Description)) > maxItemDescription {
+		return fmt.Errorf("%w: items[%d].description must be 1 to %d characters", model.ErrValidation, i, maxItemDescription)
+	}
+	if item.Quantity < 1 || item.Quantity > maxItemQuantity {
+		return fmt.Errorf("%w: items[%d].quantity must be 1 to %d", model.ErrValidation, i, maxItemQuantity)
+	}
+	if item.WeightKg <= 0 || item.WeightKg > maxItemWeightKg {
+		return fmt.Errorf("%w: items[%d].weight_kg must be above 0 and at most %d", model.ErrValidation, i, maxItemWeightKg)
+	}
+	for name, value := range map[string]float64{"length_cm": item.LengthCm, "width_cm": item.WidthCm, "height_cm": item.HeightCm} {
+		if value <= 0 || value > maxItemDimensionCm {
+			return fmt.Errorf("%w: items[%d].%s must be above 0 and at most %d", model.ErrValidation, i, name, maxItemDimensionCm)
+		}
+	}
+	if item.DeclaredValue < 0 || item.DeclaredValue > maxItemDeclaredCost {
+		return fmt.Errorf("%w: items[%d].declared_value must be 0 to %d", model.ErrValidation, i, maxItemDeclaredCost)
+	}
+	if item.Temperature == "" {
+		item.Temperature = model.TemperatureAmbient
+	}
+	if _, ok := temperatureRank[item.Temperature]; !ok {
+		return fmt.Errorf("%w: items[%d].temperature must be ambient, chilled or frozen", model.ErrValidation, i)
+	}
+	return nil
+}
+
+// validateItems checks the items of a new delivery and computes its totals,
+// without items the weight given for the delivery is kept
+func validateItems(delivery *model.Delivery) error {
+	if len(delivery.Items) > maxDeliveryItems {
+		return fmt.Errorf("%w: at most %d items are allowed", model.ErrValidation, maxDeliveryItems)
+	}
+	totals := model.DeliveryTotals{Temperature: model.TemperatureAmbient}
+	var weightKg float64
+	for i, item := range delivery.Items {
+		if item == nil {
+			return fmt.Errorf("%w: items[%d] is empty", model.ErrValidation, i)
+		}
+		err := validateItem(i, item)
+		if err != nil {
+			return err
+		}
+		quantity := float64(item.Quantity)
+		totals.ItemCount += item.Quantity
+		weightKg += quantity * item.WeightKg
+		totals.VolumeL += quantity * item.LengthCm * item.WidthCm * item.HeightCm / 1000
+		totals.MaxSideCm = math.Max(totals.MaxSideCm, math.Max(item.LengthCm, math.Max(item.WidthCm, item.HeightCm)))
+		totals.DeclaredValue += quantity * item.DeclaredValue
+		totals.Fragile = totals.Fragile || item.Fragile
+		if temperatureRank[item.Temperature] > temperatureRank[totals.Temperature] {
+			totals.Temperature = item.Temperature
+		}
+	}
+	if len(delivery.Items) > 0 {
+		delivery.WeightKg = weightKg
+	}
+	delivery.DeliveryTotals = totals
+	if !carriable(delivery.WeightKg, totals) {
+		return fmt.Errorf("%w: the delivery exceeds the weight, volume or size limits of every vehicle", model.ErrValidation)
+	}
+	return nil
+}
+
+// carriable reports whether at least one vehicle type can carry the delivery on its own
+func carriable(weightKg float64, totals model.DeliveryTotals) bool {
+	for _, limit := range vehicleLimits {
+		if totals.MaxSideCm <= limit.MaxSideCm && weightKg <= limit.MaxWeightKg && totals.VolumeL <= limit.MaxVolumeL {
+			return true
+		}
+	}
+	return false
+}
+
+// checkVehicleLimits returns model.ErrConflict when the courier's vehicle can not carry the delivery
+// together with what the courier already holds, couriers whose vehicle is not set have no vehicle limits
+func checkVehicleLimits(capacity *model.CourierCapacity, delivery *model.DeliveryGet) error {
+	limit, ok := vehicleLimits[capacity.VehicleType]
+	if !ok {
+		return nil
+	}
+	if delivery.MaxSideCm > limit.MaxSideCm {
+		return fmt.Errorf("%w: an item is too long for a %s courier", model.ErrConflict, capacity.VehicleType)
+	}
+	if capacity.ActiveWeightKg+delivery.WeightKg > limit.MaxWeightKg {
+		return fmt.Errorf("%w: delivery exceeds %s weight limit", model.ErrConflict, capacity.VehicleType)
+	}
+	if capacity.ActiveVolumeL+delivery.VolumeL > limit.MaxVolumeL {
+		return fmt.Errorf("%w: delivery exceeds %s volume limit", model.ErrConflict, capacity.VehicleType)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testItem() *model.DeliveryItem {
+	return &model.DeliveryItem{Description: " Books ", Quantity: 2, WeightKg: 1.5, LengthCm: 30, WidthCm: 20, HeightCm: 10, DeclaredValue: 12.5}
+}
+
+// TestValidateItemsTotals checks that totals are computed from quantities and the strictest temperature wins
+func TestValidateItemsTotals(t *testing.T) {
+	frozen := &model.DeliveryItem{Description: "Ice cream", Quantity: 1, WeightKg: 0.5, LengthCm: 10, WidthCm: 10, HeightCm: 45,
+		Fragile: true, Temperature: model.TemperatureFrozen}
+	delivery := &model.Delivery{WeightKg: 100, Items: []*model.DeliveryItem{testItem(), frozen}}
+
+	require.NoError(t, validateItems(delivery))
+	require.Equal(t, "Books", delivery.Items[0].Description)
+	require.Equal(t, model.TemperatureAmbient, delivery.Items[0].Temperature)
+	require.InDelta(t, 3.5, delivery.WeightKg, 1e-9)
+	require.Equal(t, 3, delivery.ItemCount)
+	require.InDelta(t, 16.5, delivery.VolumeL, 1e-9)
+	require.Equal(t, 45.0, delivery.MaxSideCm)
+	require.InDelta(t, 25, delivery.DeclaredValue, 1e-9)
+	require.True(t, delivery.Fragile)
+	require.Equal(t, model.TemperatureFrozen, delivery.Temperature)
+
+	// without items the given weight is kept
+	delivery = &model.Delivery{WeightKg: 4}
+	require.NoError(t, validateItems(delivery))
+	require.Equal(t, 4.0, delivery.WeightKg)
+	require.Equal(t, model.TemperatureAmbient, delivery.Temperature)
+}
+
+// TestValidateItemsRejects checks that invalid item data is rejected
+func TestValidateItemsRejects(t *testing.T) {
+	cases := []func(item *model.DeliveryItem){
+		func(item *model.DeliveryItem) { item.Description = "  " },
+		func(item *model.DeliveryItem) { item.Quantity = 0 },
+		func(item *model.DeliveryItem) { item.WeightKg = 0 },
+		func(item *model.DeliveryItem) { item.HeightCm = -1 },
+		func(item *model.DeliveryItem) { item.DeclaredValue = -1 },
+		func(item *model.DeliveryItem) { item.Temperature = "warm" },
+	}
+	for i, change := range cases {
+		item := testItem()
+		change(item)
+		require.ErrorIs(t, validateItems(&model.Delivery{Items: []*model.DeliveryItem{item}}), model.ErrValidation, "case %d", i)
+	}
+	require.ErrorIs(t, validateItems(&model.Delivery{Items: []*model.DeliveryItem{nil}}), model.ErrValidation)
+
+	// no vehicle can carry it
+	long := testItem()
+	long.LengthCm = 250
+	require.ErrorIs(t, validateItems(&model.Delivery{Items: []*model.DeliveryItem{long}}), model.ErrValidation)
+	require.ErrorIs(t, validateItems(&model.Delivery{WeightKg: 400}), model.ErrValidation)
+}
+
+// TestCheckVehicleLimits checks weight, volume and size limits of the courier's vehicle
+func TestCheckVehicleLimits(t *testing.T) {
+	capacity := &model.CourierCapacity{ActiveDeliveries: 1, MaxActiveDeliveries: 3, OnShift: true, VehicleType: model.VehicleBicycle,
+		ActiveWeightKg: 15, ActiveVolumeL: 40}
+
+	delivery := &model.DeliveryGet{DeliveryTotals: model.DeliveryTotals{VolumeL: 10, MaxSideCm: 50}}
+	delivery.WeightKg = 4
+	require.NoError(t, checkCourierCapacity(capacity, delivery))
+
+	delivery.WeightKg = 6
+	require.ErrorIs(t, checkCourierCapacity(capacity, delivery), model.ErrConflict)
+
+	delivery.WeightKg = 1
+	delivery.VolumeL = 25
+	require.ErrorIs(t, checkCourierCapacity(capacity, delivery), model.ErrConflict)
+
+	delivery.VolumeL = 1
+	delivery.MaxSideCm = 90
+	require.ErrorIs(t, checkCourierCapacity(capacity, delivery), model.ErrConflict)
+
+	capacity.VehicleType = model.VehicleCar
+	require.NoError(t, checkCourierCapacity(capacity, delivery))
+
+	// the vehicle of the courier is not known
+	capacity.VehicleType = ""
+	delivery.MaxSideCm = 190
+	require.NoError(t, checkCourierCapacity(capacity, delivery))
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// maxHubsPerDelivery limits how many hubs a delivery may be routed through
+const maxHubsPerDelivery = 5
+
+// planLegs splits a delivery into legs from the pickup address through the hubs to the drop-off address
+func planLegs(deliveryId uuid.UUID, hubIds []uuid.UUID, now time.Time) ([]*model.DeliveryLeg, error) {
+	if len(hubIds) == 0 || len(hubIds) > maxHubsPerDelivery {
+		return nil, fmt.Errorf("%w: a delivery is routed through 1 to %d hubs", model.ErrValidation, maxHubsPerDelivery)
+	}
+	seen := make(map[uuid.UUID]bool, len(hubIds))
+	for _, hubId := range hubIds {
+		if seen[hubId] {
+			return nil, fmt.Errorf("%w: hub %s is visited twice", model.ErrValidation, hubId)
+		}
+		seen[hubId] = true
+	}
+	legs := make([]*model.DeliveryLeg, 0, len(hubIds)+1)
+	var from *uuid.UUID
+	for i := 0; i <= len(hubIds); i++ {
+		var to *uuid.UUID
+		if i < len(hubIds) {
+			to = &hubIds[i]
+		}
+		legs = append(legs, &model.DeliveryLeg{
+			Id:         uuid.New(),
+			DeliveryId: deliveryId,
+			Position:   i,
+			FromHubId:  from,
+			ToHubId:    to,
+			Status:     model.DeliveryStatusCreated,
+			CreatedAt:  now,
+		})
+		from = to
+	}
+	return legs, nil
+}
+
+// deriveProgress derives the delivery state from its legs ordered by position: the delivery is picked up
+// with its first leg, delivered with its last one and cancelled with any of them. It is carried by the
+// courier of the first unfinished leg and waits at a hub when that leg starts at a hub and is not picked up yet
+func deriveProgress(legs []*model.DeliveryLeg) *model.LegProgress {
+	progress := &model.LegProgress{Status: model.DeliveryStatusCreated}
+	if len(legs) == 0 {
+		return progress
+	}
+	first, last := legs[0], legs[len(legs)-1]
+	progress.PickedUpAt = first.PickedUpAt
+	for _, leg := range legs {
+		if leg.Status == model.DeliveryStatusCancelled {
+			progress.Status = model.DeliveryStatusCancelled
+			return progress
+		}
+	}
+	if last.Status == model.DeliveryStatusDelivered {
+		progress.Status = model.DeliveryStatusDelivered
+		progress.CourierId = last.CourierId
+		progress.DeliveredAt = last.DeliveredAt
+		return progress
+	}
+	if first.Status != model.DeliveryStatusCreated {
+		progress.Status = model.DeliveryStatusPickedUp
+	}
+	for _, leg := range legs {
+		if leg.Status == model.DeliveryStatusDelivered {
+			continue
+		}
+		progress.CourierId = leg.CourierId
+		if leg.FromHubId != nil && leg.Status == model.DeliveryStatusCreated {
+			progress.AtHubId = leg.FromHubId
+		}
+		break
+	}
+	return progress
+}
+
+// legAtHub returns the leg whose parcel waits at the hub to be checked out, if any
+func legAtHub(legs []*model.DeliveryLeg, hubId uuid.UUID) *model.DeliveryLeg {
+	progress := deriveProgress(legs)
+	if progress.AtHubId == nil || *progress.AtHubId != hubId {
+		return nil
+	}
+	for _, leg := range legs {
+		if leg.Status == model.DeliveryStatusCreated && leg.FromHubId != nil && *leg.FromHubId == hubId {
+			return leg
+		}
+	}
+	return nil
+}
+
+// legToHub returns the picked up leg that ends at the hub, if any
+func legToHub(legs []*model.DeliveryLeg, hubId uuid.UUID) *model.DeliveryLeg {
+	for _, leg := range legs {
+		if leg.Status == model.DeliveryStatusPickedUp && leg.ToHubId != nil && *leg.ToHubId == hubId {
+			return leg
+		}
+	}
+	return nil
+}
+
+// checkFinalLeg makes sure the courier carries the parcel on the last leg of a multi-leg delivery,
+// the legs through the hubs are handed over with the leg endpoints and the hub scans
+func checkFinalLeg(legs []*model.DeliveryLeg, courierId uuid.UUID) error {
+	for _, leg := range legs {
+		if leg.ToHubId != nil || leg.Status == model.DeliveryStatusCancelled {
+			continue
+		}
+		if leg.Status != model.DeliveryStatusPickedUp || leg.CourierId == nil || *leg.CourierId != courierId {
+			return fmt.Errorf("%w: the final leg is not picked up by the courier", model.ErrConflict)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: the delivery has no open final leg", model.ErrConflict)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+type ManagerService struct {
+	rps   ManagerRepository
+	fees  CancellationFees
+	clock Clock
+}
+
+func NewManagerService(rps ManagerRepository, fees CancellationFees, clock Clock) *ManagerService {
+	return &ManagerService{rps: rps, fees: fees, clock: clock}
+}
+
+type ManagerRepository interface {
+	GetCourierRoster(ctx context.Context) ([]*model.CourierLoad, error)
+	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
+	GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error)
+	AssignDeliveryCourier(ctx context.Context, deliveryId uuid.UUID, courierId uuid.UUID, now time.Time) error
+	UnassignDeliveryCourier(ctx context.Context, deliveryId uuid.UUID, now time.Time) error
+	UpdateCourierStatus(ctx context.Context, userId uuid.UUID, status string) error
+	PatchCourier(ctx context.Context, userId uuid.UUID, patch *model.CourierPatch, currentStatus string) (*model.Courier, error)
+	CancelDelivery(ctx context.Context, cancellation *model.Cancellation, expected *model.DeliveryGet) error
+	GetCancellation(ctx context.Context, deliveryId uuid.UUID) (*model.Cancellation, error)
+}
+
+// managedCourierStatuses are the statuses a manager is allowed to set
+var managedCourierStatuses = map[string]bool{
+	model.CourierStatusActive:    true,
+	model.CourierStatusSuspended: true,
+	model.CourierStatusOffShift:  true,
+}
+
+func (srv *ManagerService) GetCourierRoster(ctx context.Context) ([]*model.CourierLoad, error) {
+	couriers, err := srv.rps.GetCourierRoster(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierRoster: %w", err)
+	}
+	return couriers, nil
+}
+
+// AssignDelivery assigns or reassigns a delivery that is not picked up yet to a courier who is not suspended,
+// its pending offers are withdrawn
+func (srv *ManagerService) AssignDelivery(ctx context.Context, assignment *model.DeliveryAssignment) error {
+	delivery, err := srv.openDelivery(ctx, assignment.DeliveryId)
+	if err != nil {
+		return err
+	}
+	if delivery.Legs > 0 {
+		return fmt.Errorf("AssignDelivery: %w: multi-leg deliveries are assigned leg by leg", model.ErrConflict)
+	}
+	courier, err := srv.rps.GetCourierByUserID(ctx, assignment.CourierUserId)
+	if err != nil {
+		return fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	if courier.Status == model.CourierStatusSuspended {
+		return fmt.Errorf("AssignDelivery: %w: courier is suspended", model.ErrConflict)
+	}
+	err = srv.rps.AssignDeliveryCourier(ctx, delivery.Id, courier.Id, srv.clock.Now())
+	if err != nil {
+		return fmt.Errorf("AssignDeliveryCourier: %w", err)
+	}
+	return nil
+}
+
+// UnassignDelivery returns a delivery that is not picked up yet to the pool, its pending offers are withdrawn
+func (srv *ManagerService) UnassignDelivery(ctx context.Context, deliveryId uuid.UUID) error {
+	delivery, err := srv.openDelivery(ctx, deliveryId)
+	if err != nil {
+		return err
+	}
+	if delivery.Legs > 0 {
+		return fmt.Errorf("UnassignDelivery: %w: multi-leg deliveries are assigned leg by leg", model.ErrConflict)
+	}
+	err = srv.rps.UnassignDeliveryCourier(ctx, delivery.Id, srv.clock.Now())
+	if err != nil {
+		return fmt.Errorf("UnassignDeliveryCourier: %w", err)
+	}
+	return nil
+}
+
+func (srv *ManagerService) SetCourierStatus(ctx context.Context, update *model.CourierStatusUpdate) error {
+	if !managedCourierStatuses[update.Status] {
+		return fmt.Errorf("%w: unknown courier status %q", model.ErrValidation, update.Status)
+	}
+	err := srv.rps.UpdateCourierStatus(ctx, update.UserId, update.Status)
+	if err != nil {
+		return fmt.Errorf("UpdateCourierStatus: %w", err)
+	}
+	return nil
+}
+
+// openDelivery returns the delivery if it is not picked up yet, a courier carrying it cannot be replaced
+func (srv *ManagerService) openDelivery(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error) {
+	delivery, err := srv.rps.GetDeliveryByID(ctx, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryByID: %w", err)
+	}
+	if delivery.DeliveryStatus != model.DeliveryStatusCreated {
+		return nil, fmt.Errorf("%w: delivery is %s", model.ErrConflict, delivery.DeliveryStatus)
+	}
+	return delivery, nil
+}
+
+// UpdateCourier lets a manager change any courier field except the computed performance indicator,
+// the status may be set to any managed status without transition rules
+func (srv *ManagerService) UpdateCourier(ctx context.Context, patch *model.CourierManagerPatch) (*model.Courier, error) {
+	err := validateCourierPatch(&patch.CourierPatch)
+	if err != nil {
+		return nil, fmt.Errorf("validateCourierPatch: %w", err)
+	}
+	if patch.Status != nil && !managedCourierStatuses[*patch.Status] {
+		return nil, fmt.Errorf("%w: unknown courier status %q", model.ErrValidation, *patch.Status)
+	}
+	courier, err := srv.rps.GetCourierByUserID(ctx, patch.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	updated, err := srv.rps.PatchCourier(ctx, patch.UserId, &patch.CourierPatch, courier.Status)
+	if err != nil {
+		return nil, fmt.Errorf("PatchCourier: %w", err)
+	}
+	return updated, nil
+}
+
+// CancelDelivery cancels any open delivery on behalf of the manager, the assigned courier is unassigned
+// and the fee for the current state is charged unless waived
+func (srv *ManagerService) CancelDelivery(ctx context.Context, managerId uuid.UUID, request *model.ManagerCancelRequest) (*model.Cancellation, error) {
+	err := validateCancelRequest(&request.CancelRequest)
+	if err != nil {
+		return nil, fmt.Errorf("validateCancelRequest: %w", err)
+	}
+	delivery, err := srv.rps.GetDeliveryByID(ctx, request.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryByID: %w", err)
+	}
+	cancellation, err := cancelDelivery(ctx, srv.rps, srv.fees, delivery, &request.CancelRequest, managerId, model.CancelledByManager, request.WaiveFee, srv.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("cancelDelivery: %w", err)
+	}
+	return cancellation, nil
+}
+
+func (srv *ManagerService) GetCancellation(ctx context.Context, deliveryId uuid.UUID) (*model.Cancellation, error) {
+	cancellation, err := srv.rps.GetCancellation(ctx, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCancellation: %w", err)
+	}
+	return cancellation, nil
+}
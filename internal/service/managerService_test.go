@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeManagerRepository keeps one delivery, its offers and couriers in memory,
+// a courier change closes pending offers the way the repository does
+type fakeManagerRepository struct {
+	ManagerRepository
+	delivery *model.DeliveryGet
+	offers   []*model.DeliveryOffer
+	couriers map[uuid.UUID]*model.Courier
+	// taken makes the next courier change fail as if a courier picked the delivery up meanwhile
+	taken bool
+}
+
+func (r *fakeManagerRepository) GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error) {
+	if r.delivery.Id != deliveryId {
+		return nil, model.ErrNotFound
+	}
+	return r.delivery, nil
+}
+
+func (r *fakeManagerRepository) GetCourierByUserID(ctx context.Context, userId uuid.UUID) (*model.Courier, error) {
+	courier, ok := r.couriers[userId]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return courier, nil
+}
+
+func (r *fakeManagerRepository) AssignDeliveryCourier(ctx context.Context, deliveryId uuid.UUID, courierId uuid.UUID, now time.Time) error {
+	return r.updateDeliveryCourier(&courierId, now)
+}
+
+func (r *fakeManagerRepository) UnassignDeliveryCourier(ctx context.Context, deliveryId uuid.UUID, now time.Time) error {
+	return r.updateDeliveryCourier(nil, now)
+}
+
+func (r *fakeManagerRepository) updateDeliveryCourier(courierId *uuid.UUID, now time.Time) error {
+	if r.taken {
+		return model.ErrConflict
+	}
+	r.delivery.CourierId = courierId
+	for _, offer := range r.offers {
+		if offer.Status == model.OfferStatusPending {
+			offer.Status, offer.RespondedAt = model.OfferStatusWithdrawn, &now
+		}
+	}
+	return nil
+}
+
+func newTestManagerRepository() *fakeManagerRepository {
+	deliveryId := uuid.New()
+	return &fakeManagerRepository{
+		delivery: &model.DeliveryGet{Id: deliveryId, DeliveryStatus: model.DeliveryStatusCreated},
+		offers:   []*model.DeliveryOffer{{Id: uuid.New(), DeliveryId: deliveryId, CourierId: uuid.New(), Status: model.OfferStatusPending}},
+		couriers: make(map[uuid.UUID]*model.Courier),
+	}
+}
+
+// TestAssignDelivery checks that assigning withdraws pending offers and that picked up deliveries,
+// suspended couriers and a delivery taken meanwhile are conflicts
+func TestAssignDelivery(t *testing.T) {
+	ctx := context.Background()
+	clock := &fixedClock{now: time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)}
+	active := &model.Courier{Id: uuid.New(), Status: model.CourierStatusActive}
+	suspended := &model.Courier{Id: uuid.New(), Status: model.CourierStatusSuspended}
+	activeUser, suspendedUser := uuid.New(), uuid.New()
+
+	newService := func() (*ManagerService, *fakeManagerRepository) {
+		repo := newTestManagerRepository()
+		repo.couriers[activeUser], repo.couriers[suspendedUser] = active, suspended
+		return NewManagerService(repo, CancellationFees{}, clock), repo
+	}
+
+	srv, repo := newService()
+	err := srv.AssignDelivery(ctx, &model.DeliveryAssignment{DeliveryId: repo.delivery.Id, CourierUserId: activeUser})
+	require.NoError(t, err)
+	require.Equal(t, active.Id, *repo.delivery.CourierId)
+	require.Equal(t, model.OfferStatusWithdrawn, repo.offers[0].Status)
+	require.Equal(t, clock.now, *repo.offers[0].RespondedAt)
+
+	for name, prepare := range map[string]func(*fakeManagerRepository) uuid.UUID{
+		"picked up": func(repo *fakeManagerRepository) uuid.UUID {
+			repo.delivery.DeliveryStatus = model.DeliveryStatusPickedUp
+			return activeUser
+		},
+		"delivered": func(repo *fakeManagerRepository) uuid.UUID {
+			repo.delivery.DeliveryStatus = model.DeliveryStatusDelivered
+			return activeUser
+		},
+		"suspended courier": func(repo *fakeManagerRepository) uuid.UUID {
+			return suspendedUser
+		},
+		"taken meanwhile": func(repo *fakeManagerRepository) uuid.UUID {
+			repo.taken = true
+			return activeUser
+		},
+	} {
+		srv, repo := newService()
+		courierUser := prepare(repo)
+		err := srv.AssignDelivery(ctx, &model.DeliveryAssignment{DeliveryId: repo.delivery.Id, CourierUserId: courierUser})
+		require.ErrorIs(t, err, model.ErrConflict, name)
+		require.Nil(t, repo.delivery.CourierId, name)
+		require.Equal(t, model.OfferStatusPending, repo.offers[0].Status, name)
+	}
+
+	srv, repo = newService()
+	err = srv.AssignDelivery(ctx, &model.DeliveryAssignment{DeliveryId: repo.delivery.Id, CourierUserId: uuid.New()})
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
+
+// TestUnassignDelivery checks that unassigning withdraws pending offers and refuses deliveries already picked up
+func TestUnassignDelivery(t *testing.T) {
+	ctx := context.Background()
+	clock := &fixedClock{now: time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)}
+	courierId := uuid.New()
+
+	repo := newTestManagerRepository()
+	repo.delivery.CourierId = &courierId
+	srv := NewManagerService(repo, CancellationFees{}, clock)
+	require.NoError(t, srv.UnassignDelivery(ctx, repo.delivery.Id))
+	require.Nil(t, repo.delivery.CourierId)
+	require.Equal(t, model.OfferStatusWithdrawn, repo.offers[0].Status)
+
+	repo = newTestManagerRepository()
+	repo.delivery.CourierId, repo.delivery.DeliveryStatus = &courierId, model.DeliveryStatusPickedUp
+	srv = NewManagerService(repo, CancellationFees{}, clock)
+	require.ErrorIs(t, srv.UnassignDelivery(ctx, repo.delivery.Id), model.ErrConflict)
+	require.Equal(t, courierId, *repo.delivery.CourierId)
+	require.Equal(t, model.OfferStatusPending, repo.offers[0].Status)
+
+	repo = newTestManagerRepository()
+	repo.delivery.CourierId, repo.taken = &courierId, true
+	srv = NewManagerService(repo, CancellationFees{}, clock)
+	require.ErrorIs(t, srv.UnassignDelivery(ctx, repo.delivery.Id), model.ErrConflict)
+	require.Equal(t, courierId, *repo.delivery.CourierId)
+}
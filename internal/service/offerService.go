@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// offerMetricsWindow is how far back acceptance metrics look
+const offerMetricsWindow = 30 * 24 * time.Hour
+
+type OfferService struct {
+	rps        OfferRepository
+	dispatcher DeliveryDispatcher
+	clock      Clock
+}
+
+func NewOfferService(rps OfferRepository, dispatcher DeliveryDispatcher, clock Clock) *OfferService {
+	return &OfferService{rps: rps, dispatcher: dispatcher, clock: clock}
+}
+
+type OfferRepository interface {
+	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
+	GetPendingOffersByCourier(ctx context.Context, courierId uuid.UUID, now time.Time) ([]*model.OfferView, error)
+	AcceptOffer(ctx context.Context, offerId uuid.UUID, courierId uuid.UUID, now time.Time, check func(*model.CourierCapacity, *model.DeliveryGet) error) error
+	DeclineOffer(ctx context.Context, offerId uuid.UUID, courierId uuid.UUID, now time.Time) (uuid.UUID, error)
+	GetOfferMetrics(ctx context.Context, since time.Time) ([]*model.OfferMetrics, error)
+}
+
+// DeliveryDispatcher passes a delivery on to the next candidate
+type DeliveryDispatcher interface {
+	Dispatch(ctx context.Context, deliveryId uuid.UUID) (*model.DispatchResult, error)
+}
+
+func (srv *OfferService) GetPendingOffers(ctx context.Context, userId uuid.UUID) ([]*model.OfferView, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	offers, err := srv.rps.GetPendingOffersByCourier(ctx, courier.Id, srv.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("GetPendingOffersByCourier: %w", err)
+	}
+	return offers, nil
+}
+
+// AcceptOffer assigns the offered delivery to the courier if the offer has not expired and they still have room for it
+func (srv *OfferService) AcceptOffer(ctx context.Context, userId uuid.UUID, offerId uuid.UUID) error {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	err = srv.rps.AcceptOffer(ctx, offerId, courier.Id, srv.clock.Now(), checkCourierCapacity)
+	if err != nil {
+		return fmt.Errorf("AcceptOffer: %w", err)
+	}
+	return nil
+}
+
+// DeclineOffer closes the offer and immediately offers the delivery to the next candidate
+func (srv *OfferService) DeclineOffer(ctx context.Context, userId uuid.UUID, offerId uuid.UUID) error {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	deliveryId, err := srv.rps.DeclineOffer(ctx, offerId, courier.Id, srv.clock.Now())
+	if err != nil {
+		return fmt.Errorf("DeclineOffer: %w", err)
+	}
+	// the dispatcher worker retries later if this fails
+	_, err = srv.dispatcher.Dispatch(ctx, deliveryId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"deliveryId": deliveryId}).Errorf("Dispatch: %v", err)
+	}
+	return nil
+}
+
+// GetOfferMetrics returns per-courier offer statistics for the last 30 days
+func (srv *OfferService) GetOfferMetrics(ctx context.Context) ([]*model.OfferMetrics, error) {
+	metrics, err := srv.rps.GetOfferMetrics(ctx, srv.clock.Now().Add(-offerMetricsWindow))
+	if err != nil {
+		return nil, fmt.Errorf("GetOfferMetrics: %w", err)
+	}
+	for _, m := range metrics {
+		closed := m.Accepted + m.Declined + m.Expired
+		if closed > 0 {
+			m.AcceptanceRate = float64(m.Accepted) / float64(closed)
+		}
+	}
+	return metrics, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOfferRepository answers offer queries from memory
+type fakeOfferRepository struct {
+	courier    *model.Courier
+	deliveryId uuid.UUID
+	metrics    []*model.OfferMetrics
+	since      time.Time
+}
+
+func (r *fakeOfferRepository) GetCourierByUserID(ctx context.Context, userId uuid.UUID) (*model.Courier, error) {
+	return r.courier, nil
+}
+
+func (r *fakeOfferRepository) GetPendingOffersByCourier(ctx context.Context, courierId uuid.UUID, now time.Time) ([]*model.OfferView, error) {
+	return nil, nil
+}
+
+func (r *fakeOfferRepository) AcceptOffer(ctx context.Context, offerId uuid.UUID, courierId uuid.UUID, now time.Time,
+	check func(*model.CourierCapacity, *model.DeliveryGet) error) error {
+	return nil
+}
+
+func (r *fakeOfferRepository) DeclineOffer(ctx context.Context, offerId uuid.UUID, courierId uuid.UUID, now time.Time) (uuid.UUID, error) {
+	return r.deliveryId, nil
+}
+
+func (r *fakeOfferRepository) GetOfferMetrics(ctx context.Context, since time.Time) ([]*model.OfferMetrics, error) {
+	r.since = since
+	return r.metrics, nil
+}
+
+// fakeDispatcher records dispatched deliveries
+type fakeDispatcher struct {
+	dispatched []uuid.UUID
+}
+
+func (d *fakeDispatcher) Dispatch(ctx context.Context, deliveryId uuid.UUID) (*model.DispatchResult, error) {
+	d.dispatched = append(d.dispatched, deliveryId)
+	return &model.DispatchResult{DeliveryId: deliveryId, Outcome: model.DispatchOffered}, nil
+}
+
+// TestDeclineOfferCascades checks that a declined delivery goes straight to the next candidate
+func TestDeclineOfferCascades(t *testing.T) {
+	rps := &fakeOfferRepository{courier: &model.Courier{Id: uuid.New()}, deliveryId: uuid.New()}
+	dispatcher := &fakeDispatcher{}
+	srv := NewOfferService(rps, dispatcher, &fixedClock{now: time.Now()})
+
+	err := srv.DeclineOffer(context.Background(), uuid.New(), uuid.New())
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{rps.deliveryId}, dispatcher.dispatched)
+}
+
+// TestGetOfferMetrics checks the acceptance rate and the metrics window
+func TestGetOfferMetrics(t *testing.T) {
+	now := time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)
+	rps := &fakeOfferRepository{metrics: []*model.OfferMetrics{
+		{Offered: 5, Accepted: 3, Declined: 1, Expired: 0},
+		{Offered: 1},
+	}}
+	srv := NewOfferService(rps, &fakeDispatcher{}, &fixedClock{now: now})
+
+	metrics, err := srv.GetOfferMetrics(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, now.Add(-offerMetricsWindow), rps.since)
+	require.InDelta(t, 0.75, metrics[0].AcceptanceRate, 1e-9)
+	require.Zero(t, metrics[1].AcceptanceRate)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// Weights of the performance components, components without data are left out and the rest rescaled
+const (
+	onTimeWeight      = 0.35
+	reliabilityWeight = 0.25
+	acceptanceWeight  = 0.2
+	ratingWeight      = 0.2
+)
+
+// neutralPerformance is given to couriers with no measurable outcomes yet
+const neutralPerformance = 50
+
+type PerformanceService struct {
+	rps    PerformanceRepository
+	clock  Clock
+	window time.Duration
+}
+
+func NewPerformanceService(rps PerformanceRepository, clock Clock, window time.Duration) *PerformanceService {
+	return &PerformanceService{rps: rps, clock: clock, window: window}
+}
+
+type PerformanceRepository interface {
+	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
+	GetPerformanceStats(ctx context.Context, since time.Time) ([]*model.PerformanceStats, error)
+	SavePerformance(ctx context.Context, breakdowns []*model.PerformanceBreakdown) error
+	GetPerformanceBreakdown(ctx context.Context, courierId uuid.UUID) (*model.PerformanceBreakdown, error)
+}
+
+// rate returns part/total or nil when there is nothing to measure
+func rate(part int, total int) *float64 {
+	if total == 0 {
+		return nil
+	}
+	r := float64(part) / float64(total)
+	return &r
+}
+
+// computePerformance turns raw outcomes into a 0..100 indicator
+func computePerformance(stats *model.PerformanceStats) *model.PerformanceBreakdown {
+	b := &model.PerformanceBreakdown{
+		CourierId:      stats.CourierId,
+		OnTimeRate:     rate(stats.OnTime, stats.Completed-stats.Failed),
+		FailedRate:     rate(stats.Failed, stats.Completed),
+		AcceptanceRate: rate(stats.Accepted, stats.Offered),
+		AverageRating:  stats.AverageRating,
+		Completed:      stats.Completed,
+		OnTime:         stats.OnTime,
+		Failed:         stats.Failed,
+		Offered:        stats.Offered,
+		Accepted:       stats.Accepted,
+		Ratings:        stats.Ratings,
+	}
+	var score, weight float64
+	if b.OnTimeRate != nil {
+		score += onTimeWeight * *b.OnTimeRate
+		weight += onTimeWeight
+	}
+	if b.FailedRate != nil {
+		score += reliabilityWeight * (1 - *b.FailedRate)
+		weight += reliabilityWeight
+	}
+	if b.AcceptanceRate != nil {
+		score += acceptanceWeight * *b.AcceptanceRate
+		weight += acceptanceWeight
+	}
+	if b.AverageRating != nil && stats.Ratings > 0 {
+		score += ratingWeight * (*b.AverageRating - 1) / 4
+		weight += ratingWeight
+	}
+	b.Indicator = neutralPerformance
+	if weight > 0 {
+		b.Indicator = int(math.Round(100 * score / weight))
+	}
+	return b
+}
+
+// Recalculate updates the performance indicator of every courier from the rolling window
+func (srv *PerformanceService) Recalculate(ctx context.Context) error {
+	now := srv.clock.Now()
+	since := now.Add(-srv.window)
+	stats, err := srv.rps.GetPerformanceStats(ctx, since)
+	if err != nil {
+		return fmt.Errorf("GetPerformanceStats: %w", err)
+	}
+	breakdowns := make([]*model.PerformanceBreakdown, 0, len(stats))
+	for _, s := range stats {
+		b := computePerformance(s)
+		b.WindowStart, b.CalculatedAt = since, now
+		breakdowns = append(breakdowns, b)
+	}
+	err = srv.rps.SavePerformance(ctx, breakdowns)
+	if err != nil {
+		return fmt.Errorf("SavePerformance: %w", err)
+	}
+	return nil
+}
+
+// Run recalculates right away and then every interval until ctx is cancelled
+func (srv *PerformanceService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := srv.Recalculate(ctx)
+		if err != nil {
+			logrus.Errorf("Recalculate: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetPerformance returns the last calculated breakdown of the courier with the given user id
+func (srv *PerformanceService) GetPerformance(ctx context.Context, userId uuid.UUID) (*model.PerformanceBreakdown, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	breakdown, err := srv.rps.GetPerformanceBreakdown(ctx, courier.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GetPerformanceBreakdown: %w", err)
+	}
+	return breakdown, nil
+}
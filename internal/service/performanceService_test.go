@@ -0,0 +1,25 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestComputePerformance checks the weighting and that missing components are left out
+func TestComputePerformance(t *testing.T) {
+	empty := computePerformance(&model.PerformanceStats{})
+	require.Equal(t, neutralPerformance, empty.Indicator)
+	require.Nil(t, empty.OnTimeRate)
+
+	perfect := computePerformance(&model.PerformanceStats{Completed: 10, OnTime: 10, Offered: 4, Accepted: 4})
+	require.Equal(t, 100, perfect.Indicator)
+
+	// 8 delivered of which 4 on time, 2 failed, half of offers accepted
+	mixed := computePerformance(&model.PerformanceStats{Completed: 10, OnTime: 4, Failed: 2, Offered: 10, Accepted: 5})
+	require.InDelta(t, 0.5, *mixed.OnTimeRate, 1e-9)
+	require.InDelta(t, 0.2, *mixed.FailedRate, 1e-9)
+	// (0.35*0.5 + 0.25*0.8 + 0.2*0.5) / 0.8
+	require.Equal(t, 59, mixed.Indicator)
+}
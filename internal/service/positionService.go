@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxLocationBatch limits the number of fixes in one upload
+	maxLocationBatch = 500
+	// maxClockSkew is how far in the future a device timestamp may be
+	maxClockSkew = 2 * time.Minute
+	// a fix is added to a delivery track when it is trackMinInterval after
+	// or trackMinDistanceKm away from the previous track point
+	trackMinInterval   = 30 * time.Second
+	trackMinDistanceKm = 0.05
+)
+
+type PositionService struct {
+	rps       PositionRepository
+	eta       EtaRecomputer
+	clock     Clock
+	retention time.Duration
+}
+
+func NewPositionService(rps PositionRepository, eta EtaRecomputer, clock Clock, retention time.Duration) *PositionService {
+	return &PositionService{rps: rps, eta: eta, clock: clock, retention: retention}
+}
+
+// EtaRecomputer refreshes ETAs of a courier's deliveries after their position changed
+type EtaRecomputer interface {
+	RecomputeCourier(ctx context.Context, courierId uuid.UUID) error
+}
+
+type PositionRepository interface {
+	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
+	GetTrackTails(ctx context.Context, courierId uuid.UUID) (map[uuid.UUID]*model.TrackPoint, error)
+	SaveLocationBatch(ctx context.Context, courierId uuid.UUID, fixes []model.LocationFix, track []*model.TrackPoint, receivedAt time.Time) error
+	GetCourierPositions(ctx context.Context) ([]*model.CourierPosition, error)
+	GetDeliveryTrack(ctx context.Context, deliveryId uuid.UUID) ([]*model.TrackPoint, error)
+	PruneLocationFixes(ctx context.Context, before time.Time) (int64, error)
+}
+
+// validFix reports whether a fix has sane coordinates and a timestamp within the retention period
+func (srv *PositionService) validFix(fix *model.LocationFix, now time.Time) bool {
+	if fix.Lat < -90 || fix.Lat > 90 || fix.Lon < -180 || fix.Lon > 180 || (fix.Lat == 0 && fix.Lon == 0) {
+		return false
+	}
+	if (fix.AccuracyM != nil && *fix.AccuracyM < 0) || (fix.SpeedMps != nil && *fix.SpeedMps < 0) {
+		return false
+	}
+	if fix.RecordedAt.IsZero() || fix.RecordedAt.After(now.Add(maxClockSkew)) || fix.RecordedAt.Before(now.Add(-srv.retention)) {
+		return false
+	}
+	fix.RecordedAt = fix.RecordedAt.UTC()
+	return true
+}
+
+// downsample picks the fixes that extend each delivery track, fixes must be sorted by time
+// and those older than the track tail are skipped
+func downsample(tails map[uuid.UUID]*model.TrackPoint, fixes []model.LocationFix) []*model.TrackPoint {
+	var track []*model.TrackPoint
+	for deliveryId, tail := range tails {
+		for _, fix := range fixes {
+			if tail != nil {
+				if !fix.RecordedAt.After(tail.RecordedAt) {
+					continue
+				}
+				if fix.RecordedAt.Sub(tail.RecordedAt) < trackMinInterval &&
+					haversineKm(model.GeoPoint{Lat: tail.Lat, Lon: tail.Lon}, fix.Point()) < trackMinDistanceKm {
+					continue
+				}
+			}
+			tail = &model.TrackPoint{DeliveryId: deliveryId, Lat: fix.Lat, Lon: fix.Lon, RecordedAt: fix.RecordedAt}
+			track = append(track, tail)
+		}
+	}
+	return track
+}
+
+// IngestFixes stores a batch of fixes from the courier app. Invalid fixes are rejected one by one,
+// repeated fixes are accepted again without effect so that the app can safely retry an upload
+func (srv *PositionService) IngestFixes(ctx context.Context, userId uuid.UUID, batch *model.LocationBatch) (*model.LocationBatchResult, error) {
+	if len(batch.Fixes) == 0 || len(batch.Fixes) > maxLocationBatch {
+		return nil, fmt.Errorf("%w: a batch must contain 1 to %d fixes", model.ErrValidation, maxLocationBatch)
+	}
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	now := srv.clock.Now()
+	result := &model.LocationBatchResult{}
+	fixes := make([]model.LocationFix, 0, len(batch.Fixes))
+	seen := make(map[time.Time]bool, len(batch.Fixes))
+	for _, fix := range batch.Fixes {
+		if !srv.validFix(&fix, now) {
+			result.Rejected++
+			continue
+		}
+		result.Accepted++
+		if !seen[fix.RecordedAt] {
+			seen[fix.RecordedAt] = true
+			fixes = append(fixes, fix)
+		}
+	}
+	sort.Slice(fixes, func(i, j int) bool { return fixes[i].RecordedAt.Before(fixes[j].RecordedAt) })
+
+	tails, err := srv.rps.GetTrackTails(ctx, courier.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GetTrackTails: %w", err)
+	}
+	err = srv.rps.SaveLocationBatch(ctx, courier.Id, fixes, downsample(tails, fixes), now)
+	if err != nil {
+		return nil, fmt.Errorf("SaveLocationBatch: %w", err)
+	}
+	// the fixes are stored, a failed estimate is corrected by the next upload
+	err = srv.eta.RecomputeCourier(ctx, courier.Id)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"courierId": courier.Id}).Errorf("RecomputeCourier: %v", err)
+	}
+	return result, nil
+}
+
+func (srv *PositionService) GetCourierPositions(ctx context.Context) ([]*model.CourierPosition, error) {
+	positions, err := srv.rps.GetCourierPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierPositions: %w", err)
+	}
+	return positions, nil
+}
+
+func (srv *PositionService) GetDeliveryTrack(ctx context.Context, deliveryId uuid.UUID) ([]*model.TrackPoint, error) {
+	track, err := srv.rps.GetDeliveryTrack(ctx, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryTrack: %w", err)
+	}
+	return track, nil
+}
+
+// Run prunes raw fixes older than the retention period every interval until ctx is cancelled
+func (srv *PositionService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruned, err := srv.rps.PruneLocationFixes(ctx, srv.clock.Now().Add(-srv.retention))
+			if err != nil {
+				logrus.Errorf("PruneLocationFixes: %v", err)
+				continue
+			}
+			logrus.WithFields(logrus.Fields{"pruned": pruned}).Debug("PruneLocationFixes")
+		}
+	}
+}
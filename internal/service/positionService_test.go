@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDownsample checks that close fixes are dropped and fixes older than the track are ignored
+func TestDownsample(t *testing.T) {
+	start := time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)
+	withTrack, withoutTrack := uuid.New(), uuid.New()
+	tails := map[uuid.UUID]*model.TrackPoint{
+		withTrack:    {DeliveryId: withTrack, Lat: 53.9, Lon: 27.56, RecordedAt: start},
+		withoutTrack: nil,
+	}
+	fixes := []model.LocationFix{
+		{Lat: 53.9, Lon: 27.56, RecordedAt: start.Add(-time.Minute)},
+		{Lat: 53.9001, Lon: 27.56, RecordedAt: start.Add(10 * time.Second)},
+		{Lat: 53.9002, Lon: 27.56, RecordedAt: start.Add(40 * time.Second)},
+		{Lat: 53.91, Lon: 27.56, RecordedAt: start.Add(45 * time.Second)},
+	}
+
+	counts := make(map[uuid.UUID]int)
+	for _, point := range downsample(tails, fixes) {
+		counts[point.DeliveryId]++
+	}
+	// the old fix and the close 10 s one are skipped, the 45 s one is kept because it moved more than 50 m
+	require.Equal(t, 2, counts[withTrack])
+	// a new track starts from the oldest fix and every following fix is either late or far enough
+	require.Equal(t, 4, counts[withoutTrack])
+}
+
+// TestValidFix checks coordinate and timestamp limits
+func TestValidFix(t *testing.T) {
+	now := time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)
+	srv := NewPositionService(nil, nil, &fixedClock{now: now}, 24*time.Hour)
+	negative := -1.0
+
+	require.True(t, srv.validFix(&model.LocationFix{Lat: 53.9, Lon: 27.56, RecordedAt: now}, now))
+	require.False(t, srv.validFix(&model.LocationFix{Lat: 91, Lon: 27.56, RecordedAt: now}, now))
+	require.False(t, srv.validFix(&model.LocationFix{Lat: 53.9, Lon: 27.56, RecordedAt: now.Add(time.Hour)}, now))
+	require.False(t, srv.validFix(&model.LocationFix{Lat: 53.9, Lon: 27.56, RecordedAt: now.Add(-48 * time.Hour)}, now))
+	require.False(t, srv.validFix(&model.LocationFix{Lat: 53.9, Lon: 27.56, SpeedMps: &negative, RecordedAt: now}, now))
+}
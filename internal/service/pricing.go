@@ -0,0 +1,143 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// pricingInput is what the tariff is applied to
+type pricingInput struct {
+	DistanceKm  float64
+	WeightKg    float64
+	Express     bool
+	Fragile     bool
+	WindowStart *time.Time
+}
+
+// roundCents rounds an amount to whole cents
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// nightHour reports whether the hour falls into the night period, which may wrap past midnight,
+// equal start and end hours mean the tariff has no night period
+func nightHour(hour int, start int, end int) bool {
+	if start == end {
+		return false
+	}
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// applyTariff prices a delivery: base plus per-km plus per-kg, percentage surcharges are charged
+// on that sum, the fragile surcharge is flat, the minimum price applies before the promo discount
+func applyTariff(tariff *model.Tariff, input pricingInput) (*model.Quote, error) {
+	quote := &model.Quote{
+		TariffId:      tariff.Id,
+		Zone:          tariff.Zone,
+		TariffVersion: tariff.Version,
+		DistanceKm:    roundCents(input.DistanceKm),
+		WeightKg:      input.WeightKg,
+		BaseFee:       roundCents(tariff.BaseFee),
+		DistanceFee:   roundCents(input.DistanceKm * tariff.PerKm),
+		WeightFee:     roundCents(input.WeightKg * tariff.PerKg),
+	}
+	subtotal := quote.BaseFee + quote.DistanceFee + quote.WeightFee
+	if input.Express {
+		quote.ExpressSurcharge = roundCents(subtotal * tariff.ExpressPct / 100)
+	}
+	if input.Fragile {
+		quote.FragileSurcharge = roundCents(tariff.FragileFee)
+	}
+	if input.WindowStart != nil {
+		location, err := time.LoadLocation(tariff.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("LoadLocation: %w", err)
+		}
+		if nightHour(input.WindowStart.In(location).Hour(), tariff.NightStartHour, tariff.NightEndHour) {
+			quote.NightSurcharge = roundCents(subtotal * tariff.NightPct / 100)
+		}
+	}
+	quote.Total = roundCents(math.Max(subtotal+quote.ExpressSurcharge+quote.FragileSurcharge+quote.NightSurcharge, tariff.MinPrice))
+	return quote, nil
+}
+
+// applyPromo takes the promo discount off the quote, the total never becomes negative
+func applyPromo(quote *model.Quote, promo *model.Promo) {
+	discount := promo.AmountOff
+	if promo.PercentOff > 0 {
+		discount = quote.Total * promo.PercentOff / 100
+	}
+	quote.Discount = roundCents(math.Min(discount, quote.Total))
+	quote.PromoCode = promo.Code
+	quote.Total = roundCents(quote.Total - quote.Discount)
+}
+
+// checkPromo returns model.ErrValidation when the promo can not be used at the given moment
+func checkPromo(promo *model.Promo, now time.Time) error {
+	if promo.ValidFrom != nil && now.Before(*promo.ValidFrom) {
+		return fmt.Errorf("%w: promo code %s is not valid yet", model.ErrValidation, promo.Code)
+	}
+	if promo.ValidTo != nil && !now.Before(*promo.ValidTo) {
+		return fmt.Errorf("%w: promo code %s has expired", model.ErrValidation, promo.Code)
+	}
+	if promo.MaxUses != nil && promo.Used >= *promo.MaxUses {
+		return fmt.Errorf("%w: promo code %s is used up", model.ErrValidation, promo.Code)
+	}
+	return nil
+}
+
+// validateTariff checks a new tariff version and normalizes its zone and time zone
+func validateTariff(tariff *model.Tariff) error {
+	tariff.Zone = strings.TrimSpace(tariff.Zone)
+	if tariff.Zone == "" {
+		return fmt.Errorf("%w: zone is required, use %q for the default tariff", model.ErrValidation, model.DefaultTariffZone)
+	}
+	for name, value := range map[string]float64{
+		"base_fee": tariff.BaseFee, "per_km": tariff.PerKm, "per_kg": tariff.PerKg, "min_price": tariff.MinPrice,
+		"express_pct": tariff.ExpressPct, "fragile_fee": tariff.FragileFee, "night_pct": tariff.NightPct,
+	} {
+		if value < 0 {
+			return fmt.Errorf("%w: %s must not be negative", model.ErrValidation, name)
+		}
+	}
+	if tariff.NightStartHour < 0 || tariff.NightStartHour > 23 || tariff.NightEndHour < 0 || tariff.NightEndHour > 23 {
+		return fmt.Errorf("%w: night hours must be between 0 and 23", model.ErrValidation)
+	}
+	if tariff.TimeZone == "" {
+		tariff.TimeZone = "UTC"
+	}
+	_, err := time.LoadLocation(tariff.TimeZone)
+	if err != nil {
+		return fmt.Errorf("%w: unknown time_zone %q", model.ErrValidation, tariff.TimeZone)
+	}
+	return nil
+}
+
+// validatePromo checks a new promo code and normalizes it to upper case
+func validatePromo(promo *model.Promo) error {
+	promo.Code = strings.ToUpper(strings.TrimSpace(promo.Code))
+	if promo.Code == "" || len(promo.Code) > 32 {
+		return fmt.Errorf("%w: code must be 1 to 32 characters", model.ErrValidation)
+	}
+	if (promo.PercentOff > 0) == (promo.AmountOff > 0) {
+		return fmt.Errorf("%w: exactly one of percent_off and amount_off must be set", model.ErrValidation)
+	}
+	if promo.PercentOff < 0 || promo.PercentOff > 100 || promo.AmountOff < 0 {
+		return fmt.Errorf("%w: percent_off must be 0 to 100 and amount_off not negative", model.ErrValidation)
+	}
+	if promo.ValidFrom != nil && promo.ValidTo != nil && !promo.ValidTo.After(*promo.ValidFrom) {
+		return fmt.Errorf("%w: valid_to must be after valid_from", model.ErrValidation)
+	}
+	if promo.MaxUses != nil && *promo.MaxUses < 1 {
+		return fmt.Errorf("%w: max_uses must be positive", model.ErrValidation)
+	}
+	promo.Used = 0
+	return nil
+}
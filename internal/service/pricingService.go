@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+type PricingService struct {
+	rps      PricingRepository
+	distance Distancer
+	clock    Clock
+	zones    ZoneLocator
+}
+
+func NewPricingService(rps PricingRepository, distance Distancer, clock Clock, zones ZoneLocator) *PricingService {
+	return &PricingService{rps: rps, distance: distance, clock: clock, zones: zones}
+}
+
+type PricingRepository interface {
+	GetActiveTariff(ctx context.Context, zone string, at time.Time) (*model.Tariff, error)
+	InsertTariff(ctx context.Context, tariff *model.Tariff) error
+	GetTariffs(ctx context.Context, zone string) ([]*model.Tariff, error)
+	GetPromo(ctx context.Context, code string) (*model.Promo, error)
+	InsertPromo(ctx context.Context, promo *model.Promo) error
+	GetDeliveryPrice(ctx context.Context, deliveryId uuid.UUID) (*model.Quote, error)
+}
+
+// DeliveryPricer freezes the price of a validated delivery before it is inserted
+type DeliveryPricer interface {
+	PriceDelivery(ctx context.Context, delivery *model.Delivery) error
+}
+
+// quote prices a validated delivery with the tariff of the service zone of its pickup point and sets the zone,
+// deliveries outside every service zone use the tariff of their drop-off city. The zone has to be open
+// at the start of the delivery window, or now when there is no window
+func (srv *PricingService) quote(ctx context.Context, delivery *model.Delivery) (*model.Quote, error) {
+	now := srv.clock.Now()
+	tariffZone := delivery.Dropoff.City
+	zone, err := srv.zones.Locate(ctx, delivery.Pickup.Point())
+	if err != nil {
+		return nil, fmt.Errorf("Locate: %w", err)
+	}
+	delivery.ZoneId = nil
+	if zone != nil {
+		at := now
+		if delivery.WindowStart != nil {
+			at = *delivery.WindowStart
+		}
+		open, err := zoneOpen(zone, at)
+		if err != nil {
+			return nil, fmt.Errorf("zoneOpen: %w", err)
+		}
+		if !open {
+			return nil, fmt.Errorf("%w: zone %s is closed at %s", model.ErrConflict, zone.Code, at.UTC().Format(time.RFC3339))
+		}
+		tariffZone = zone.Code
+		delivery.ZoneId = &zone.Id
+	}
+	tariff, err := srv.rps.GetActiveTariff(ctx, tariffZone, now)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil, fmt.Errorf("%w: no tariff for zone %s", model.ErrConflict, tariffZone)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetActiveTariff: %w", err)
+	}
+	quote, err := applyTariff(tariff, pricingInput{
+		DistanceKm:  srv.distance.DistanceKm(delivery.Pickup.Point(), delivery.Dropoff.Point()),
+		WeightKg:    delivery.WeightKg,
+		Express:     delivery.Express,
+		Fragile:     delivery.Fragile,
+		WindowStart: delivery.WindowStart,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("applyTariff: %w", err)
+	}
+	delivery.PromoCode = strings.ToUpper(strings.TrimSpace(delivery.PromoCode))
+	if delivery.PromoCode != "" {
+		promo, err := srv.rps.GetPromo(ctx, delivery.PromoCode)
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, fmt.Errorf("%w: unknown promo code %s", model.ErrValidation, delivery.PromoCode)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("GetPromo: %w", err)
+		}
+		err = checkPromo(promo, now)
+		if err != nil {
+			return nil, err
+		}
+		applyPromo(quote, promo)
+	}
+	quote.QuotedAt = now
+	return quote, nil
+}
+
+// Quote prices a delivery without creating it, the promo code is checked but not used up
+func (srv *PricingService) Quote(ctx context.Context, request *model.QuoteRequest) (*model.Quote, error) {
+	delivery := &model.Delivery{Pickup: request.Pickup, Dropoff: request.Dropoff, WeightKg: request.WeightKg, Items: request.Items,
+		Express: request.Express, WindowStart: request.WindowStart, PromoCode: request.PromoCode}
+	err := validateLocation("pickup", &delivery.Pickup)
+	if err != nil {
+		return nil, fmt.Errorf("validateLocation: %w", err)
+	}
+	err = validateLocation("dropoff", &delivery.Dropoff)
+	if err != nil {
+		return nil, fmt.Errorf("validateLocation: %w", err)
+	}
+	if delivery.WeightKg < 0 {
+		return nil, fmt.Errorf("%w: weight_kg must not be negative", model.ErrValidation)
+	}
+	err = validateItems(delivery)
+	if err != nil {
+		return nil, fmt.Errorf("validateItems: %w", err)
+	}
+	quote, err := srv.quote(ctx, delivery)
+	if err != nil {
+		return nil, fmt.Errorf("quote: %w", err)
+	}
+	return quote, nil
+}
+
+// PriceDelivery sets the price of a validated delivery, the promo code is used up when the delivery is inserted
+func (srv *PricingService) PriceDelivery(ctx context.Context, delivery *model.Delivery) error {
+	quote, err := srv.quote(ctx, delivery)
+	if err != nil {
+		return fmt.Errorf("quote: %w", err)
+	}
+	delivery.Price = quote
+	return nil
+}
+
+// CreateTariff adds a new version of the zone's tariff, it applies from ValidFrom or immediately when unset
+func (srv *PricingService) CreateTariff(ctx context.Context, tariff *model.Tariff) (*model.Tariff, error) {
+	err := validateTariff(tariff)
+	if err != nil {
+		return nil, fmt.Errorf("validateTariff: %w", err)
+	}
+	tariff.Id = uuid.New()
+	tariff.CreatedAt = srv.clock.Now()
+	if tariff.ValidFrom.IsZero() {
+		tariff.ValidFrom = tariff.CreatedAt
+	}
+	tariff.ValidFrom = tariff.ValidFrom.UTC()
+	err = srv.rps.InsertTariff(ctx, tariff)
+	if err != nil {
+		return nil, fmt.Errorf("InsertTariff: %w", err)
+	}
+	return tariff, nil
+}
+
+// GetTariffs returns all versions of the zone's tariff, or of every zone when zone is empty
+func (srv *PricingService) GetTariffs(ctx context.Context, zone string) ([]*model.Tariff, error) {
+	tariffs, err := srv.rps.GetTariffs(ctx, strings.TrimSpace(zone))
+	if err != nil {
+		return nil, fmt.Errorf("GetTariffs: %w", err)
+	}
+	return tariffs, nil
+}
+
+func (srv *PricingService) CreatePromo(ctx context.Context, promo *model.Promo) (*model.Promo, error) {
+	err := validatePromo(promo)
+	if err != nil {
+		return nil, fmt.Errorf("validatePromo: %w", err)
+	}
+	err = srv.rps.InsertPromo(ctx, promo)
+	if err != nil {
+		return nil, fmt.Errorf("InsertPromo: %w", err)
+	}
+	return promo, nil
+}
+
+func (srv *PricingService) GetDeliveryPrice(ctx context.Context, deliveryId uuid.UUID) (*model.Quote, error) {
+	quote, err := srv.rps.GetDeliveryPrice(ctx, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryPrice: %w", err)
+	}
+	return quote, nil
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testTariff() *model.Tariff {
+	return &model.Tariff{Id: uuid.New(), Zone: model.DefaultTariffZone, Version: 1, BaseFee: 3, PerKm: 1, PerKg: 0.5, MinPrice: 5,
+		ExpressPct: 50, FragileFee: 2, NightPct: 25, NightStartHour: 22, NightEndHour: 6, TimeZone: "UTC"}
+}
+
+// TestApplyTariff checks the price components and surcharges
+func TestApplyTariff(t *testing.T) {
+	night := time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC)
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	quote, err := applyTariff(testTariff(), pricingInput{DistanceKm: 10, WeightKg: 4, WindowStart: &day})
+	require.NoError(t, err)
+	require.Equal(t, 3.0, quote.BaseFee)
+	require.Equal(t, 10.0, quote.DistanceFee)
+	require.Equal(t, 2.0, quote.WeightFee)
+	require.Zero(t, quote.NightSurcharge)
+	require.Equal(t, 15.0, quote.Total)
+
+	quote, err = applyTariff(testTariff(), pricingInput{DistanceKm: 10, WeightKg: 4, Express: true, Fragile: true, WindowStart: &night})
+	require.NoError(t, err)
+	require.Equal(t, 7.5, quote.ExpressSurcharge)
+	require.Equal(t, 2.0, quote.FragileSurcharge)
+	require.Equal(t, 3.75, quote.NightSurcharge)
+	require.Equal(t, 28.25, quote.Total)
+
+	// short light deliveries are charged the minimum price
+	quote, err = applyTariff(testTariff(), pricingInput{DistanceKm: 0.5, WeightKg: 1})
+	require.NoError(t, err)
+	require.Equal(t, 5.0, quote.Total)
+}
+
+// TestNightHour checks night periods within a day and across midnight
+func TestNightHour(t *testing.T) {
+	require.True(t, nightHour(23, 22, 6))
+	require.True(t, nightHour(2, 22, 6))
+	require.False(t, nightHour(6, 22, 6))
+	require.True(t, nightHour(1, 0, 5))
+	require.False(t, nightHour(5, 0, 5))
+	require.False(t, nightHour(3, 4, 4))
+}
+
+// TestApplyPromo checks percentage and fixed discounts, the total never goes below zero
+func TestApplyPromo(t *testing.T) {
+	quote := &model.Quote{Total: 20}
+	applyPromo(quote, &model.Promo{Code: "TEN", PercentOff: 10})
+	require.Equal(t, 2.0, quote.Discount)
+	require.Equal(t, 18.0, quote.Total)
+
+	quote = &model.Quote{Total: 4}
+	applyPromo(quote, &model.Promo{Code: "FIVE", AmountOff: 5})
+	require.Equal(t, 4.0, quote.Discount)
+	require.Zero(t, quote.Total)
+}
+
+// TestCheckPromo checks validity period and usage limit of promo codes
+func TestCheckPromo(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	later, earlier, one := now.Add(time.Hour), now.Add(-time.Hour), 1
+
+	require.NoError(t, checkPromo(&model.Promo{Code: "A", ValidFrom: &earlier, ValidTo: &later}, now))
+	require.ErrorIs(t, checkPromo(&model.Promo{Code: "A", ValidFrom: &later}, now), model.ErrValidation)
+	require.ErrorIs(t, checkPromo(&model.Promo{Code: "A", ValidTo: &earlier}, now), model.ErrValidation)
+	require.ErrorIs(t, checkPromo(&model.Promo{Code: "A", MaxUses: &one, Used: 1}, now), model.ErrValidation)
+}
+
+// TestValidateTariffAndPromo checks that invalid pricing rules are rejected
+func TestValidateTariffAndPromo(t *testing.T) {
+	tariff := testTariff()
+	tariff.TimeZone = ""
+	require.NoError(t, validateTariff(tariff))
+	require.Equal(t, "UTC", tariff.TimeZone)
+
+	tariff.PerKm = -1
+	require.ErrorIs(t, validateTariff(tariff), model.ErrValidation)
+	tariff = testTariff()
+	tariff.NightEndHour = 24
+	require.ErrorIs(t, validateTariff(tariff), model.ErrValidation)
+	tariff = testTariff()
+	tariff.TimeZone = "Mars/Olympus"
+	require.ErrorIs(t, validateTariff(tariff), model.ErrValidation)
+
+	promo := &model.Promo{Code: " spring ", PercentOff: 15, Used: 3}
+	require.NoError(t, validatePromo(promo))
+	require.Equal(t, "SPRING", promo.Code)
+	require.Zero(t, promo.Used)
+	require.ErrorIs(t, validatePromo(&model.Promo{Code: "BOTH", PercentOff: 10, AmountOff: 1}), model.ErrValidation)
+	require.ErrorIs(t, validatePromo(&model.Promo{Code: "NONE"}), model.ErrValidation)
+}
+
+type fakePricingRepository struct {
+	PricingRepository
+	tariff *model.Tariff
+	promos map[string]*model.Promo
+}
+
+func (r *fakePricingRepository) GetActiveTariff(ctx context.Context, zone string, at time.Time) (*model.Tariff, error) {
+	if r.tariff == nil {
+		return nil, model.ErrNotFound
+	}
+	return r.tariff, nil
+}
+
+func (r *fakePricingRepository) GetPromo(ctx context.Context, code string) (*model.Promo, error) {
+	promo, ok := r.promos[code]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return promo, nil
+}
+
+// fixedZone locates every point in the same zone, nil meaning outside every zone
+type fixedZone struct {
+	zone *model.Zone
+}
+
+func (z fixedZone) Locate(ctx context.Context, point model.GeoPoint) (*model.Zone, error) {
+	return z.zone, nil
+}
+
+// TestQuote checks that a quote uses the distance between the addresses, item weights and the promo code
+func TestQuote(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakePricingRepository{tariff: testTariff(), promos: map[string]*model.Promo{"HALF": {Code: "HALF", PercentOff: 50}}}
+	srv := NewPricingService(repo, StraightLineDistance{DetourFactor: 1}, &fixedClock{now}, fixedZone{})
+	request := &model.QuoteRequest{
+		Pickup:    model.Location{AddressLine1: "1 Main St", City: "Minsk", Lat: 53.90, Lon: 27.56},
+		Dropoff:   model.Location{AddressLine1: "2 Side St", City: "Minsk", Lat: 53.99, Lon: 27.56},
+		Items:     []*model.DeliveryItem{{Description: "Vase", Quantity: 1, WeightKg: 2, LengthCm: 20, WidthCm: 20, HeightCm: 40, Fragile: true}},
+		PromoCode: " half ",
+	}
+
+	quote, err := srv.Quote(context.Background(), request)
+	require.NoError(t, err)
+	require.InDelta(t, 10.01, quote.DistanceKm, 0.01)
+	require.Equal(t, 2.0, quote.WeightKg)
+	require.Equal(t, 1.0, quote.WeightFee)
+	require.Equal(t, 2.0, quote.FragileSurcharge)
+	require.Equal(t, "HALF", quote.PromoCode)
+	require.InDelta(t, (3+quote.DistanceFee+1+2)/2, quote.Total, 0.01)
+	require.Equal(t, now, quote.QuotedAt)
+
+	request.PromoCode = "UNKNOWN"
+	_, err = srv.Quote(context.Background(), request)
+	require.ErrorIs(t, err, model.ErrValidation)
+
+	request.PromoCode = ""
+	repo.tariff = nil
+	_, err = srv.Quote(context.Background(), request)
+	require.ErrorIs(t, err, model.ErrConflict)
+}
+
+type zoneTariffRepository struct {
+	fakePricingRepository
+	zones []string
+}
+
+func (r *zoneTariffRepository) GetActiveTariff(ctx context.Context, zone string, at time.Time) (*model.Tariff, error) {
+	r.zones = append(r.zones, zone)
+	return r.fakePricingRepository.GetActiveTariff(ctx, zone, at)
+}
+
+// TestQuoteZone checks that the pickup zone picks the tariff and must be open at the start of the window
+func TestQuoteZone(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC) // Monday
+	zone := &model.Zone{Id: uuid.New(), Code: "minsk-center", TimeZone: "UTC", Hours: []model.ZoneHours{{Weekday: time.Monday, Open: "08:00", Close: "20:00"}}}
+	repo := &zoneTariffRepository{fakePricingRepository: fakePricingRepository{tariff: testTariff()}}
+	srv := NewPricingService(repo, StraightLineDistance{DetourFactor: 1}, &fixedClock{now}, fixedZone{zone})
+	delivery := &model.Delivery{
+		Pickup:  model.Location{AddressLine1: "1 Main St", City: "Minsk", Lat: 53.90, Lon: 27.56},
+		Dropoff: model.Location{AddressLine1: "2 Side St", City: "Brest", Lat: 53.99, Lon: 27.56},
+	}
+
+	require.NoError(t, srv.PriceDelivery(context.Background(), delivery))
+	require.Equal(t, []string{"minsk-center"}, repo.zones)
+	require.Equal(t, zone.Id, *delivery.ZoneId)
+
+	late := time.Date(2026, 3, 2, 21, 0, 0, 0, time.UTC)
+	delivery.WindowStart = &late
+	require.ErrorIs(t, srv.PriceDelivery(context.Background(), delivery), model.ErrConflict)
+
+	srv = NewPricingService(repo, StraightLineDistance{DetourFactor: 1}, &fixedClock{now}, fixedZone{})
+	require.NoError(t, srv.PriceDelivery(context.Background(), delivery))
+	require.Equal(t, "Brest", repo.zones[len(repo.zones)-1])
+	require.Nil(t, delivery.ZoneId)
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxProofBytes limits the size of one photo or signature image
+	maxProofBytes = model.MaxProofBytes
+	// maxPinAttempts is the number of wrong PINs after which the PIN is locked
+	maxPinAttempts    = 5
+	deliveryPinDigits = 6
+)
+
+type ProofService struct {
+	rps      ProofRepository
+	blobs    BlobStore
+	clock    Clock
+	required map[string]bool
+}
+
+// NewProofService rejects unknown proof kinds in required
+func NewProofService(rps ProofRepository, blobs BlobStore, clock Clock, required []string) (*ProofService, error) {
+	srv := &ProofService{rps: rps, blobs: blobs, clock: clock, required: make(map[string]bool)}
+	for _, kind := range required {
+		kind = strings.TrimSpace(kind)
+		switch kind {
+		case "":
+		case model.ProofPhoto, model.ProofSignature, model.ProofPin:
+			srv.required[kind] = true
+		default:
+			return nil, fmt.Errorf("unknown proof kind: %s", kind)
+		}
+	}
+	return srv, nil
+}
+
+// BlobStore keeps proof artifacts, keys are slash separated paths
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type ProofRepository interface {
+	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
+	GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error)
+	HasDeliveryPin(ctx context.Context, deliveryId uuid.UUID) (bool, error)
+	ReissueDeliveryPin(ctx context.Context, deliveryId uuid.UUID, pin string) error
+	CompleteDelivery(ctx context.Context, courierId, deliveryId uuid.UUID, deliveredAt time.Time, pin string, maxPinAttempts int,
+		proofs []*model.DeliveryProof, cash *model.CashEntry, collected *float64) error
+	GetDeliveryProofs(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryProof, error)
+	GetDeliveryProof(ctx context.Context, proofId uuid.UUID) (*model.DeliveryProof, error)
+	GetDeliveryLegs(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryLeg, error)
+}
+
+// newDeliveryPin returns a random 6-digit PIN
+func newDeliveryPin() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", fmt.Errorf("Int(): %w", err)
+	}
+	return fmt.Sprintf("%0*d", deliveryPinDigits, n.Int64()), nil
+}
+
+// validateCompletion checks capture metadata and the submitted artifacts
+func validateCompletion(completion *model.DeliveryCompletion, now time.Time) error {
+	if completion.CapturedAt.IsZero() {
+		completion.CapturedAt = now
+	}
+	if completion.CapturedAt.After(now.Add(maxClockSkew)) {
+		return fmt.Errorf("%w: captured_at is in the future", model.ErrValidation)
+	}
+	completion.CapturedAt = completion.CapturedAt.UTC()
+	err := validateReportedPoint(completion.Lat, completion.Lon)
+	if err != nil {
+		return err
+	}
+	completion.Pin = strings.TrimSpace(completion.Pin)
+
+	present := map[string]bool{model.ProofPin: completion.Pin != ""}
+	for _, artifact := range completion.Artifacts {
+		if artifact.Kind != model.ProofPhoto && artifact.Kind != model.ProofSignature {
+			return fmt.Errorf("%w: unknown proof kind %s", model.ErrValidation, artifact.Kind)
+		}
+		if present[artifact.Kind] {
+			return fmt.Errorf("%w: only one %s is accepted", model.ErrValidation, artifact.Kind)
+		}
+		if len(artifact.Data) == 0 || len(artifact.Data) > maxProofBytes {
+			return fmt.Errorf("%w: %s must be 1 byte to %d bytes", model.ErrValidation, artifact.Kind, maxProofBytes)
+		}
+		if !strings.HasPrefix(http.DetectContentType(artifact.Data), "image/") {
+			return fmt.Errorf("%w: %s must be an image", model.ErrValidation, artifact.Kind)
+		}
+		present[artifact.Kind] = true
+	}
+	return nil
+}
+
+// checkRequiredProof checks the submitted proof against the required proof kinds, deliveries without
+// an issued PIN, return legs and deliveries created before PINs, are completed with the remaining proof
+func (srv *ProofService) checkRequiredProof(completion *model.DeliveryCompletion, pinIssued bool) error {
+	present := map[string]bool{model.ProofPin: completion.Pin != ""}
+	for _, artifact := range completion.Artifacts {
+		present[artifact.Kind] = true
+	}
+	for kind := range srv.required {
+		if kind == model.ProofPin && !pinIssued {
+			continue
+		}
+		if !present[kind] {
+			return fmt.Errorf("%w: %s is required to complete the delivery", model.ErrValidation, kind)
+		}
+	}
+	return nil
+}
+
+// CompleteDelivery marks the courier's picked up delivery delivered once the required proof is given, a multi-leg
+// delivery only by the courier of its final leg. The PIN is checked together with the status change, artifacts are
+// stored before it and removed again if the change fails
+func (srv *ProofService) CompleteDelivery(ctx context.Context, userId uuid.UUID, completion *model.DeliveryCompletion) ([]*model.DeliveryProof, error) {
+	now := srv.clock.Now().UTC()
+	err := validateCompletion(completion, now)
+	if err != nil {
+		return nil, fmt.Errorf("validateCompletion: %w", err)
+	}
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	delivery, err := srv.rps.GetDeliveryByID(ctx, completion.DeliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryByID: %w", err)
+	}
+	if delivery.CourierId == nil || *delivery.CourierId != courier.Id {
+		return nil, fmt.Errorf("CompleteDelivery: %w", model.ErrNotFound)
+	}
+	if delivery.DeliveryStatus != model.DeliveryStatusPickedUp {
+		return nil, fmt.Errorf("CompleteDelivery: %w: delivery is %s, it has to be picked up first", model.ErrConflict, delivery.DeliveryStatus)
+	}
+	if delivery.Legs > 0 {
+		legs, err := srv.rps.GetDeliveryLegs(ctx, delivery.Id)
+		if err != nil {
+			return nil, fmt.Errorf("GetDeliveryLegs: %w", err)
+		}
+		err = checkFinalLeg(legs, courier.Id)
+		if err != nil {
+			return nil, fmt.Errorf("checkFinalLeg: %w", err)
+		}
+	}
+	pinIssued := false
+	if srv.required[model.ProofPin] {
+		pinIssued, err = srv.rps.HasDeliveryPin(ctx, delivery.Id)
+		if err != nil {
+			return nil, fmt.Errorf("HasDeliveryPin: %w", err)
+		}
+	}
+	err = srv.checkRequiredProof(completion, pinIssued)
+	if err != nil {
+		return nil, fmt.Errorf("checkRequiredProof: %w", err)
+	}
+	cash, err := cashCollection(delivery, courier.Id, completion.CollectedAmount, now)
+	if err != nil {
+		return nil, fmt.Errorf("cashCollection: %w", err)
+	}
+
+	newProof := func(kind string) *model.DeliveryProof {
+		return &model.DeliveryProof{Id: uuid.New(), DeliveryId: delivery.Id, CourierId: courier.Id, Event: model.DeliveryStatusDelivered,
+			Kind: kind, CapturedAt: completion.CapturedAt, Lat: completion.Lat, Lon: completion.Lon, CreatedAt: now}
+	}
+	var proofs []*model.DeliveryProof
+	if completion.Pin != "" {
+		proofs = append(proofs, newProof(model.ProofPin))
+	}
+	var stored []string
+	for _, artifact := range completion.Artifacts {
+		proof := newProof(artifact.Kind)
+		sum := sha256.Sum256(artifact.Data)
+		proof.BlobKey = fmt.Sprintf("deliveries/%s/%s", delivery.Id, proof.Id)
+		proof.ContentType = http.DetectContentType(artifact.Data)
+		proof.SizeBytes = int64(len(artifact.Data))
+		proof.Sha256 = hex.EncodeToString(sum[:])
+		err = srv.blobs.Put(ctx, proof.BlobKey, bytes.NewReader(artifact.Data))
+		if err != nil {
+			srv.deleteBlobs(stored)
+			return nil, fmt.Errorf("Put: %w", err)
+		}
+		stored = append(stored, proof.BlobKey)
+		proofs = append(proofs, proof)
+	}
+	err = srv.rps.CompleteDelivery(ctx, courier.Id, delivery.Id, now, completion.Pin, maxPinAttempts, proofs, cash, completion.CollectedAmount)
+	if err != nil {
+		srv.deleteBlobs(stored)
+		return nil, fmt.Errorf("CompleteDelivery: %w", err)
+	}
+	return proofs, nil
+}
+
+// ReissuePin issues a new PIN for an open delivery, which also unlocks a PIN locked by wrong attempts
+func (srv *ProofService) ReissuePin(ctx context.Context, deliveryId uuid.UUID) (string, error) {
+	delivery, err := srv.rps.GetDeliveryByID(ctx, deliveryId)
+	if err != nil {
+		return "", fmt.Errorf("GetDeliveryByID: %w", err)
+	}
+	if deliveryClosed(delivery.DeliveryStatus) {
+		return "", fmt.Errorf("ReissuePin: %w: delivery is %s", model.ErrConflict, delivery.DeliveryStatus)
+	}
+	pin, err := newDeliveryPin()
+	if err != nil {
+		return "", fmt.Errorf("newDeliveryPin: %w", err)
+	}
+	err = srv.rps.ReissueDeliveryPin(ctx, delivery.Id, pin)
+	if err != nil {
+		return "", fmt.Errorf("ReissueDeliveryPin: %w", err)
+	}
+	return pin, nil
+}
+
+// deleteBlobs removes artifacts of a failed completion, the request context may already be cancelled
+func (srv *ProofService) deleteBlobs(keys []string) {
+	for _, key := range keys {
+		err := srv.blobs.Delete(context.Background(), key)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{"key": key}).Errorf("Delete: %v", err)
+		}
+	}
+}
+
+func (srv *ProofService) GetDeliveryProofs(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryProof, error) {
+	proofs, err := srv.rps.GetDeliveryProofs(ctx, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryProofs: %w", err)
+	}
+	return proofs, nil
+}
+
+// OpenProof returns the stored artifact of a photo or signature proof, the caller closes it
+func (srv *ProofService) OpenProof(ctx context.Context, proofId uuid.UUID) (*model.DeliveryProof, io.ReadCloser, error) {
+	proof, err := srv.rps.GetDeliveryProof(ctx, proofId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetDeliveryProof: %w", err)
+	}
+	if proof.BlobKey == "" {
+		return nil, nil, fmt.Errorf("OpenProof: %w: %s proof has no file", model.ErrNotFound, proof.Kind)
+	}
+	blob, err := srv.blobs.Open(ctx, proof.BlobKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Open: %w", err)
+	}
+	return proof, blob, nil
+}
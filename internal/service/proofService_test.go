@@ -0,0 +1,228 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProofRepository struct {
+	courier   *model.Courier
+	delivery  *model.DeliveryGet
+	pin       string
+	completed []*model.DeliveryProof
+	failSave  bool
+	legs      []*model.DeliveryLeg
+}
+
+func (r *fakeProofRepository) GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error) {
+	return r.courier, nil
+}
+
+func (r *fakeProofRepository) GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error) {
+	return r.delivery, nil
+}
+
+func (r *fakeProofRepository) HasDeliveryPin(ctx context.Context, deliveryId uuid.UUID) (bool, error) {
+	return r.pin != "", nil
+}
+
+func (r *fakeProofRepository) ReissueDeliveryPin(ctx context.Context, deliveryId uuid.UUID, pin string) error {
+	r.pin = pin
+	return nil
+}
+
+func (r *fakeProofRepository) CompleteDelivery(ctx context.Context, courierId, deliveryId uuid.UUID, deliveredAt time.Time, pin string, maxPinAttempts int,
+	proofs []*model.DeliveryProof, cash *model.CashEntry, collected *float64) error {
+	if pin != "" && pin != r.pin {
+		return model.ErrValidation
+	}
+	if r.failSave {
+		return errors.New("test_error")
+	}
+	r.completed = proofs
+	return nil
+}
+
+func (r *fakeProofRepository) GetDeliveryProofs(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryProof, error) {
+	return r.completed, nil
+}
+
+func (r *fakeProofRepository) GetDeliveryProof(ctx context.Context, proofId uuid.UUID) (*model.DeliveryProof, error) {
+	return nil, model.ErrNotFound
+}
+
+func (r *fakeProofRepository) GetDeliveryLegs(ctx context.Context, deliveryId uuid.UUID) ([]*model.DeliveryLeg, error) {
+	return r.legs, nil
+}
+
+type memoryBlobStore map[string][]byte
+
+func (s memoryBlobStore) Put(ctx context.Context, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	s[key] = data
+	return err
+}
+
+func (s memoryBlobStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(s[key])), nil
+}
+
+func (s memoryBlobStore) Delete(ctx context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+// pngHeader is enough for content sniffing to report image/png
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newProofFixture(t *testing.T, required ...string) (*ProofService, *fakeProofRepository, memoryBlobStore) {
+	courier := &model.Courier{Id: uuid.New()}
+	repo := &fakeProofRepository{
+		courier:  courier,
+		delivery: &model.DeliveryGet{Id: uuid.New(), CourierId: &courier.Id, DeliveryStatus: model.DeliveryStatusPickedUp},
+		pin:      "123456",
+	}
+	blobs := memoryBlobStore{}
+	srv, err := NewProofService(repo, blobs, &fixedClock{time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}, required)
+	require.NoError(t, err)
+	return srv, repo, blobs
+}
+
+// TestCompleteDeliveryRequiresProof checks that configured proof kinds and a matching PIN are enforced
+func TestCompleteDeliveryRequiresProof(t *testing.T) {
+	srv, repo, _ := newProofFixture(t, model.ProofPin, model.ProofPhoto)
+	ctx := context.Background()
+
+	_, err := srv.CompleteDelivery(ctx, uuid.New(), &model.DeliveryCompletion{DeliveryId: repo.delivery.Id, Pin: "123456"})
+	require.ErrorIs(t, err, model.ErrValidation)
+
+	photo := []model.ProofArtifact{{Kind: model.ProofPhoto, Data: pngHeader}}
+	_, err = srv.CompleteDelivery(ctx, uuid.New(), &model.DeliveryCompletion{DeliveryId: repo.delivery.Id, Pin: "654321", Artifacts: photo})
+	require.ErrorIs(t, err, model.ErrValidation)
+
+	text := []model.ProofArtifact{{Kind: model.ProofPhoto, Data: []byte("test_text")}}
+	_, err = srv.CompleteDelivery(ctx, uuid.New(), &model.DeliveryCompletion{DeliveryId: repo.delivery.Id, Pin: "123456", Artifacts: text})
+	require.ErrorIs(t, err, model.ErrValidation)
+	require.Nil(t, repo.completed)
+}
+
+// TestCompleteDeliveryStoresProof checks that artifacts are stored with their capture metadata
+func TestCompleteDeliveryStoresProof(t *testing.T) {
+	srv, repo, blobs := newProofFixture(t, model.ProofSignature)
+	lat, lon := 53.9, 27.56
+	completion := &model.DeliveryCompletion{DeliveryId: repo.delivery.Id, Lat: &lat, Lon: &lon,
+		Artifacts: []model.ProofArtifact{{Kind: model.ProofSignature, Data: pngHeader}}}
+
+	proofs, err := srv.CompleteDelivery(context.Background(), uuid.New(), completion)
+	require.NoError(t, err)
+	require.Len(t, proofs, 1)
+	require.Equal(t, model.ProofSignature, proofs[0].Kind)
+	require.Equal(t, "image/png", proofs[0].ContentType)
+	require.Equal(t, model.DeliveryStatusDelivered, proofs[0].Event)
+	require.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), proofs[0].CapturedAt)
+	require.Equal(t, &lat, proofs[0].Lat)
+	require.Equal(t, pngHeader, blobs[proofs[0].BlobKey])
+	require.Equal(t, proofs, repo.completed)
+}
+
+// TestCompleteDeliveryRemovesBlobsOnFailure checks that no orphaned artifacts are left when the status change fails
+func TestCompleteDeliveryRemovesBlobsOnFailure(t *testing.T) {
+	srv, repo, blobs := newProofFixture(t)
+	repo.failSave = true
+	completion := &model.DeliveryCompletion{DeliveryId: repo.delivery.Id,
+		Artifacts: []model.ProofArtifact{{Kind: model.ProofPhoto, Data: pngHeader}}}
+
+	_, err := srv.CompleteDelivery(context.Background(), uuid.New(), completion)
+	require.Error(t, err)
+	require.Empty(t, blobs)
+}
+
+// TestCompleteDeliveryWithoutPin checks that deliveries without an issued PIN, such as return legs and
+// deliveries created before PINs, are completed with the remaining proof
+func TestCompleteDeliveryWithoutPin(t *testing.T) {
+	srv, repo, _ := newProofFixture(t, model.ProofPin)
+	ctx := context.Background()
+	completion := &model.DeliveryCompletion{DeliveryId: repo.delivery.Id}
+
+	_, err := srv.CompleteDelivery(ctx, uuid.New(), completion)
+	require.ErrorIs(t, err, model.ErrValidation)
+	repo.pin = ""
+	_, err = srv.CompleteDelivery(ctx, uuid.New(), completion)
+	require.NoError(t, err)
+}
+
+// TestCompleteDeliveryNotPickedUp checks that a delivery nobody picked up can not be completed
+func TestCompleteDeliveryNotPickedUp(t *testing.T) {
+	srv, repo, _ := newProofFixture(t)
+	repo.delivery.DeliveryStatus = model.DeliveryStatusCreated
+
+	_, err := srv.CompleteDelivery(context.Background(), uuid.New(), &model.DeliveryCompletion{DeliveryId: repo.delivery.Id})
+	require.ErrorIs(t, err, model.ErrConflict)
+	require.Nil(t, repo.completed)
+}
+
+// TestCompleteDeliveryOfAnotherCourier checks that couriers can not complete deliveries they do not carry
+func TestCompleteDeliveryOfAnotherCourier(t *testing.T) {
+	srv, repo, _ := newProofFixture(t)
+	other := uuid.New()
+	repo.delivery.CourierId = &other
+
+	_, err := srv.CompleteDelivery(context.Background(), uuid.New(), &model.DeliveryCompletion{DeliveryId: repo.delivery.Id})
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
+
+// TestCompleteMultiLegDelivery checks that only the courier carrying the final leg completes a multi-leg delivery
+func TestCompleteMultiLegDelivery(t *testing.T) {
+	srv, repo, _ := newProofFixture(t)
+	ctx := context.Background()
+	hubId := uuid.New()
+	first := &model.DeliveryLeg{Id: uuid.New(), ToHubId: &hubId, CourierId: &repo.courier.Id, Status: model.DeliveryStatusPickedUp}
+	final := &model.DeliveryLeg{Id: uuid.New(), Position: 1, FromHubId: &hubId, Status: model.DeliveryStatusCreated}
+	repo.delivery.Legs = 2
+	repo.legs = []*model.DeliveryLeg{first, final}
+	completion := &model.DeliveryCompletion{DeliveryId: repo.delivery.Id}
+
+	_, err := srv.CompleteDelivery(ctx, uuid.New(), completion)
+	require.ErrorIs(t, err, model.ErrConflict)
+	require.Nil(t, repo.completed)
+
+	other := uuid.New()
+	first.Status = model.DeliveryStatusDelivered
+	final.Status, final.CourierId = model.DeliveryStatusPickedUp, &other
+	_, err = srv.CompleteDelivery(ctx, uuid.New(), completion)
+	require.ErrorIs(t, err, model.ErrConflict)
+
+	final.CourierId = &repo.courier.Id
+	_, err = srv.CompleteDelivery(ctx, uuid.New(), completion)
+	require.NoError(t, err)
+}
+
+// TestReissuePin checks that a new PIN replaces a locked one and closed deliveries keep theirs
+func TestReissuePin(t *testing.T) {
+	srv, repo, _ := newProofFixture(t)
+	ctx := context.Background()
+
+	pin, err := srv.ReissuePin(ctx, repo.delivery.Id)
+	require.NoError(t, err)
+	require.Equal(t, repo.pin, pin)
+
+	repo.delivery.DeliveryStatus = model.DeliveryStatusDelivered
+	_, err = srv.ReissuePin(ctx, repo.delivery.Id)
+	require.ErrorIs(t, err, model.ErrConflict)
+	require.Equal(t, pin, repo.pin)
+}
+
+// TestNewDeliveryPin checks the PIN format
+func TestNewDeliveryPin(t *testing.T) {
+	pin, err := newDeliveryPin()
+	require.NoError(t, err)
+	require.Regexp(t, `^[0-9]{6}$`, pin)
+}
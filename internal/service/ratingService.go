@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+const (
+	maxRatingTags    = 5
+	maxRatingComment = 1000
+	// recentRatings is the number of ratings listed with the aggregates of a courier
+	recentRatings = 20
+)
+
+// ratingTags are the accepted rating tags
+var ratingTags = map[string]bool{
+	model.RatingTagOnTime:       true,
+	model.RatingTagFriendly:     true,
+	model.RatingTagCareful:      true,
+	model.RatingTagProfessional: true,
+	model.RatingTagLate:         true,
+	model.RatingTagRude:         true,
+	model.RatingTagDamaged:      true,
+	model.RatingTagNoContact:    true,
+}
+
+type RatingService struct {
+	rps   RatingRepository
+	clock Clock
+	// window is how long after delivery a rating is accepted
+	window time.Duration
+	// alertScore is the highest score that shows up in the low rating alerts
+	alertScore int
+}
+
+func NewRatingService(rps RatingRepository, clock Clock, window time.Duration, alertScore int) *RatingService {
+	return &RatingService{rps: rps, clock: clock, window: window, alertScore: alertScore}
+}
+
+type RatingRepository interface {
+	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
+	GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error)
+	GetDeliveryIDByTrackingCode(ctx context.Context, code string) (uuid.UUID, error)
+	InsertRating(ctx context.Context, rating *model.Rating) error
+	ModerateRating(ctx context.Context, moderation *model.RatingModeration, managerId uuid.UUID, at time.Time) (*model.Rating, error)
+	GetCourierRatings(ctx context.Context, courierId uuid.UUID, recent int) (*model.CourierRatings, error)
+	GetLowRatings(ctx context.Context, maxScore int, since *time.Time) ([]*model.Rating, error)
+}
+
+// validateRating checks the score, tags and comment and removes repeated tags
+func validateRating(request *model.RatingRequest) error {
+	if request.Score < 1 || request.Score > 5 {
+		return fmt.Errorf("%w: score must be 1 to 5", model.ErrValidation)
+	}
+	seen := make(map[string]bool, len(request.Tags))
+	tags := make([]string, 0, len(request.Tags))
+	for _, tag := range request.Tags {
+		if !ratingTags[tag] {
+			return fmt.Errorf("%w: unknown tag %q", model.ErrValidation, tag)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > maxRatingTags {
+		return fmt.Errorf("%w: at most %d tags are allowed", model.ErrValidation, maxRatingTags)
+	}
+	request.Tags = tags
+	request.Comment = strings.TrimSpace(request.Comment)
+	if len([]rune(request.Comment)) > maxRatingComment {
+		return fmt.Errorf("%w: comment must be at most %d characters", model.ErrValidation, maxRatingComment)
+	}
+	return nil
+}
+
+// rate stores the rating of a delivered delivery within the rating window, a delivery is rated once
+func (srv *RatingService) rate(ctx context.Context, delivery *model.DeliveryGet, request *model.RatingRequest, source string, ratedBy *uuid.UUID) (*model.Rating, error) {
+	now := srv.clock.Now().UTC()
+	if delivery.DeliveryStatus != model.DeliveryStatusDelivered || delivery.DeliveredAt == nil || delivery.CourierId == nil {
+		return nil, fmt.Errorf("%w: only delivered deliveries can be rated", model.ErrConflict)
+	}
+	if now.After(delivery.DeliveredAt.Add(srv.window)) {
+		return nil, fmt.Errorf("%w: the rating period has ended", model.ErrConflict)
+	}
+	rating := &model.Rating{
+		Id:         uuid.New(),
+		DeliveryId: delivery.Id,
+		CourierId:  *delivery.CourierId,
+		Score:      request.Score,
+		Tags:       request.Tags,
+		Comment:    request.Comment,
+		Source:     source,
+		RatedBy:    ratedBy,
+		Status:     model.RatingPublished,
+		CreatedAt:  now,
+	}
+	err := srv.rps.InsertRating(ctx, rating)
+	if err != nil {
+		return nil, fmt.Errorf("InsertRating: %w", err)
+	}
+	return rating, nil
+}
+
+// RateAsClient rates a delivery owned by the client, deliveries of other clients are reported as not found
+func (srv *RatingService) RateAsClient(ctx context.Context, clientId uuid.UUID, request *model.RatingRequest) (*model.Rating, error) {
+	err := validateRating(request)
+	if err != nil {
+		return nil, fmt.Errorf("validateRating: %w", err)
+	}
+	delivery, err := srv.rps.GetDeliveryByID(ctx, request.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryByID: %w", err)
+	}
+	if delivery.ClientId == nil || *delivery.ClientId != clientId {
+		return nil, fmt.Errorf("RateAsClient: %w", model.ErrNotFound)
+	}
+	return srv.rate(ctx, delivery, request, model.RatedByClient, &clientId)
+}
+
+// RateByTrackingCode rates the delivery of a valid tracking code
+func (srv *RatingService) RateByTrackingCode(ctx context.Context, code string, request *model.RatingRequest) (*model.Rating, error) {
+	err := validateRating(request)
+	if err != nil {
+		return nil, fmt.Errorf("validateRating: %w", err)
+	}
+	code, ok := normalizeTrackingCode(code)
+	if !ok {
+		return nil, fmt.Errorf("RateByTrackingCode: %w", model.ErrNotFound)
+	}
+	deliveryId, err := srv.rps.GetDeliveryIDByTrackingCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryIDByTrackingCode: %w", err)
+	}
+	delivery, err := srv.rps.GetDeliveryByID(ctx, deliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeliveryByID: %w", err)
+	}
+	return srv.rate(ctx, delivery, request, model.RatedByTracking, nil)
+}
+
+// ModerateRating hides a rating from aggregates and performance or publishes it again
+func (srv *RatingService) ModerateRating(ctx context.Context, managerId uuid.UUID, moderation *model.RatingModeration) (*model.Rating, error) {
+	if moderation.Status != model.RatingPublished && moderation.Status != model.RatingHidden {
+		return nil, fmt.Errorf("%w: status must be published or hidden", model.ErrValidation)
+	}
+	moderation.Note = strings.TrimSpace(moderation.Note)
+	if len([]rune(moderation.Note)) > maxRatingComment {
+		return nil, fmt.Errorf("%w: note must be at most %d characters", model.ErrValidation, maxRatingComment)
+	}
+	rating, err := srv.rps.ModerateRating(ctx, moderation, managerId, srv.clock.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("ModerateRating: %w", err)
+	}
+	return rating, nil
+}
+
+// GetCourierRatings returns the rating aggregates and recent ratings of the courier with the given user id
+func (srv *RatingService) GetCourierRatings(ctx context.Context, userId uuid.UUID) (*model.CourierRatings, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	ratings, err := srv.rps.GetCourierRatings(ctx, courier.Id, recentRatings)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierRatings: %w", err)
+	}
+	return ratings, nil
+}
+
+// GetRatingAlerts returns published ratings at or below the alert score, newest first
+func (srv *RatingService) GetRatingAlerts(ctx context.Context, since *time.Time) ([]*model.Rating, error) {
+	ratings, err := srv.rps.GetLowRatings(ctx, srv.alertScore, since)
+	if err != nil {
+		return nil, fmt.Errorf("GetLowRatings: %w", err)
+	}
+	return ratings, nil
+}
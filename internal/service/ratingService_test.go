@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateRating checks score bounds, known tags and that repeated tags are dropped
+func TestValidateRating(t *testing.T) {
+	request := &model.RatingRequest{Score: 4, Tags: []string{model.RatingTagFriendly, model.RatingTagFriendly, model.RatingTagOnTime}, Comment: "  thanks "}
+	require.NoError(t, validateRating(request))
+	require.Equal(t, []string{model.RatingTagFriendly, model.RatingTagOnTime}, request.Tags)
+	require.Equal(t, "thanks", request.Comment)
+
+	for _, score := range []int{0, 6} {
+		require.ErrorIs(t, validateRating(&model.RatingRequest{Score: score}), model.ErrValidation)
+	}
+	require.ErrorIs(t, validateRating(&model.RatingRequest{Score: 3, Tags: []string{"fast"}}), model.ErrValidation)
+}
+
+type fakeRatingRepository struct {
+	RatingRepository
+	delivery *model.DeliveryGet
+	rating   *model.Rating
+}
+
+func (r *fakeRatingRepository) GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error) {
+	return r.delivery, nil
+}
+
+func (r *fakeRatingRepository) GetDeliveryIDByTrackingCode(ctx context.Context, code string) (uuid.UUID, error) {
+	return r.delivery.Id, nil
+}
+
+func (r *fakeRatingRepository) InsertRating(ctx context.Context, rating *model.Rating) error {
+	r.rating = rating
+	return nil
+}
+
+// TestRateDelivery checks ownership, delivery status and the rating window
+func TestRateDelivery(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	clientId, courierId := uuid.New(), uuid.New()
+	deliveredAt := now.Add(-48 * time.Hour)
+	delivery := &model.DeliveryGet{Id: uuid.New(), ClientId: &clientId, CourierId: &courierId, DeliveryStatus: model.DeliveryStatusDelivered, DeliveredAt: &deliveredAt}
+	rps := &fakeRatingRepository{delivery: delivery}
+	srv := NewRatingService(rps, &fixedClock{now}, 72*time.Hour, 2)
+	request := func() *model.RatingRequest {
+		return &model.RatingRequest{DeliveryId: model.DeliveryId{Id: delivery.Id}, Score: 2, Tags: []string{model.RatingTagLate}}
+	}
+
+	_, err := srv.RateAsClient(context.Background(), uuid.New(), request())
+	require.ErrorIs(t, err, model.ErrNotFound)
+
+	rating, err := srv.RateAsClient(context.Background(), clientId, request())
+	require.NoError(t, err)
+	require.Equal(t, courierId, rating.CourierId)
+	require.Equal(t, model.RatedByClient, rating.Source)
+	require.Equal(t, clientId, *rating.RatedBy)
+	require.Equal(t, model.RatingPublished, rating.Status)
+	require.Equal(t, rating, rps.rating)
+
+	rating, err = srv.RateByTrackingCode(context.Background(), "abcd2345wxyz", request())
+	require.NoError(t, err)
+	require.Equal(t, model.RatedByTracking, rating.Source)
+	require.Nil(t, rating.RatedBy)
+
+	late := now.Add(-73 * time.Hour)
+	delivery.DeliveredAt = &late
+	_, err = srv.RateAsClient(context.Background(), clientId, request())
+	require.ErrorIs(t, err, model.ErrConflict)
+
+	delivery.DeliveredAt, delivery.DeliveryStatus = nil, model.DeliveryStatusPickedUp
+	_, err = srv.RateAsClient(context.Background(), clientId, request())
+	require.ErrorIs(t, err, model.ErrConflict)
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+const (
+	// stopDuration is the time spent at every pickup and drop-off
+	stopDuration = 4 * time.Minute
+	// latenessPenalty makes a minute of lateness cost as much as this many minutes of driving,
+	// so that windows are kept whenever some order allows it
+	latenessPenalty = 1000
+	// maxImprovementRounds bounds the 2-opt search
+	maxImprovementRounds = 50
+	// defaultVehicle is used for couriers with an unknown vehicle type
+	defaultVehicle = model.VehicleBicycle
+)
+
+// Distancer returns the expected travel distance between two points,
+// a precomputed distance matrix can be plugged in instead of straight lines
+type Distancer interface {
+	DistanceKm(a, b model.GeoPoint) float64
+}
+
+// StraightLineDistance is the great-circle distance stretched by a detour factor
+type StraightLineDistance struct {
+	DetourFactor float64
+}
+
+func (d StraightLineDistance) DistanceKm(a, b model.GeoPoint) float64 {
+	return haversineKm(a, b) * d.DetourFactor
+}
+
+// DefaultDistance assumes roads are 30% longer than straight lines
+var DefaultDistance = StraightLineDistance{DetourFactor: 1.3}
+
+// RoutePlanner orders a courier's stops with a nearest-neighbour start improved by 2-opt
+type RoutePlanner struct {
+	distance Distancer
+	// speeds is the speed profile in km/h per vehicle type
+	speeds map[string]float64
+}
+
+func NewRoutePlanner(distance Distancer, speeds map[string]float64) *RoutePlanner {
+	return &RoutePlanner{distance: distance, speeds: speeds}
+}
+
+// speedFor returns the profile speed of the vehicle type
+func (p *RoutePlanner) speedFor(vehicleType string) float64 {
+	if speed, ok := p.speeds[vehicleType]; ok && speed > 0 {
+		return speed
+	}
+	return p.speeds[defaultVehicle]
+}
+
+// routeStops lists the remaining stops, a delivery that is not picked up yet needs both
+func routeStops(deliveries []*model.DeliveryGet) []model.RouteStop {
+	var stops []model.RouteStop
+	for _, delivery := range deliveries {
+		if delivery.PickedUpAt == nil {
+			stops = append(stops, model.RouteStop{DeliveryId: delivery.Id, Kind: model.StopPickup, Location: delivery.Pickup})
+		}
+		stops = append(stops, model.RouteStop{
+			DeliveryId:  delivery.Id,
+			Kind:        model.StopDropoff,
+			Location:    delivery.Dropoff,
+			WindowStart: delivery.WindowStart,
+			WindowEnd:   delivery.WindowEnd,
+		})
+	}
+	return stops
+}
+
+// validOrder reports whether every pickup comes before the drop-off of the same delivery
+func validOrder(stops []model.RouteStop) bool {
+	droppedOff := make(map[uuid.UUID]bool, len(stops))
+	for _, stop := range stops {
+		switch stop.Kind {
+		case model.StopDropoff:
+			droppedOff[stop.DeliveryId] = true
+		case model.StopPickup:
+			if droppedOff[stop.DeliveryId] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// simulate fills arrival times and returns the cost of the order: minutes until the last stop
+// plus a penalty for every minute of lateness
+func (p *RoutePlanner) simulate(plan *model.RoutePlan, start *model.GeoPoint, speed float64, now time.Time) float64 {
+	at, position := now, start
+	var late time.Duration
+	plan.DistanceKm, plan.LateStops = 0, 0
+	for i := range plan.Stops {
+		stop := &plan.Stops[i]
+		point := stop.Location.Point()
+		if position != nil {
+			km := p.distance.DistanceKm(*position, point)
+			plan.DistanceKm += km
+			if speed > 0 {
+				at = at.Add(time.Duration(km / speed * float64(time.Hour)))
+			}
+		}
+		// a courier who is early waits for the window to open
+		if stop.WindowStart != nil && at.Before(*stop.WindowStart) {
+			at = *stop.WindowStart
+		}
+		stop.Arrival = at
+		stop.Late = stop.WindowEnd != nil && at.After(*stop.WindowEnd)
+		if stop.Late {
+			late += at.Sub(*stop.WindowEnd)
+			plan.LateStops++
+		}
+		at = at.Add(stopDuration)
+		position = &point
+	}
+	plan.FinishAt = at
+	return at.Sub(now).Minutes() + latenessPenalty*late.Minutes()
+}
+
+// nearestNeighbour always moves to the closest stop that is allowed next
+func (p *RoutePlanner) nearestNeighbour(stops []model.RouteStop, start *model.GeoPoint) []model.RouteStop {
+	pending := make(map[uuid.UUID]bool)
+	for _, stop := range stops {
+		if stop.Kind == model.StopPickup {
+			pending[stop.DeliveryId] = true
+		}
+	}
+	left := append([]model.RouteStop(nil), stops...)
+	order := make([]model.RouteStop, 0, len(stops))
+	position := start
+	for len(left) > 0 {
+		best, bestKm := -1, 0.0
+		for i, stop := range left {
+			if stop.Kind == model.StopDropoff && pending[stop.DeliveryId] {
+				continue
+			}
+			km := 0.0
+			if position != nil {
+				km = p.distance.DistanceKm(*position, stop.Location.Point())
+			}
+			if best == -1 || km < bestKm {
+				best, bestKm = i, km
+			}
+		}
+		stop := left[best]
+		if stop.Kind == model.StopPickup {
+			delete(pending, stop.DeliveryId)
+		}
+		point := stop.Location.Point()
+		position = &point
+		order = append(order, stop)
+		left = append(left[:best], left[best+1:]...)
+	}
+	return order
+}
+
+// earliestDeadline visits deliveries by window end, each pickup right before its drop-off
+func earliestDeadline(deliveries []*model.DeliveryGet) []model.RouteStop {
+	ordered := append([]*model.DeliveryGet(nil), deliveries...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i].WindowEnd, ordered[j].WindowEnd
+		return a != nil && (b == nil || a.Before(*b))
+	})
+	return routeStops(ordered)
+}
+
+// Plan returns the best order found for the courier's remaining stops,
+// the better of nearest-neighbour and earliest-deadline orders is improved with 2-opt moves
+func (p *RoutePlanner) Plan(route *model.CourierRoute, now time.Time) *model.RoutePlan {
+	speed := p.speedFor(route.VehicleType)
+	stops := routeStops(route.Deliveries)
+
+	best := &model.RoutePlan{CourierId: route.CourierId, Stops: p.nearestNeighbour(stops, route.Position)}
+	bestCost := p.simulate(best, route.Position, speed, now)
+	deadline := &model.RoutePlan{CourierId: route.CourierId, Stops: earliestDeadline(route.Deliveries)}
+	if cost := p.simulate(deadline, route.Position, speed, now); cost < bestCost {
+		best, bestCost = deadline, cost
+	}
+
+	for round := 0; round < maxImprovementRounds; round++ {
+		improved := false
+		for i := 0; i < len(best.Stops)-1; i++ {
+			for j := i + 1; j < len(best.Stops); j++ {
+				candidate := &model.RoutePlan{CourierId: route.CourierId, Stops: append([]model.RouteStop(nil), best.Stops...)}
+				for a, b := i, j; a < b; a, b = a+1, b-1 {
+					candidate.Stops[a], candidate.Stops[b] = candidate.Stops[b], candidate.Stops[a]
+				}
+				if !validOrder(candidate.Stops) {
+					continue
+				}
+				if cost := p.simulate(candidate, route.Position, speed, now); cost < bestCost-1e-9 {
+					best, bestCost, improved = candidate, cost, true
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+	p.simulate(best, route.Position, speed, now)
+	return best
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlanKeepsPickupBeforeDropoff checks precedence when drop-offs are closer than pickups
+func TestPlanKeepsPickupBeforeDropoff(t *testing.T) {
+	now := time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)
+	route := &model.CourierRoute{
+		VehicleType: model.VehicleBicycle,
+		Position:    &model.GeoPoint{Lat: 53.9, Lon: 27.5},
+		Deliveries: []*model.DeliveryGet{
+			{Id: uuid.New(), Pickup: model.Location{Lat: 53.9, Lon: 27.7}, Dropoff: model.Location{Lat: 53.9, Lon: 27.51}},
+			{Id: uuid.New(), Pickup: model.Location{Lat: 53.95, Lon: 27.7}, Dropoff: model.Location{Lat: 53.9, Lon: 27.52}},
+		},
+	}
+	plan := NewRoutePlanner(DefaultDistance, map[string]float64{model.VehicleBicycle: 15}).Plan(route, now)
+
+	require.Len(t, plan.Stops, 4)
+	require.True(t, validOrder(plan.Stops))
+	require.Equal(t, model.StopPickup, plan.Stops[0].Kind)
+	require.Equal(t, model.StopDropoff, plan.Stops[3].Kind)
+	require.Equal(t, plan.Stops[3].Arrival.Add(stopDuration), plan.FinishAt)
+}
+
+// TestPlanRespectsWindows checks that a far drop-off with a tight window is visited before a near one in the other direction
+func TestPlanRespectsWindows(t *testing.T) {
+	now := time.Date(2024, time.December, 13, 9, 0, 0, 0, time.UTC)
+	tight, loose := now.Add(50*time.Minute), now.Add(4*time.Hour)
+	near := &model.DeliveryGet{Id: uuid.New(), PickedUpAt: &now, WindowEnd: &loose, Dropoff: model.Location{Lat: 53.9, Lon: 27.48}}
+	far := &model.DeliveryGet{Id: uuid.New(), PickedUpAt: &now, WindowEnd: &tight, Dropoff: model.Location{Lat: 53.9, Lon: 27.62}}
+	route := &model.CourierRoute{
+		VehicleType: model.VehicleBicycle,
+		Position:    &model.GeoPoint{Lat: 53.9, Lon: 27.5},
+		Deliveries:  []*model.DeliveryGet{near, far},
+	}
+	plan := NewRoutePlanner(DefaultDistance, map[string]float64{model.VehicleBicycle: 15}).Plan(route, now)
+
+	require.Equal(t, far.Id, plan.Stops[0].DeliveryId)
+	require.Zero(t, plan.LateStops)
+}
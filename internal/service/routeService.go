@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+type RouteService struct {
+	rps     RouteRepository
+	planner *RoutePlanner
+	clock   Clock
+}
+
+func NewRouteService(rps RouteRepository, planner *RoutePlanner, clock Clock) *RouteService {
+	return &RouteService{rps: rps, planner: planner, clock: clock}
+}
+
+type RouteRepository interface {
+	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
+	GetCourierRoute(ctx context.Context, courierId uuid.UUID) (*model.CourierRoute, error)
+}
+
+// GetRoute plans the remaining stops of the courier with the given user id
+func (srv *RouteService) GetRoute(ctx context.Context, userId uuid.UUID) (*model.RoutePlan, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	route, err := srv.rps.GetCourierRoute(ctx, courier.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierRoute: %w", err)
+	}
+	return srv.planner.Plan(route, srv.clock.Now()), nil
+}
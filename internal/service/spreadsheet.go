@@ -0,0 +1,216 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/xuri/excelize/v2"
+)
+
+// readSpreadsheet returns the rows of a CSV file or of the first sheet of an XLSX workbook,
+// XLSX cells are read unformatted so numbers and dates do not depend on the sheet's number formats
+func readSpreadsheet(format string, data []byte) ([][]string, error) {
+	switch format {
+	case model.ImportFormatCSV:
+		data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+		r := csv.NewReader(bytes.NewReader(data))
+		r.FieldsPerRecord = -1
+		r.TrimLeadingSpace = true
+		rows, err := r.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", model.ErrValidation, err)
+		}
+		return rows, nil
+	case model.ImportFormatXLSX:
+		f, err := excelize.OpenReader(bytes.NewReader(data), excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", model.ErrValidation, err)
+		}
+		defer f.Close()
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("%w: workbook has no sheets", model.ErrValidation)
+		}
+		rows, err := f.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", model.ErrValidation, err)
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q, use %s or %s", model.ErrValidation, format, model.ImportFormatCSV, model.ImportFormatXLSX)
+	}
+}
+
+// importTimeLayouts are the accepted text forms of window times, times without an offset are UTC
+var importTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+// parseImportTime parses a text time or an Excel date serial number
+func parseImportTime(value string) (time.Time, error) {
+	for _, layout := range importTimeLayouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t.UTC(), nil
+		}
+	}
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a time, use RFC 3339 or YYYY-MM-DD HH:MM", value)
+	}
+	t, err := excelize.ExcelDateToTime(serial, false)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.UTC(), nil
+}
+
+// parseImportBool accepts the usual spreadsheet spellings of yes and no, an empty cell is no
+func parseImportBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "", "0", "false", "no", "n":
+		return false, nil
+	case "1", "true", "yes", "y", "x":
+		return true, nil
+	}
+	return false, fmt.Errorf("%q is not yes or no", value)
+}
+
+// importColumn sets a delivery field from a trimmed cell value
+type importColumn func(delivery *model.Delivery, value string) error
+
+func textColumn(field func(*model.Delivery) *string) importColumn {
+	return func(delivery *model.Delivery, value string) error {
+		*field(delivery) = value
+		return nil
+	}
+}
+
+func floatColumn(field func(*model.Delivery) *float64) importColumn {
+	return func(delivery *model.Delivery, value string) error {
+		if value == "" {
+			return nil
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		*field(delivery) = parsed
+		return nil
+	}
+}
+
+func timeColumn(field func(*model.Delivery) **time.Time) importColumn {
+	return func(delivery *model.Delivery, value string) error {
+		if value == "" {
+			return nil
+		}
+		parsed, err := parseImportTime(value)
+		if err != nil {
+			return err
+		}
+		*field(delivery) = &parsed
+		return nil
+	}
+}
+
+// importColumns maps header names to the delivery fields they fill
+var importColumns = map[string]importColumn{
+	"pickup_address_line1":  textColumn(func(d *model.Delivery) *string { return &d.Pickup.AddressLine1 }),
+	"pickup_address_line2":  textColumn(func(d *model.Delivery) *string { return &d.Pickup.AddressLine2 }),
+	"pickup_city":           textColumn(func(d *model.Delivery) *string { return &d.Pickup.City }),
+	"pickup_postcode":       textColumn(func(d *model.Delivery) *string { return &d.Pickup.Postcode }),
+	"pickup_lat":            floatColumn(func(d *model.Delivery) *float64 { return &d.Pickup.Lat }),
+	"pickup_lon":            floatColumn(func(d *model.Delivery) *float64 { return &d.Pickup.Lon }),
+	"dropoff_address_line1": textColumn(func(d *model.Delivery) *string { return &d.Dropoff.AddressLine1 }),
+	"dropoff_address_line2": textColumn(func(d *model.Delivery) *string { return &d.Dropoff.AddressLine2 }),
+	"dropoff_city":          textColumn(func(d *model.Delivery) *string { return &d.Dropoff.City }),
+	"dropoff_postcode":      textColumn(func(d *model.Delivery) *string { return &d.Dropoff.Postcode }),
+	"dropoff_lat":           floatColumn(func(d *model.Delivery) *float64 { return &d.Dropoff.Lat }),
+	"dropoff_lon":           floatColumn(func(d *model.Delivery) *float64 { return &d.Dropoff.Lon }),
+	"recipient_name":        textColumn(func(d *model.Delivery) *string { return &d.Recipient.Name }),
+	"recipient_phone":       textColumn(func(d *model.Delivery) *string { return &d.Recipient.Phone }),
+	"access_notes":          textColumn(func(d *model.Delivery) *string { return &d.Recipient.AccessNotes }),
+	"window_start":          timeColumn(func(d *model.Delivery) **time.Time { return &d.WindowStart }),
+	"window_end":            timeColumn(func(d *model.Delivery) **time.Time { return &d.WindowEnd }),
+	"weight_kg":             floatColumn(func(d *model.Delivery) *float64 { return &d.WeightKg }),
+	"cod_amount":            floatColumn(func(d *model.Delivery) *float64 { return &d.CodAmount }),
+	"delivery_comment":      textColumn(func(d *model.Delivery) *string { return &d.DeliveryComment }),
+	"promo_code":            textColumn(func(d *model.Delivery) *string { return &d.PromoCode }),
+	"express": func(d *model.Delivery, value string) error {
+		var err error
+		d.Express, err = parseImportBool(value)
+		return err
+	},
+	"client_id": func(d *model.Delivery, value string) error {
+		if value == "" {
+			return nil
+		}
+		clientId, err := uuid.Parse(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a client id", value)
+		}
+		d.ClientId = &clientId
+		return nil
+	},
+}
+
+// requiredImportColumns have to be present in the header, their cells are checked like the fields of a single delivery
+var requiredImportColumns = []string{"pickup_address_line1", "pickup_city", "pickup_lat", "pickup_lon",
+	"dropoff_address_line1", "dropoff_city", "dropoff_lat", "dropoff_lon", "recipient_name", "recipient_phone", "window_start", "window_end"}
+
+// importHeader checks the header row and returns the normalized column names
+func importHeader(header []string) ([]string, error) {
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if importColumns[name] == nil {
+			return nil, fmt.Errorf("%w: unknown column %q", model.ErrValidation, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: column %q appears twice", model.ErrValidation, name)
+		}
+		seen[name] = true
+		columns[i] = name
+	}
+	for _, name := range requiredImportColumns {
+		if !seen[name] {
+			return nil, fmt.Errorf("%w: column %q is required", model.ErrValidation, name)
+		}
+	}
+	return columns, nil
+}
+
+// parseImportRow fills a delivery from a row, every cell that can not be parsed is reported
+func parseImportRow(rowNumber int, columns []string, row []string) (*model.Delivery, []*model.ImportRowError) {
+	delivery := &model.Delivery{}
+	var rowErrors []*model.ImportRowError
+	for i, value := range row {
+		if i >= len(columns) || columns[i] == "" {
+			continue
+		}
+		err := importColumns[columns[i]](delivery, strings.TrimSpace(value))
+		if err != nil {
+			rowErrors = append(rowErrors, &model.ImportRowError{Row: rowNumber, Column: columns[i], Message: err.Error()})
+		}
+	}
+	return delivery, rowErrors
+}
+
+// blankRow reports rows without any value, spreadsheets often end with some
+func blankRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+// trackingCodeAlphabet is Crockford's base32, it has no easily confused letters
+const (
+	trackingCodeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	trackingCodeLength   = 12
+)
+
+type TrackingService struct {
+	rps TrackingRepository
+}
+
+func NewTrackingService(rps TrackingRepository) *TrackingService {
+	return &TrackingService{rps: rps}
+}
+
+type TrackingRepository interface {
+	GetDeliveryByID(ctx context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error)
+	GetTrackingView(ctx context.Context, code string) (*model.TrackingView, error)
+	ReissueTrackingCode(ctx context.Context, deliveryId uuid.UUID, code string) error
+	RevokeTrackingCodes(ctx context.Context, deliveryId uuid.UUID) error
+}
+
+// newTrackingCode returns a random 60-bit code
+func newTrackingCode() (string, error) {
+	buf := make([]byte, trackingCodeLength)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", fmt.Errorf("Read(): %w", err)
+	}
+	for i := range buf {
+		buf[i] = trackingCodeAlphabet[buf[i]&31]
+	}
+	return string(buf), nil
+}
+
+// normalizeTrackingCode upper-cases the code and reports whether it is well-formed
+func normalizeTrackingCode(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != trackingCodeLength {
+		return "", false
+	}
+	for _, r := range code {
+		if !strings.ContainsRune(trackingCodeAlphabet, r) {
+			return "", false
+		}
+	}
+	return code, true
+}
+
+// Track returns the redacted delivery view for a tracking code
+func (srv *TrackingService) Track(ctx context.Context, code string) (*model.TrackingView, error) {
+	code, ok := normalizeTrackingCode(code)
+	if !ok {
+		return nil, fmt.Errorf("Track: %w", model.ErrNotFound)
+	}
+	view, err := srv.rps.GetTrackingView(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("GetTrackingView: %w", err)
+	}
+	switch {
+	case deliveryClosed(view.Status):
+		view.ETA = nil
+	case view.ETA == nil:
+		view.ETA = view.WindowEnd
+	}
+	if view.Return != nil {
+		switch {
+		case deliveryClosed(view.Return.Status):
+			view.Return.ETA = nil
+		case view.Return.ETA == nil:
+			view.Return.ETA = view.Return.WindowEnd
+		}
+	}
+	return view, nil
+}
+
+// ReissueCode revokes the current tracking code of the delivery and issues a new one
+func (srv *TrackingService) ReissueCode(ctx context.Context, deliveryId uuid.UUID) (string, error) {
+	_, err := srv.rps.GetDeliveryByID(ctx, deliveryId)
+	if err != nil {
+		return "", fmt.Errorf("GetDeliveryByID: %w", err)
+	}
+	code, err := newTrackingCode()
+	if err != nil {
+		return "", fmt.Errorf("newTrackingCode: %w", err)
+	}
+	err = srv.rps.ReissueTrackingCode(ctx, deliveryId, code)
+	if err != nil {
+		return "", fmt.Errorf("ReissueTrackingCode: %w", err)
+	}
+	return code, nil
+}
+
+// RevokeCodes makes every tracking code of the delivery unusable
+func (srv *TrackingService) RevokeCodes(ctx context.Context, deliveryId uuid.UUID) error {
+	err := srv.rps.RevokeTrackingCodes(ctx, deliveryId)
+	if err != nil {
+		return fmt.Errorf("RevokeTrackingCodes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTrackingRepository struct {
+	delivery *model.DeliveryGet
+	codes    map[uuid.UUID]string
+	view     *model.TrackingView
+}
+
+func (r *fakeTrackingRepository) GetDeliveryByID(_ context.Context, deliveryId uuid.UUID) (*model.DeliveryGet, error) {
+	if r.delivery == nil || r.delivery.Id != deliveryId {
+		return nil, model.ErrNotFound
+	}
+	return r.delivery, nil
+}
+
+func (r *fakeTrackingRepository) GetTrackingView(_ context.Context, _ string) (*model.TrackingView, error) {
+	if r.view == nil {
+		return nil, model.ErrNotFound
+	}
+	return r.view, nil
+}
+
+func (r *fakeTrackingRepository) ReissueTrackingCode(_ context.Context, deliveryId uuid.UUID, code string) error {
+	r.codes[deliveryId] = code
+	return nil
+}
+
+func (r *fakeTrackingRepository) RevokeTrackingCodes(_ context.Context, deliveryId uuid.UUID) error {
+	delete(r.codes, deliveryId)
+	return nil
+}
+
+// TestReissueCode checks that a new code is stored for a known delivery and that an unknown one is not found
+func TestReissueCode(t *testing.T) {
+	rps := &fakeTrackingRepository{delivery: &model.DeliveryGet{Id: uuid.New()}, codes: make(map[uuid.UUID]string)}
+	srv := NewTrackingService(rps)
+
+	code, err := srv.ReissueCode(context.Background(), rps.delivery.Id)
+	require.NoError(t, err)
+	require.Len(t, code, trackingCodeLength)
+	require.Equal(t, code, rps.codes[rps.delivery.Id])
+
+	_, err = srv.ReissueCode(context.Background(), uuid.New())
+	require.ErrorIs(t, err, model.ErrNotFound)
+	require.Len(t, rps.codes, 1)
+}
+
+// TestTrackReturnedDelivery checks that the original code follows the return leg and its ETA until it is delivered
+func TestTrackReturnedDelivery(t *testing.T) {
+	windowEnd := time.Date(2024, time.December, 13, 18, 0, 0, 0, time.UTC)
+	rps := &fakeTrackingRepository{view: &model.TrackingView{Status: model.DeliveryStatusReturned, ETA: &windowEnd,
+		Return: &model.TrackingReturn{Status: model.DeliveryStatusPickedUp, WindowEnd: &windowEnd}}}
+	srv := NewTrackingService(rps)
+
+	view, err := srv.Track(context.Background(), "ABCDEFGHJKMN")
+	require.NoError(t, err)
+	require.Nil(t, view.ETA)
+	require.Equal(t, &windowEnd, view.Return.ETA)
+
+	rps.view.Return = &model.TrackingReturn{Status: model.DeliveryStatusDelivered, WindowEnd: &windowEnd, ETA: &windowEnd, DeliveredAt: &windowEnd}
+	view, err = srv.Track(context.Background(), "ABCDEFGHJKMN")
+	require.NoError(t, err)
+	require.Nil(t, view.Return.ETA)
+	require.Equal(t, &windowEnd, view.Return.DeliveredAt)
+}
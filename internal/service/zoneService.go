@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/liza/labwork_45/internal/model"
+)
+
+type ZoneService struct {
+	rps   ZoneRepository
+	clock Clock
+	// postgis locates zones with ST_Contains in the database instead of in Go
+	postgis bool
+}
+
+func NewZoneService(rps ZoneRepository, clock Clock, postgis bool) *ZoneService {
+	return &ZoneService{rps: rps, clock: clock, postgis: postgis}
+}
+
+type ZoneRepository interface {
+	GetCourierByUserID(context.Context, uuid.UUID) (*model.Courier, error)
+	InsertZone(ctx context.Context, zone *model.Zone) error
+	UpdateZone(ctx context.Context, zone *model.Zone) error
+	GetZones(ctx context.Context, activeOnly bool) ([]*model.Zone, error)
+	LocateZonePostGIS(ctx context.Context, point model.GeoPoint) (*model.Zone, error)
+	SetCourierZones(ctx context.Context, courierId uuid.UUID, zoneIds []uuid.UUID) error
+	GetCourierZones(ctx context.Context, courierId uuid.UUID) ([]*model.Zone, error)
+}
+
+// ZoneLocator finds the active zone containing a point, it returns nil outside every zone
+type ZoneLocator interface {
+	Locate(ctx context.Context, point model.GeoPoint) (*model.Zone, error)
+}
+
+// checkHomeZone lets couriers without home zones claim deliveries anywhere, other couriers only
+// deliveries of their home zones and deliveries picked up outside every zone
+func checkHomeZone(zones []*model.Zone, delivery *model.DeliveryGet) error {
+	if len(zones) == 0 || delivery.ZoneId == nil {
+		return nil
+	}
+	for _, zone := range zones {
+		if zone.Id == *delivery.ZoneId {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: delivery is outside the courier's home zones", model.ErrForbidden)
+}
+
+// validateZone normalizes the code and name and checks the boundary, time zone and hours
+func validateZone(zone *model.Zone) error {
+	zone.Code = strings.TrimSpace(zone.Code)
+	zone.Name = strings.TrimSpace(zone.Name)
+	if zone.Code == "" || zone.Code == model.DefaultTariffZone {
+		return fmt.Errorf("%w: code is required and must not be %q", model.ErrValidation, model.DefaultTariffZone)
+	}
+	if zone.Name == "" {
+		zone.Name = zone.Code
+	}
+	_, err := parseGeometry(zone.Boundary)
+	if err != nil {
+		return fmt.Errorf("boundary: %w", err)
+	}
+	if zone.TimeZone == "" {
+		zone.TimeZone = "UTC"
+	}
+	_, err = time.LoadLocation(zone.TimeZone)
+	if err != nil {
+		return fmt.Errorf("%w: unknown time_zone %q", model.ErrValidation, zone.TimeZone)
+	}
+	return validateZoneHours(zone.Hours)
+}
+
+// CreateZone adds an active zone
+func (srv *ZoneService) CreateZone(ctx context.Context, zone *model.Zone) (*model.Zone, error) {
+	err := validateZone(zone)
+	if err != nil {
+		return nil, fmt.Errorf("validateZone: %w", err)
+	}
+	zone.Id = uuid.New()
+	zone.Active = true
+	zone.CreatedAt = srv.clock.Now().UTC()
+	zone.UpdatedAt = zone.CreatedAt
+	err = srv.rps.InsertZone(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("InsertZone: %w", err)
+	}
+	return zone, nil
+}
+
+// UpdateZone replaces the zone's definition, deliveries keep the zone they were created in
+func (srv *ZoneService) UpdateZone(ctx context.Context, zone *model.Zone) (*model.Zone, error) {
+	err := validateZone(zone)
+	if err != nil {
+		return nil, fmt.Errorf("validateZone: %w", err)
+	}
+	zone.UpdatedAt = srv.clock.Now().UTC()
+	err = srv.rps.UpdateZone(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateZone: %w", err)
+	}
+	return zone, nil
+}
+
+// GetZones returns every zone including inactive ones
+func (srv *ZoneService) GetZones(ctx context.Context) ([]*model.Zone, error) {
+	zones, err := srv.rps.GetZones(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("GetZones: %w", err)
+	}
+	return zones, nil
+}
+
+// Locate returns the smallest active zone containing the point, or nil outside every zone
+func (srv *ZoneService) Locate(ctx context.Context, point model.GeoPoint) (*model.Zone, error) {
+	if srv.postgis {
+		zone, err := srv.rps.LocateZonePostGIS(ctx, point)
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("LocateZonePostGIS: %w", err)
+		}
+		return zone, nil
+	}
+	zones, err := srv.rps.GetZones(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("GetZones: %w", err)
+	}
+	return locateZone(zones, point), nil
+}
+
+// SetCourierZones replaces the home zones of a courier, they only see and claim deliveries of those zones
+func (srv *ZoneService) SetCourierZones(ctx context.Context, request *model.CourierZones) ([]*model.Zone, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, request.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	err = srv.rps.SetCourierZones(ctx, courier.Id, request.ZoneIds)
+	if err != nil {
+		return nil, fmt.Errorf("SetCourierZones: %w", err)
+	}
+	zones, err := srv.rps.GetCourierZones(ctx, courier.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierZones: %w", err)
+	}
+	return zones, nil
+}
+
+// GetCourierZones returns the home zones of the courier with the given user id
+func (srv *ZoneService) GetCourierZones(ctx context.Context, userId uuid.UUID) ([]*model.Zone, error) {
+	courier, err := srv.rps.GetCourierByUserID(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierByUserID: %w", err)
+	}
+	zones, err := srv.rps.GetCourierZones(ctx, courier.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GetCourierZones: %w", err)
+	}
+	return zones, nil
+}
@@ -10,6 +10,7 @@ import (
 	echoSwagger "github.com/swaggo/echo-swagger"
 
 	_ "github.com/liza/labwork_45/docs"
+	"github.com/liza/labwork_45/internal/blobstore"
 	configuration "github.com/liza/labwork_45/internal/config"
 	"github.com/liza/labwork_45/internal/handlers"
 	"github.com/liza/labwork_45/internal/middleware"
@@ -55,6 +56,11 @@ func main() {
 		return
 	}
 
+	e.IPExtractor, err = middleware.NewIPExtractor(cfg.TrustedProxies)
+	if err != nil {
+		e.Logger.Fatal(fmt.Errorf("error configuring trusted proxies: %w", err))
+	}
+
 	pool, err := NewDBPsql(cfg.PgxDBAddr)
 	if err != nil {
 		err := echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("error creating database connection with PostgreSQL: %w", err))
@@ -62,6 +68,60 @@ func main() {
 	}
 
 	rps := repository.NewPsqlConnection(pool)
+	fees, err := service.NewCancellationFees(cfg.CancellationFees)
+	if err != nil {
+		e.Logger.Fatal(fmt.Errorf("error configuring cancellation fees: %w", err))
+	}
+
+	dispatcher := service.NewDispatcher(rps, service.DefaultScorer, service.SystemClock{}, cfg.DispatchOfferTimeout, cfg.DispatchReofferAfter)
+	// the dispatcher loop also expires unanswered offers and passes them to the next courier
+	if cfg.DispatchInterval > 0 {
+		go dispatcher.Run(context.Background(), cfg.DispatchInterval)
+	}
+	offerHandler := handlers.NewOfferHandler(service.NewOfferService(rps, dispatcher, service.SystemClock{}))
+	performance := service.NewPerformanceService(rps, service.SystemClock{}, cfg.PerformanceWindow)
+	if cfg.PerformanceInterval > 0 {
+		go performance.Run(context.Background(), cfg.PerformanceInterval)
+	}
+	performanceHandler := handlers.NewPerformanceHandler(performance)
+	planner := service.NewRoutePlanner(service.DefaultDistance, cfg.EtaSpeeds)
+	routeHandler := handlers.NewRouteHandler(service.NewRouteService(rps, planner, service.SystemClock{}))
+	eta := service.NewEtaService(rps, planner, service.SystemClock{})
+	positions := service.NewPositionService(rps, eta, service.SystemClock{}, cfg.LocationRetention)
+	if cfg.LocationPruneInterval > 0 {
+		go positions.Run(context.Background(), cfg.LocationPruneInterval)
+	}
+	positionHandler := handlers.NewPositionHandler(positions)
+	events := service.NewEventHub(rps, service.SystemClock{}, cfg.SigningKey)
+	go events.Run(context.Background())
+	eventHandler := handlers.NewEventHandler(events)
+	availabilityHandler := handlers.NewAvailabilityHandler(service.NewAvailabilityService(rps, service.SystemClock{}))
+	blobs, err := blobstore.NewLocalStore(cfg.BlobStoreDir)
+	if err != nil {
+		e.Logger.Fatal(fmt.Errorf("error opening blob store: %w", err))
+	}
+	proofs, err := service.NewProofService(rps, blobs, service.SystemClock{}, cfg.DeliveryProof)
+	if err != nil {
+		e.Logger.Fatal(fmt.Errorf("error configuring delivery proof: %w", err))
+	}
+	proofHandler := handlers.NewProofHandler(proofs)
+	zones := service.NewZoneService(rps, service.SystemClock{}, cfg.ZonePostGIS)
+	zoneHandler := handlers.NewZoneHandler(zones)
+	pricing := service.NewPricingService(rps, service.DefaultDistance, service.SystemClock{}, zones)
+	pricingHandler := handlers.NewPricingHandler(pricing)
+	cashHandler := handlers.NewCashHandler(service.NewCashService(rps, service.SystemClock{}))
+	rules, err := service.NewPayoutRules(cfg.PayoutRules)
+	if err != nil {
+		e.Logger.Fatal(fmt.Errorf("error configuring payout rules: %w", err))
+	}
+	earnings := service.NewEarningService(rps, rules, service.DefaultDistance, service.SystemClock{})
+	if cfg.EarningsInterval > 0 {
+		go earnings.Run(context.Background(), cfg.EarningsInterval)
+	}
+	earningHandler := handlers.NewEarningHandler(earnings)
+	ratingHandler := handlers.NewRatingHandler(service.NewRatingService(rps, service.SystemClock{}, cfg.RatingWindow, cfg.LowRatingScore))
+	hubHandler := handlers.NewHubHandler(service.NewHubService(rps, service.SystemClock{}))
+	attemptHandler := handlers.NewAttemptHandler(service.NewAttemptService(rps, service.SystemClock{}, cfg.MaxDeliveryAttempts, cfg.RescheduleLeadTime))
 
 	auth := e.Group("/auth")
 	{
@@ -78,22 +138,136 @@ func main() {
 	}
 	courier := e.Group("/courier")
 	{
-		srv := service.NewCourierService(rps)
+		srv := service.NewCourierService(rps, pricing, service.SystemClock{})
 		handler := handlers.NewCourierHandler(srv)
 
 		courier.PATCH("/updatecourier", handler.UpdateCourier, middleware.CourierIdentity())
 		courier.GET("/getalldeliveries", handler.GetAlldeliveries, middleware.CourierIdentity())
+		courier.GET("/delivery/:id", handler.GetDelivery, middleware.CourierIdentity())
 		courier.PATCH("/choose_availible_delivery", handler.ChooseAvailibleDelivery, middleware.CourierIdentity())
 		courier.PATCH("/update_delivery_status", handler.UpdateDeliveryStatus, middleware.CourierIdentity())
+		courier.POST("/deliver", proofHandler.CompleteDelivery, middleware.CourierIdentity())
+		courier.POST("/failed_attempt", attemptHandler.FailAttempt, middleware.CourierIdentity())
+
+		courier.GET("/offers", offerHandler.GetOffers, middleware.CourierIdentity())
+		courier.PATCH("/accept_offer", offerHandler.AcceptOffer, middleware.CourierIdentity())
+		courier.PATCH("/decline_offer", offerHandler.DeclineOffer, middleware.CourierIdentity())
+
+		courier.PATCH("/go_online", availabilityHandler.GoOnline, middleware.CourierIdentity())
+		courier.PATCH("/go_offline", availabilityHandler.GoOffline, middleware.CourierIdentity())
+		courier.GET("/shifts", availabilityHandler.GetMyShifts, middleware.CourierIdentity())
+		courier.GET("/performance", performanceHandler.GetMyPerformance, middleware.CourierIdentity())
+		courier.POST("/locations", positionHandler.PostLocations, middleware.CourierIdentity())
+		courier.GET("/route", routeHandler.GetMyRoute, middleware.CourierIdentity())
+		courier.GET("/cash", cashHandler.GetMyCash, middleware.CourierIdentity())
+		courier.GET("/statements", earningHandler.GetMyStatements, middleware.CourierIdentity())
+		courier.GET("/statement/:id", earningHandler.GetMyStatement, middleware.CourierIdentity())
+		courier.GET("/legs", hubHandler.GetMyLegs, middleware.CourierIdentity())
+		courier.PATCH("/claim_leg", hubHandler.ClaimLeg, middleware.CourierIdentity())
+		courier.PATCH("/pickup_leg", hubHandler.PickUpLeg, middleware.CourierIdentity())
+	}
+
+	manager := e.Group("/manager")
+	{
+		srv := service.NewManagerService(rps, fees, service.SystemClock{})
+		handler := handlers.NewManagerHandler(srv)
+
+		manager.GET("/couriers", handler.GetCouriers, middleware.ManagerIdentity())
+		manager.PATCH("/assign_delivery", handler.AssignDelivery, middleware.ManagerIdentity())
+		manager.PATCH("/unassign_delivery", handler.UnassignDelivery, middleware.ManagerIdentity())
+		manager.PATCH("/cancel_delivery", handler.CancelDelivery, middleware.ManagerIdentity())
+		manager.GET("/delivery_cancellation/:id", handler.GetCancellation, middleware.ManagerIdentity())
+		manager.PATCH("/courier_status", handler.SetCourierStatus, middleware.ManagerIdentity())
+		manager.PATCH("/courier", handler.UpdateCourier, middleware.ManagerIdentity())
+		manager.PATCH("/courier_capacity", availabilityHandler.SetCourierCapacity, middleware.ManagerIdentity())
+		manager.POST("/courier_shift", availabilityHandler.PlanShift, middleware.ManagerIdentity())
+		manager.DELETE("/courier_shift", availabilityHandler.CancelShift, middleware.ManagerIdentity())
+		manager.GET("/courier_performance/:userid", performanceHandler.GetCourierPerformance, middleware.ManagerIdentity())
+		manager.GET("/courier_positions", positionHandler.GetCourierPositions, middleware.ManagerIdentity())
+		manager.GET("/delivery_track/:id", positionHandler.GetDeliveryTrack, middleware.ManagerIdentity())
+		manager.GET("/courier_route/:userid", routeHandler.PreviewCourierRoute, middleware.ManagerIdentity())
+		manager.GET("/delivery_proof/:id", proofHandler.GetDeliveryProofs, middleware.ManagerIdentity())
+		manager.GET("/delivery_proof_file/:id", proofHandler.GetProofFile, middleware.ManagerIdentity())
+		manager.PATCH("/reissue_delivery_pin", proofHandler.ReissuePin, middleware.ManagerIdentity())
+		manager.GET("/delivery_attempts/:id", attemptHandler.GetDeliveryAttempts, middleware.ManagerIdentity())
+		manager.GET("/delivery_price/:id", pricingHandler.GetDeliveryPrice, middleware.ManagerIdentity())
+		manager.POST("/cash_handover", cashHandler.RecordHandover, middleware.ManagerIdentity())
+		manager.GET("/cash_handovers/:userid", cashHandler.GetCashHandovers, middleware.ManagerIdentity())
+		manager.GET("/courier_cash/:userid", cashHandler.GetCourierCash, middleware.ManagerIdentity())
+		manager.GET("/cash_outstanding", cashHandler.GetOutstandingCash, middleware.ManagerIdentity())
+		manager.PATCH("/rating_moderation", ratingHandler.ModerateRating, middleware.ManagerIdentity())
+		manager.GET("/courier_ratings/:userid", ratingHandler.GetCourierRatings, middleware.ManagerIdentity())
+		manager.GET("/rating_alerts", ratingHandler.GetRatingAlerts, middleware.ManagerIdentity())
+		manager.PUT("/courier_zones", zoneHandler.SetCourierZones, middleware.ManagerIdentity())
+		manager.GET("/courier_zones/:userid", zoneHandler.GetCourierZones, middleware.ManagerIdentity())
+		manager.POST("/delivery_route", hubHandler.RouteDelivery, middleware.ManagerIdentity())
+		manager.GET("/delivery_legs/:id", hubHandler.GetDeliveryLegs, middleware.ManagerIdentity())
+		manager.PATCH("/assign_leg", hubHandler.AssignLeg, middleware.ManagerIdentity())
+
+		dispatchHandler := handlers.NewDispatchHandler(dispatcher)
+		manager.POST("/dispatch", dispatchHandler.Dispatch, middleware.ManagerIdentity())
+		manager.GET("/offer_metrics", offerHandler.GetOfferMetrics, middleware.ManagerIdentity())
+	}
+	client := e.Group("/client")
+	{
+		srv := service.NewClientService(rps, fees, pricing, service.SystemClock{})
+		handler := handlers.NewClientHandler(srv)
+
+		client.POST("/create_delivery", handler.CreateDelivery, middleware.UserIdentity())
+		client.GET("/getmydeliveries", handler.GetMyDeliveries, middleware.UserIdentity())
+		client.GET("/getdelivery/:id", handler.GetDelivery, middleware.UserIdentity())
+		client.PATCH("/cancel_delivery", handler.CancelDelivery, middleware.UserIdentity())
+		client.PATCH("/reissue_tracking_code", handler.ReissueTrackingCode, middleware.UserIdentity())
+		client.POST("/rate_delivery", ratingHandler.RateDelivery, middleware.UserIdentity())
 	}
 
 	delivery := e.Group("/delivery")
 	{
-		srv := service.NewCourierService(rps)
+		srv := service.NewCourierService(rps, pricing, service.SystemClock{})
 		handler := handlers.NewCourierHandler(srv)
 
 		delivery.POST("/create_delivary", handler.CreateDelivery, middleware.AdminIdentity())
+		delivery.POST("/quote", pricingHandler.Quote, middleware.UserIdentity())
+		delivery.POST("/tariff", pricingHandler.CreateTariff, middleware.AdminIdentity())
+		delivery.GET("/tariffs", pricingHandler.GetTariffs, middleware.AdminIdentity())
+		delivery.POST("/promo", pricingHandler.CreatePromo, middleware.AdminIdentity())
+
+		importHandler := handlers.NewImportHandler(service.NewImportService(rps, pricing, service.SystemClock{}, cfg.ImportBatchSize))
+		delivery.POST("/import", importHandler.ImportDeliveries, middleware.AdminIdentity())
+
+		trackingHandler := handlers.NewTrackingHandler(service.NewTrackingService(rps))
+		delivery.PATCH("/reissue_tracking_code", trackingHandler.ReissueCode, middleware.AdminIdentity())
+		delivery.PATCH("/revoke_tracking_code", trackingHandler.RevokeCodes, middleware.AdminIdentity())
+	}
+	admin := e.Group("/admin")
+	{
+		admin.POST("/earning_adjustment", earningHandler.AdjustEarnings, middleware.AdminIdentity())
+		admin.GET("/statements", earningHandler.GetStatements, middleware.AdminIdentity())
+		admin.GET("/statements_csv", earningHandler.ExportStatements, middleware.AdminIdentity())
+		admin.POST("/zone", zoneHandler.CreateZone, middleware.AdminIdentity())
+		admin.PUT("/zone/:id", zoneHandler.UpdateZone, middleware.AdminIdentity())
+		admin.GET("/zones", zoneHandler.GetZones, middleware.AdminIdentity())
+		admin.GET("/zone_lookup", zoneHandler.LocateZone, middleware.AdminIdentity())
+		admin.POST("/hub", hubHandler.CreateHub, middleware.AdminIdentity())
+		admin.GET("/hubs", hubHandler.GetHubs, middleware.AdminIdentity())
+		admin.PUT("/hub_staff", hubHandler.SetHubStaff, middleware.AdminIdentity())
+	}
+	hub := e.Group("/hub")
+	{
+		hub.POST("/check_in", hubHandler.CheckIn, middleware.HubStaffIdentity())
+		hub.POST("/check_out", hubHandler.CheckOut, middleware.HubStaffIdentity())
+		hub.GET("/inventory", hubHandler.GetHubInventory, middleware.HubStaffIdentity())
+	}
+	track := e.Group("/track")
+	{
+		srv := service.NewTrackingService(rps)
+		handler := handlers.NewTrackingHandler(srv)
+
+		track.GET("/:code", handler.Track, middleware.RateLimit(cfg.TrackRateLimit, cfg.TrackRateBurst))
+		track.POST("/:code/rating", ratingHandler.RateByTrackingCode, middleware.RateLimit(cfg.TrackRateLimit, cfg.TrackRateBurst))
 	}
+	e.GET("/events", eventHandler.StreamEvents, middleware.EventsIdentity())
+	e.POST("/events/ticket", eventHandler.IssueTicket, middleware.AnyIdentity())
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
 	e.Logger.Fatal(e.Start(":8080"))
@@ -0,0 +1,18 @@
+CREATE TABLE labwork.courier_performance (
+	courier_id uuid NOT NULL,
+	performance_indicator int4 NOT NULL,
+	on_time_rate double precision NULL,
+	failed_rate double precision NULL,
+	acceptance_rate double precision NULL,
+	average_rating double precision NULL,
+	completed int4 NOT NULL,
+	on_time int4 NOT NULL,
+	failed int4 NOT NULL,
+	offered int4 NOT NULL,
+	accepted int4 NOT NULL,
+	ratings int4 NOT NULL,
+	window_start timestamptz NOT NULL,
+	calculated_at timestamptz NOT NULL,
+	CONSTRAINT courier_performance_pk PRIMARY KEY (courier_id),
+	CONSTRAINT courier_performance_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id) ON DELETE CASCADE
+);
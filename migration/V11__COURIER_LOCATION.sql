@@ -0,0 +1,36 @@
+-- raw GPS fixes, pruned after the retention period
+CREATE TABLE labwork.courier_location (
+	courier_id uuid NOT NULL,
+	recorded_at timestamptz NOT NULL,
+	lat double precision NOT NULL,
+	lon double precision NOT NULL,
+	accuracy_m double precision NULL,
+	speed_mps double precision NULL,
+	received_at timestamptz NOT NULL,
+	CONSTRAINT courier_location_pk PRIMARY KEY (courier_id, recorded_at),
+	CONSTRAINT courier_location_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id) ON DELETE CASCADE
+);
+
+CREATE INDEX courier_location_recorded_at_idx ON labwork.courier_location (recorded_at);
+
+CREATE TABLE labwork.courier_position (
+	courier_id uuid NOT NULL,
+	recorded_at timestamptz NOT NULL,
+	lat double precision NOT NULL,
+	lon double precision NOT NULL,
+	accuracy_m double precision NULL,
+	speed_mps double precision NULL,
+	received_at timestamptz NOT NULL,
+	CONSTRAINT courier_position_pk PRIMARY KEY (courier_id),
+	CONSTRAINT courier_position_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id) ON DELETE CASCADE
+);
+
+-- downsampled route per delivery, kept together with the delivery
+CREATE TABLE labwork.delivery_track (
+	delivery_id uuid NOT NULL,
+	recorded_at timestamptz NOT NULL,
+	lat double precision NOT NULL,
+	lon double precision NOT NULL,
+	CONSTRAINT delivery_track_pk PRIMARY KEY (delivery_id, recorded_at),
+	CONSTRAINT delivery_track_delivery_id_fkey FOREIGN KEY (delivery_id) REFERENCES labwork.delivery(id) ON DELETE CASCADE
+);
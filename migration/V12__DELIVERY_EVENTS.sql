@@ -0,0 +1,51 @@
+-- delivery changes are published on the delivery_events channel so that every app instance can stream them
+CREATE OR REPLACE FUNCTION labwork.notify_delivery_event() RETURNS trigger AS $$
+DECLARE
+	kind varchar;
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		kind := 'created';
+	ELSIF NEW.courier_id IS DISTINCT FROM OLD.courier_id THEN
+		kind := 'assignment';
+	ELSIF NEW.delivery_status IS DISTINCT FROM OLD.delivery_status THEN
+		kind := 'status';
+	ELSE
+		RETURN NEW;
+	END IF;
+	PERFORM pg_notify('delivery_events', json_build_object(
+		'type', kind,
+		'delivery_id', NEW.id,
+		'client_id', NEW.client_id,
+		'courier_id', NEW.courier_id,
+		'status', NEW.delivery_status,
+		'at', now())::text);
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER delivery_event_trigger AFTER INSERT OR UPDATE ON labwork.delivery
+	FOR EACH ROW EXECUTE FUNCTION labwork.notify_delivery_event();
+
+-- a new courier position is published for every delivery the courier is carrying
+CREATE OR REPLACE FUNCTION labwork.notify_courier_position() RETURNS trigger AS $$
+DECLARE
+	d record;
+BEGIN
+	FOR d IN SELECT id, client_id, delivery_status FROM labwork.delivery
+		WHERE courier_id = NEW.courier_id AND delivery_status NOT IN ('delivered', 'cancelled')
+	LOOP
+		PERFORM pg_notify('delivery_events', json_build_object(
+			'type', 'position',
+			'delivery_id', d.id,
+			'client_id', d.client_id,
+			'courier_id', NEW.courier_id,
+			'status', d.delivery_status,
+			'position', json_build_object('lat', NEW.lat, 'lon', NEW.lon),
+			'at', NEW.recorded_at)::text);
+	END LOOP;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER courier_position_event_trigger AFTER INSERT OR UPDATE ON labwork.courier_position
+	FOR EACH ROW EXECUTE FUNCTION labwork.notify_courier_position();
@@ -0,0 +1,5 @@
+ALTER TABLE labwork.courier
+	ADD COLUMN vehicle_type varchar NOT NULL DEFAULT 'bicycle',
+	ADD CONSTRAINT courier_vehicle_type_check CHECK (vehicle_type IN ('foot', 'bicycle', 'scooter', 'car'));
+
+ALTER TABLE labwork.delivery ADD COLUMN eta timestamptz NULL;
@@ -0,0 +1,32 @@
+-- one-time PIN the recipient gives the courier, locked after too many wrong attempts
+CREATE TABLE labwork.delivery_pin (
+	delivery_id uuid NOT NULL,
+	pin varchar NOT NULL,
+	attempts int NOT NULL DEFAULT 0,
+	used_at timestamptz NULL,
+	CONSTRAINT delivery_pin_pk PRIMARY KEY (delivery_id),
+	CONSTRAINT delivery_pin_delivery_id_fkey FOREIGN KEY (delivery_id) REFERENCES labwork.delivery(id) ON DELETE CASCADE
+);
+
+-- proof artifacts live in the blob store, rows keep their key and capture metadata
+CREATE TABLE labwork.delivery_proof (
+	id uuid NOT NULL,
+	delivery_id uuid NOT NULL,
+	courier_id uuid NOT NULL,
+	event varchar NOT NULL,
+	kind varchar NOT NULL,
+	blob_key varchar NULL,
+	content_type varchar NULL,
+	size_bytes bigint NULL,
+	sha256 varchar NULL,
+	captured_at timestamptz NOT NULL,
+	lat double precision NULL,
+	lon double precision NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT delivery_proof_pk PRIMARY KEY (id),
+	CONSTRAINT delivery_proof_kind_check CHECK (kind IN ('photo', 'signature', 'pin')),
+	CONSTRAINT delivery_proof_delivery_id_fkey FOREIGN KEY (delivery_id) REFERENCES labwork.delivery(id) ON DELETE CASCADE,
+	CONSTRAINT delivery_proof_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id)
+);
+
+CREATE INDEX delivery_proof_delivery_id_idx ON labwork.delivery_proof (delivery_id);
@@ -0,0 +1,42 @@
+ALTER TABLE labwork.delivery
+	ADD COLUMN attempts int NOT NULL DEFAULT 0,
+	ADD COLUMN return_of uuid NULL,
+	ADD CONSTRAINT delivery_return_of_fkey FOREIGN KEY (return_of) REFERENCES labwork.delivery(id) ON DELETE SET NULL;
+
+CREATE TABLE labwork.delivery_attempt (
+	id uuid NOT NULL,
+	delivery_id uuid NOT NULL,
+	courier_id uuid NOT NULL,
+	number int NOT NULL,
+	reason varchar NOT NULL,
+	comment varchar NOT NULL DEFAULT '',
+	attempted_at timestamptz NOT NULL,
+	lat double precision NULL,
+	lon double precision NULL,
+	CONSTRAINT delivery_attempt_pk PRIMARY KEY (id),
+	CONSTRAINT delivery_attempt_number_key UNIQUE (delivery_id, number),
+	CONSTRAINT delivery_attempt_reason_check CHECK (reason IN ('recipient_absent', 'address_not_found', 'access_denied', 'refused', 'unsafe_location', 'other')),
+	CONSTRAINT delivery_attempt_delivery_id_fkey FOREIGN KEY (delivery_id) REFERENCES labwork.delivery(id) ON DELETE CASCADE,
+	CONSTRAINT delivery_attempt_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id)
+);
+
+-- returned deliveries are closed, their courier is no longer carrying them
+CREATE OR REPLACE FUNCTION labwork.notify_courier_position() RETURNS trigger AS $$
+DECLARE
+	d record;
+BEGIN
+	FOR d IN SELECT id, client_id, delivery_status FROM labwork.delivery
+		WHERE courier_id = NEW.courier_id AND delivery_status NOT IN ('delivered', 'cancelled', 'returned')
+	LOOP
+		PERFORM pg_notify('delivery_events', json_build_object(
+			'type', 'position',
+			'delivery_id', d.id,
+			'client_id', d.client_id,
+			'courier_id', NEW.courier_id,
+			'status', d.delivery_status,
+			'position', json_build_object('lat', NEW.lat, 'lon', NEW.lon),
+			'at', NEW.recorded_at)::text);
+	END LOOP;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,54 @@
+CREATE TABLE labwork.delivery_cancellation (
+	delivery_id uuid NOT NULL,
+	cancelled_by uuid NOT NULL,
+	role varchar NOT NULL,
+	reason varchar NOT NULL,
+	comment varchar NOT NULL DEFAULT '',
+	state varchar NOT NULL,
+	fee numeric(10, 2) NOT NULL DEFAULT 0,
+	courier_id uuid NULL,
+	cancelled_at timestamptz NOT NULL,
+	CONSTRAINT delivery_cancellation_pk PRIMARY KEY (delivery_id),
+	CONSTRAINT delivery_cancellation_role_check CHECK (role IN ('client', 'manager')),
+	CONSTRAINT delivery_cancellation_reason_check CHECK (reason IN ('no_longer_needed', 'wrong_details', 'duplicate', 'too_late', 'operational', 'other')),
+	CONSTRAINT delivery_cancellation_state_check CHECK (state IN ('created', 'assigned', 'picked_up')),
+	CONSTRAINT delivery_cancellation_delivery_id_fkey FOREIGN KEY (delivery_id) REFERENCES labwork.delivery(id) ON DELETE CASCADE,
+	CONSTRAINT delivery_cancellation_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id) ON DELETE SET NULL
+);
+
+CREATE INDEX delivery_cancellation_courier_id_idx ON labwork.delivery_cancellation (courier_id, cancelled_at);
+
+ALTER TABLE labwork.delivery_offer
+	DROP CONSTRAINT delivery_offer_status_check,
+	ADD CONSTRAINT delivery_offer_status_check CHECK (status IN ('pending', 'accepted', 'declined', 'expired', 'withdrawn'));
+
+-- status changes take precedence so that a cancellation is published as such, and the courier who was
+-- unassigned is included so that their app learns about it
+CREATE OR REPLACE FUNCTION labwork.notify_delivery_event() RETURNS trigger AS $$
+DECLARE
+	kind varchar;
+	previous uuid;
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		kind := 'created';
+	ELSIF NEW.delivery_status IS DISTINCT FROM OLD.delivery_status THEN
+		kind := 'status';
+	ELSIF NEW.courier_id IS DISTINCT FROM OLD.courier_id THEN
+		kind := 'assignment';
+	ELSE
+		RETURN NEW;
+	END IF;
+	IF TG_OP = 'UPDATE' AND NEW.courier_id IS DISTINCT FROM OLD.courier_id THEN
+		previous := OLD.courier_id;
+	END IF;
+	PERFORM pg_notify('delivery_events', json_build_object(
+		'type', kind,
+		'delivery_id', NEW.id,
+		'client_id', NEW.client_id,
+		'courier_id', NEW.courier_id,
+		'previous_courier_id', previous,
+		'status', NEW.delivery_status,
+		'at', now())::text);
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
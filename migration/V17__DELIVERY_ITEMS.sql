@@ -0,0 +1,35 @@
+ALTER TABLE labwork.delivery
+	ADD COLUMN item_count int4 NOT NULL DEFAULT 0,
+	ADD COLUMN volume_l double precision NOT NULL DEFAULT 0,
+	ADD COLUMN max_side_cm double precision NOT NULL DEFAULT 0,
+	ADD COLUMN declared_value numeric(12, 2) NOT NULL DEFAULT 0,
+	ADD COLUMN fragile bool NOT NULL DEFAULT false,
+	ADD COLUMN temperature varchar NOT NULL DEFAULT 'ambient',
+	ADD CONSTRAINT delivery_item_count_check CHECK (item_count >= 0),
+	ADD CONSTRAINT delivery_volume_l_check CHECK (volume_l >= 0),
+	ADD CONSTRAINT delivery_max_side_cm_check CHECK (max_side_cm >= 0),
+	ADD CONSTRAINT delivery_declared_value_check CHECK (declared_value >= 0),
+	ADD CONSTRAINT delivery_temperature_check CHECK (temperature IN ('ambient', 'chilled', 'frozen'));
+
+CREATE TABLE labwork.delivery_item (
+	id uuid NOT NULL,
+	delivery_id uuid NOT NULL,
+	position int4 NOT NULL,
+	description varchar NOT NULL,
+	quantity int4 NOT NULL,
+	weight_kg double precision NOT NULL,
+	length_cm double precision NOT NULL,
+	width_cm double precision NOT NULL,
+	height_cm double precision NOT NULL,
+	declared_value numeric(12, 2) NOT NULL DEFAULT 0,
+	fragile bool NOT NULL DEFAULT false,
+	temperature varchar NOT NULL DEFAULT 'ambient',
+	CONSTRAINT delivery_item_pk PRIMARY KEY (id),
+	CONSTRAINT delivery_item_delivery_id_fkey FOREIGN KEY (delivery_id) REFERENCES labwork.delivery(id) ON DELETE CASCADE,
+	CONSTRAINT delivery_item_position_key UNIQUE (delivery_id, position),
+	CONSTRAINT delivery_item_quantity_check CHECK (quantity > 0),
+	CONSTRAINT delivery_item_weight_kg_check CHECK (weight_kg > 0),
+	CONSTRAINT delivery_item_dimensions_check CHECK (length_cm > 0 AND width_cm > 0 AND height_cm > 0),
+	CONSTRAINT delivery_item_declared_value_check CHECK (declared_value >= 0),
+	CONSTRAINT delivery_item_temperature_check CHECK (temperature IN ('ambient', 'chilled', 'frozen'))
+);
@@ -0,0 +1,67 @@
+CREATE TABLE labwork.tariff (
+	id uuid NOT NULL,
+	zone varchar NOT NULL,
+	version int4 NOT NULL,
+	valid_from timestamptz NOT NULL,
+	base_fee numeric(10, 2) NOT NULL DEFAULT 0,
+	per_km numeric(10, 2) NOT NULL DEFAULT 0,
+	per_kg numeric(10, 2) NOT NULL DEFAULT 0,
+	min_price numeric(10, 2) NOT NULL DEFAULT 0,
+	express_pct double precision NOT NULL DEFAULT 0,
+	fragile_fee numeric(10, 2) NOT NULL DEFAULT 0,
+	night_pct double precision NOT NULL DEFAULT 0,
+	night_start_hour int4 NOT NULL DEFAULT 22,
+	night_end_hour int4 NOT NULL DEFAULT 6,
+	time_zone varchar NOT NULL DEFAULT 'UTC',
+	created_at timestamptz NOT NULL,
+	CONSTRAINT tariff_pk PRIMARY KEY (id),
+	CONSTRAINT tariff_zone_version_key UNIQUE (zone, version),
+	CONSTRAINT tariff_amounts_check CHECK (base_fee >= 0 AND per_km >= 0 AND per_kg >= 0 AND min_price >= 0 AND fragile_fee >= 0),
+	CONSTRAINT tariff_pct_check CHECK (express_pct >= 0 AND night_pct >= 0),
+	CONSTRAINT tariff_night_hours_check CHECK (night_start_hour BETWEEN 0 AND 23 AND night_end_hour BETWEEN 0 AND 23)
+);
+
+CREATE INDEX tariff_zone_valid_from_idx ON labwork.tariff (zone, valid_from);
+
+-- deliveries in zones without a tariff of their own are priced with the default zone
+INSERT INTO labwork.tariff (id, zone, version, valid_from, base_fee, per_km, per_kg, min_price, express_pct, fragile_fee, night_pct, created_at)
+VALUES (gen_random_uuid(), '*', 1, now(), 3, 1, 0.5, 5, 50, 2, 25, now());
+
+CREATE TABLE labwork.promo_code (
+	code varchar NOT NULL,
+	percent_off double precision NOT NULL DEFAULT 0,
+	amount_off numeric(10, 2) NOT NULL DEFAULT 0,
+	valid_from timestamptz NULL,
+	valid_to timestamptz NULL,
+	max_uses int4 NULL,
+	used int4 NOT NULL DEFAULT 0,
+	CONSTRAINT promo_code_pk PRIMARY KEY (code),
+	CONSTRAINT promo_code_discount_check CHECK (percent_off BETWEEN 0 AND 100 AND amount_off >= 0),
+	CONSTRAINT promo_code_used_check CHECK (used >= 0 AND (max_uses IS NULL OR used <= max_uses))
+);
+
+ALTER TABLE labwork.delivery
+	ADD COLUMN express bool NOT NULL DEFAULT false,
+	ADD COLUMN price numeric(10, 2) NULL;
+
+CREATE TABLE labwork.delivery_price (
+	delivery_id uuid NOT NULL,
+	tariff_id uuid NOT NULL,
+	distance_km double precision NOT NULL,
+	weight_kg double precision NOT NULL,
+	base_fee numeric(10, 2) NOT NULL,
+	distance_fee numeric(10, 2) NOT NULL,
+	weight_fee numeric(10, 2) NOT NULL,
+	express_surcharge numeric(10, 2) NOT NULL DEFAULT 0,
+	fragile_surcharge numeric(10, 2) NOT NULL DEFAULT 0,
+	night_surcharge numeric(10, 2) NOT NULL DEFAULT 0,
+	discount numeric(10, 2) NOT NULL DEFAULT 0,
+	promo_code varchar NULL,
+	total numeric(10, 2) NOT NULL,
+	quoted_at timestamptz NOT NULL,
+	CONSTRAINT delivery_price_pk PRIMARY KEY (delivery_id),
+	CONSTRAINT delivery_price_delivery_id_fkey FOREIGN KEY (delivery_id) REFERENCES labwork.delivery(id) ON DELETE CASCADE,
+	CONSTRAINT delivery_price_tariff_id_fkey FOREIGN KEY (tariff_id) REFERENCES labwork.tariff(id),
+	CONSTRAINT delivery_price_promo_code_fkey FOREIGN KEY (promo_code) REFERENCES labwork.promo_code(code),
+	CONSTRAINT delivery_price_total_check CHECK (total >= 0)
+);
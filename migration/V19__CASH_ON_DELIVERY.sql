@@ -0,0 +1,40 @@
+ALTER TABLE labwork.delivery
+	ADD COLUMN cod_amount numeric(10, 2) NOT NULL DEFAULT 0,
+	ADD COLUMN cod_collected numeric(10, 2) NULL,
+	ADD CONSTRAINT delivery_cod_amount_check CHECK (cod_amount >= 0),
+	ADD CONSTRAINT delivery_cod_collected_check CHECK (cod_collected >= 0);
+
+CREATE TABLE labwork.cash_handover (
+	id uuid NOT NULL,
+	courier_id uuid NOT NULL,
+	manager_id uuid NOT NULL,
+	expected numeric(10, 2) NOT NULL,
+	counted numeric(10, 2) NOT NULL,
+	discrepancy numeric(10, 2) NOT NULL,
+	comment varchar NOT NULL DEFAULT '',
+	handed_over_at timestamptz NOT NULL,
+	CONSTRAINT cash_handover_pk PRIMARY KEY (id),
+	CONSTRAINT cash_handover_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id) ON DELETE CASCADE,
+	CONSTRAINT cash_handover_counted_check CHECK (counted >= 0)
+);
+
+CREATE INDEX cash_handover_courier_id_idx ON labwork.cash_handover (courier_id, handed_over_at);
+
+CREATE TABLE labwork.courier_cash_entry (
+	id uuid NOT NULL,
+	courier_id uuid NOT NULL,
+	kind varchar NOT NULL,
+	amount numeric(10, 2) NOT NULL,
+	delivery_id uuid NULL,
+	handover_id uuid NULL,
+	created_at timestamptz NOT NULL,
+	CONSTRAINT courier_cash_entry_pk PRIMARY KEY (id),
+	CONSTRAINT courier_cash_entry_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id) ON DELETE CASCADE,
+	CONSTRAINT courier_cash_entry_delivery_id_fkey FOREIGN KEY (delivery_id) REFERENCES labwork.delivery(id) ON DELETE SET NULL,
+	CONSTRAINT courier_cash_entry_handover_id_fkey FOREIGN KEY (handover_id) REFERENCES labwork.cash_handover(id) ON DELETE CASCADE,
+	CONSTRAINT courier_cash_entry_kind_check CHECK (kind IN ('collection', 'handover')),
+	CONSTRAINT courier_cash_entry_handover_check CHECK (kind <> 'handover' OR handover_id IS NOT NULL)
+);
+
+CREATE INDEX courier_cash_entry_courier_id_idx ON labwork.courier_cash_entry (courier_id, created_at);
+CREATE UNIQUE INDEX courier_cash_entry_delivery_id_key ON labwork.courier_cash_entry (delivery_id) WHERE kind = 'collection';
@@ -0,0 +1,38 @@
+CREATE TABLE labwork.courier_earning (
+	id uuid NOT NULL,
+	courier_id uuid NOT NULL,
+	delivery_id uuid NULL,
+	kind varchar NOT NULL,
+	amount numeric(10, 2) NOT NULL,
+	comment varchar NOT NULL DEFAULT '',
+	created_by uuid NULL,
+	created_at timestamptz NOT NULL,
+	CONSTRAINT courier_earning_pk PRIMARY KEY (id),
+	CONSTRAINT courier_earning_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id) ON DELETE CASCADE,
+	CONSTRAINT courier_earning_delivery_id_fkey FOREIGN KEY (delivery_id) REFERENCES labwork.delivery(id) ON DELETE SET NULL,
+	CONSTRAINT courier_earning_kind_check CHECK (kind IN ('delivery', 'on_time_bonus', 'adjustment', 'penalty')),
+	CONSTRAINT courier_earning_penalty_check CHECK (kind <> 'penalty' OR amount < 0)
+);
+
+CREATE INDEX courier_earning_courier_id_idx ON labwork.courier_earning (courier_id, created_at);
+CREATE INDEX courier_earning_created_at_idx ON labwork.courier_earning (created_at);
+CREATE UNIQUE INDEX courier_earning_delivery_id_key ON labwork.courier_earning (delivery_id, kind) WHERE delivery_id IS NOT NULL;
+
+CREATE TABLE labwork.courier_statement (
+	id uuid NOT NULL,
+	courier_id uuid NOT NULL,
+	period varchar NOT NULL,
+	period_start timestamptz NOT NULL,
+	period_end timestamptz NOT NULL,
+	deliveries int4 NOT NULL DEFAULT 0,
+	delivery_amount numeric(10, 2) NOT NULL DEFAULT 0,
+	bonus_amount numeric(10, 2) NOT NULL DEFAULT 0,
+	adjustment_amount numeric(10, 2) NOT NULL DEFAULT 0,
+	penalty_amount numeric(10, 2) NOT NULL DEFAULT 0,
+	total numeric(10, 2) NOT NULL,
+	generated_at timestamptz NOT NULL,
+	CONSTRAINT courier_statement_pk PRIMARY KEY (id),
+	CONSTRAINT courier_statement_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id) ON DELETE CASCADE,
+	CONSTRAINT courier_statement_period_key UNIQUE (courier_id, period, period_start),
+	CONSTRAINT courier_statement_period_check CHECK (period IN ('week', 'month') AND period_end > period_start)
+);
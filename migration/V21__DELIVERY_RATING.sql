@@ -0,0 +1,25 @@
+CREATE TABLE labwork.delivery_rating (
+	id uuid NOT NULL,
+	delivery_id uuid NOT NULL,
+	courier_id uuid NOT NULL,
+	score int2 NOT NULL,
+	tags varchar[] NOT NULL DEFAULT '{}',
+	comment varchar NOT NULL DEFAULT '',
+	source varchar NOT NULL,
+	rated_by uuid NULL,
+	status varchar NOT NULL DEFAULT 'published',
+	moderated_by uuid NULL,
+	moderated_at timestamptz NULL,
+	moderation_note varchar NOT NULL DEFAULT '',
+	created_at timestamptz NOT NULL,
+	CONSTRAINT delivery_rating_pk PRIMARY KEY (id),
+	CONSTRAINT delivery_rating_delivery_id_key UNIQUE (delivery_id),
+	CONSTRAINT delivery_rating_delivery_id_fkey FOREIGN KEY (delivery_id) REFERENCES labwork.delivery(id) ON DELETE CASCADE,
+	CONSTRAINT delivery_rating_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id) ON DELETE CASCADE,
+	CONSTRAINT delivery_rating_score_check CHECK (score BETWEEN 1 AND 5),
+	CONSTRAINT delivery_rating_source_check CHECK (source IN ('client', 'tracking')),
+	CONSTRAINT delivery_rating_status_check CHECK (status IN ('published', 'hidden'))
+);
+
+CREATE INDEX delivery_rating_courier_id_idx ON labwork.delivery_rating (courier_id, created_at);
+CREATE INDEX delivery_rating_score_idx ON labwork.delivery_rating (score, created_at) WHERE status = 'published';
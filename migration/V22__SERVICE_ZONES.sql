@@ -0,0 +1,29 @@
+-- boundaries are GeoJSON in WGS 84, zones are matched in the application unless PostGIS lookups are enabled
+CREATE TABLE labwork.zone (
+	id uuid NOT NULL,
+	code varchar NOT NULL,
+	name varchar NOT NULL,
+	boundary jsonb NOT NULL,
+	time_zone varchar NOT NULL DEFAULT 'UTC',
+	hours jsonb NOT NULL DEFAULT '[]',
+	active bool NOT NULL DEFAULT true,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL,
+	CONSTRAINT zone_pk PRIMARY KEY (id),
+	CONSTRAINT zone_code_key UNIQUE (code),
+	CONSTRAINT zone_boundary_check CHECK (boundary->>'type' IN ('Polygon', 'MultiPolygon'))
+);
+
+CREATE TABLE labwork.courier_zone (
+	courier_id uuid NOT NULL,
+	zone_id uuid NOT NULL,
+	CONSTRAINT courier_zone_pk PRIMARY KEY (courier_id, zone_id),
+	CONSTRAINT courier_zone_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id) ON DELETE CASCADE,
+	CONSTRAINT courier_zone_zone_id_fkey FOREIGN KEY (zone_id) REFERENCES labwork.zone(id) ON DELETE CASCADE
+);
+
+CREATE INDEX courier_zone_zone_id_idx ON labwork.courier_zone (zone_id);
+
+ALTER TABLE labwork.delivery ADD COLUMN zone_id uuid NULL;
+ALTER TABLE labwork.delivery ADD CONSTRAINT delivery_zone_id_fkey FOREIGN KEY (zone_id) REFERENCES labwork.zone(id) ON DELETE SET NULL;
+CREATE INDEX delivery_zone_id_idx ON labwork.delivery (zone_id);
@@ -0,0 +1,48 @@
+CREATE TABLE labwork.hub (
+	id uuid NOT NULL,
+	code varchar NOT NULL,
+	name varchar NOT NULL,
+	address_line1 varchar NOT NULL,
+	address_line2 varchar NOT NULL DEFAULT '',
+	city varchar NOT NULL,
+	postcode varchar NOT NULL DEFAULT '',
+	lat float8 NOT NULL,
+	lon float8 NOT NULL,
+	active bool NOT NULL DEFAULT true,
+	created_at timestamptz NOT NULL,
+	CONSTRAINT hub_pk PRIMARY KEY (id),
+	CONSTRAINT hub_code_key UNIQUE (code)
+);
+
+CREATE TABLE labwork.hub_staff (
+	user_id uuid NOT NULL,
+	hub_id uuid NOT NULL,
+	CONSTRAINT hub_staff_pk PRIMARY KEY (user_id),
+	CONSTRAINT hub_staff_hub_id_fkey FOREIGN KEY (hub_id) REFERENCES labwork.hub(id) ON DELETE CASCADE
+);
+
+ALTER TABLE labwork.delivery ADD COLUMN legs int4 NOT NULL DEFAULT 0;
+
+CREATE TABLE labwork.delivery_leg (
+	id uuid NOT NULL,
+	delivery_id uuid NOT NULL,
+	position int4 NOT NULL,
+	from_hub_id uuid NULL,
+	to_hub_id uuid NULL,
+	courier_id uuid NULL,
+	status varchar NOT NULL DEFAULT 'created',
+	created_at timestamptz NOT NULL,
+	picked_up_at timestamptz NULL,
+	delivered_at timestamptz NULL,
+	version int4 NOT NULL DEFAULT 0,
+	CONSTRAINT delivery_leg_pk PRIMARY KEY (id),
+	CONSTRAINT delivery_leg_position_key UNIQUE (delivery_id, position),
+	CONSTRAINT delivery_leg_delivery_id_fkey FOREIGN KEY (delivery_id) REFERENCES labwork.delivery(id) ON DELETE CASCADE,
+	CONSTRAINT delivery_leg_from_hub_id_fkey FOREIGN KEY (from_hub_id) REFERENCES labwork.hub(id),
+	CONSTRAINT delivery_leg_to_hub_id_fkey FOREIGN KEY (to_hub_id) REFERENCES labwork.hub(id),
+	CONSTRAINT delivery_leg_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id) ON DELETE SET NULL,
+	CONSTRAINT delivery_leg_status_check CHECK (status IN ('created', 'picked_up', 'delivered', 'cancelled'))
+);
+
+CREATE INDEX delivery_leg_courier_id_idx ON labwork.delivery_leg (courier_id) WHERE status IN ('created', 'picked_up');
+CREATE INDEX delivery_leg_from_hub_id_idx ON labwork.delivery_leg (from_hub_id) WHERE status = 'created';
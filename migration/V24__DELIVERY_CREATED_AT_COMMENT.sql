@@ -0,0 +1,4 @@
+-- V3 added created_at with DEFAULT now(). The old schema kept no creation time, so deliveries that existed
+-- before V3 carry the moment V3 ran rather than when they were created, and it may be later than their window.
+-- V3 is left untouched to keep its checksum; the caveat is recorded on the column instead.
+COMMENT ON COLUMN labwork.delivery.created_at IS 'When the delivery was created. Deliveries that existed before V3 carry the time V3 ran, the old schema kept no creation time.';
@@ -0,0 +1,5 @@
+-- V13 gave every courier the bicycle vehicle by default. The vehicle is now unknown until a manager sets it.
+-- Existing values are kept because a defaulted bicycle cannot be told apart from one a manager chose.
+ALTER TABLE labwork.courier
+	ALTER COLUMN vehicle_type DROP DEFAULT,
+	ALTER COLUMN vehicle_type DROP NOT NULL;
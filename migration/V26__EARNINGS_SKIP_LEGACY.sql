@@ -0,0 +1,8 @@
+-- Deliveries completed before earnings were introduced were paid outside the ledger and are never accrued.
+-- They are the delivered ones without earnings that precede the first accrual, all of them when nothing was accrued yet.
+ALTER TABLE labwork.delivery ADD COLUMN accrue_earnings bool NOT NULL DEFAULT true;
+
+UPDATE labwork.delivery d SET accrue_earnings = false
+WHERE d.delivery_status = 'delivered'
+	AND NOT EXISTS (SELECT 1 FROM labwork.courier_earning e WHERE e.delivery_id = d.id)
+	AND (d.delivered_at IS NULL OR d.delivered_at < COALESCE((SELECT MIN(e.created_at) FROM labwork.courier_earning e WHERE e.kind = 'delivery'), 'infinity'));
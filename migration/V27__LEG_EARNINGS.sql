@@ -0,0 +1,6 @@
+-- couriers are paid per delivered leg of a multi-leg delivery, single-leg deliveries keep one earning per kind
+ALTER TABLE labwork.courier_earning ADD COLUMN leg_id uuid NULL;
+ALTER TABLE labwork.courier_earning ADD CONSTRAINT courier_earning_leg_id_fkey FOREIGN KEY (leg_id) REFERENCES labwork.delivery_leg(id) ON DELETE SET NULL;
+DROP INDEX labwork.courier_earning_delivery_id_key;
+CREATE UNIQUE INDEX courier_earning_delivery_id_key ON labwork.courier_earning (delivery_id, kind) WHERE delivery_id IS NOT NULL AND leg_id IS NULL;
+CREATE UNIQUE INDEX courier_earning_leg_id_key ON labwork.courier_earning (leg_id, kind) WHERE leg_id IS NOT NULL;
@@ -0,0 +1,48 @@
+-- delivery_date was a free-form varchar, it is replaced with timestamptz columns.
+-- Values without an explicit offset are interpreted as UTC, dates as DD.MM.YYYY when ambiguous.
+SET TIME ZONE 'UTC';
+SET DateStyle = 'ISO, DMY';
+
+ALTER TABLE labwork.delivery
+	ADD COLUMN created_at timestamptz NOT NULL DEFAULT now(),
+	ADD COLUMN window_start timestamptz NULL,
+	ADD COLUMN window_end timestamptz NULL,
+	ADD COLUMN picked_up_at timestamptz NULL,
+	ADD COLUMN delivered_at timestamptz NULL;
+
+-- Rows whose delivery_date could not be parsed keep NULL windows and are listed here
+CREATE TABLE labwork.delivery_date_migration_error (
+	delivery_id uuid NOT NULL,
+	delivery_date varchar NULL,
+	error_message varchar NOT NULL,
+	reported_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT delivery_date_migration_error_pk PRIMARY KEY (delivery_id)
+);
+
+DO $$
+DECLARE
+	r record;
+	parsed timestamptz;
+	failed int := 0;
+BEGIN
+	FOR r IN SELECT id, delivery_date FROM labwork.delivery LOOP
+		BEGIN
+			parsed := trim(r.delivery_date)::timestamptz;
+			-- a bare date promises the whole day
+			UPDATE labwork.delivery
+			SET window_start = parsed,
+				window_end = CASE WHEN trim(r.delivery_date) ~ '^[0-9.\-/]+$' THEN parsed + interval '1 day' ELSE parsed END
+			WHERE id = r.id;
+		EXCEPTION WHEN others THEN
+			failed := failed + 1;
+			INSERT INTO labwork.delivery_date_migration_error (delivery_id, delivery_date, error_message)
+			VALUES (r.id, r.delivery_date, SQLERRM);
+			RAISE WARNING 'delivery %: cannot parse delivery_date "%": %', r.id, r.delivery_date, SQLERRM;
+		END;
+	END LOOP;
+	RAISE NOTICE '% delivery rows failed to parse, see labwork.delivery_date_migration_error', failed;
+END $$;
+
+ALTER TABLE labwork.delivery DROP COLUMN delivery_date;
+ALTER TABLE labwork.delivery ADD CONSTRAINT delivery_window_check CHECK (window_end >= window_start);
+CREATE INDEX delivery_window_start_idx ON labwork.delivery (window_start);
@@ -0,0 +1,20 @@
+ALTER TABLE labwork.delivery
+	ADD COLUMN pickup_address_line1 varchar NOT NULL DEFAULT '',
+	ADD COLUMN pickup_address_line2 varchar NOT NULL DEFAULT '',
+	ADD COLUMN pickup_city varchar NOT NULL DEFAULT '',
+	ADD COLUMN pickup_postcode varchar NOT NULL DEFAULT '',
+	ADD COLUMN pickup_lat double precision NULL,
+	ADD COLUMN pickup_lon double precision NULL,
+	ADD COLUMN dropoff_address_line1 varchar NOT NULL DEFAULT '',
+	ADD COLUMN dropoff_address_line2 varchar NOT NULL DEFAULT '',
+	ADD COLUMN dropoff_city varchar NOT NULL DEFAULT '',
+	ADD COLUMN dropoff_postcode varchar NOT NULL DEFAULT '',
+	ADD COLUMN dropoff_lat double precision NULL,
+	ADD COLUMN dropoff_lon double precision NULL,
+	ADD COLUMN recipient_name varchar NOT NULL DEFAULT '',
+	ADD COLUMN recipient_phone varchar NOT NULL DEFAULT '',
+	ADD COLUMN access_notes varchar NOT NULL DEFAULT '';
+
+ALTER TABLE labwork.delivery
+	ADD CONSTRAINT delivery_pickup_coordinates_check CHECK (pickup_lat BETWEEN -90 AND 90 AND pickup_lon BETWEEN -180 AND 180),
+	ADD CONSTRAINT delivery_dropoff_coordinates_check CHECK (dropoff_lat BETWEEN -90 AND 90 AND dropoff_lon BETWEEN -180 AND 180);
@@ -0,0 +1,5 @@
+ALTER TABLE labwork.delivery
+	ADD COLUMN client_id uuid NULL,
+	ADD CONSTRAINT delivery_client_id_fkey FOREIGN KEY (client_id) REFERENCES labwork."user"(id) ON DELETE SET NULL;
+
+CREATE INDEX delivery_client_id_idx ON labwork.delivery (client_id, created_at DESC);
@@ -0,0 +1,11 @@
+-- Deliveries created before this migration get a code through /delivery/reissue_tracking_code
+CREATE TABLE labwork.tracking_code (
+	code varchar(16) NOT NULL,
+	delivery_id uuid NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	revoked_at timestamptz NULL,
+	CONSTRAINT tracking_code_pk PRIMARY KEY (code),
+	CONSTRAINT tracking_code_delivery_id_fkey FOREIGN KEY (delivery_id) REFERENCES labwork.delivery(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX tracking_code_active_idx ON labwork.tracking_code (delivery_id) WHERE revoked_at IS NULL;
@@ -0,0 +1,5 @@
+-- Statuses a manager can set: active, suspended, off_shift
+UPDATE labwork.courier SET status = 'off_shift' WHERE status IS NULL OR status NOT IN ('active', 'suspended', 'off_shift');
+ALTER TABLE labwork.courier ALTER COLUMN status SET DEFAULT 'off_shift';
+
+CREATE INDEX delivery_courier_id_idx ON labwork.delivery (courier_id);
@@ -0,0 +1,18 @@
+CREATE TABLE labwork.delivery_offer (
+	id uuid NOT NULL,
+	delivery_id uuid NOT NULL,
+	courier_id uuid NOT NULL,
+	status varchar NOT NULL,
+	score double precision NOT NULL,
+	offered_at timestamptz NOT NULL,
+	expires_at timestamptz NOT NULL,
+	responded_at timestamptz NULL,
+	CONSTRAINT delivery_offer_pk PRIMARY KEY (id),
+	CONSTRAINT delivery_offer_delivery_id_fkey FOREIGN KEY (delivery_id) REFERENCES labwork.delivery(id) ON DELETE CASCADE,
+	CONSTRAINT delivery_offer_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id) ON DELETE CASCADE,
+	CONSTRAINT delivery_offer_status_check CHECK (status IN ('pending', 'accepted', 'declined', 'expired'))
+);
+
+-- a delivery is offered to one courier at a time
+CREATE UNIQUE INDEX delivery_offer_pending_idx ON labwork.delivery_offer (delivery_id) WHERE status = 'pending';
+CREATE INDEX delivery_offer_expires_at_idx ON labwork.delivery_offer (expires_at) WHERE status = 'pending';
@@ -0,0 +1,21 @@
+ALTER TABLE labwork.courier
+	ADD COLUMN max_active_deliveries int4 NOT NULL DEFAULT 3,
+	ADD COLUMN max_weight_kg double precision NULL,
+	ADD CONSTRAINT courier_max_active_deliveries_check CHECK (max_active_deliveries > 0),
+	ADD CONSTRAINT courier_max_weight_kg_check CHECK (max_weight_kg > 0);
+
+ALTER TABLE labwork.delivery
+	ADD COLUMN weight_kg double precision NOT NULL DEFAULT 0,
+	ADD CONSTRAINT delivery_weight_kg_check CHECK (weight_kg >= 0);
+
+CREATE TABLE labwork.courier_shift (
+	id uuid NOT NULL,
+	courier_id uuid NOT NULL,
+	starts_at timestamptz NOT NULL,
+	ends_at timestamptz NOT NULL,
+	CONSTRAINT courier_shift_pk PRIMARY KEY (id),
+	CONSTRAINT courier_shift_courier_id_fkey FOREIGN KEY (courier_id) REFERENCES labwork.courier(id) ON DELETE CASCADE,
+	CONSTRAINT courier_shift_period_check CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX courier_shift_courier_id_idx ON labwork.courier_shift (courier_id, starts_at);